@@ -0,0 +1,222 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package audit keeps a tamper-evident log of all generation requests, e.g.
+// for AI usage compliance. Every entry contains the hash of the previous one,
+// so changing, inserting or removing entries breaks the chain, see Verify.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/filelock"
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+// Entry is one generation request or its outcome.
+type Entry struct {
+	Seq  int       `json:"seq"`
+	Time time.Time `json:"time"`
+	// Request is set on the entry written before the provider is called. The
+	// outcome follows in an entry without it, a request without an outcome
+	// was interrupted and may still have been billed.
+	Request  bool              `json:"request,omitempty"`
+	User     string            `json:"user"`
+	Host     string            `json:"host"`
+	Model    string            `json:"model"`
+	Prompt   string            `json:"prompt"`
+	Settings map[string]string `json:"settings,omitempty"`
+	// Files are the saved images and videos, or their upload locations if
+	// they were not kept locally.
+	Files []string `json:"files,omitempty"`
+	// Filtered is the number of images removed by the safety filter.
+	Filtered int    `json:"filtered,omitempty"`
+	Error    string `json:"error,omitempty"`
+	// Prev is the hash of the previous entry, empty for the first.
+	Prev string `json:"prev"`
+	// Hash is the SHA-256 of the entry encoded without it.
+	Hash string `json:"hash"`
+}
+
+// ErrBroken is returned by Verify if the chain was tampered with.
+var ErrBroken = errors.New("audit log chain is broken")
+
+var mu sync.Mutex
+
+// FilePath returns the location of the audit log.
+func FilePath() (string, error) {
+	dataDir, err := providers.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "audit.jsonl"), nil
+}
+
+// Append chains the entry to the log. Seq, Prev and Hash are set by Append.
+func Append(e Entry) error {
+	auditFile, err := FilePath()
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(auditFile), 0700); err != nil {
+		return fmt.Errorf("failed to create data dir: %w", err)
+	}
+	unlock, err := filelock.Lock(auditFile + ".lock")
+	if err != nil {
+		return fmt.Errorf("failed to lock audit log: %w", err)
+	}
+	defer unlock()
+
+	f, err := os.OpenFile(auditFile, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+	last, err := lastEntry(f)
+	if err != nil {
+		return err
+	}
+	if last != nil {
+		e.Seq = last.Seq + 1
+		e.Prev = last.Hash
+	} else {
+		e.Seq = 1
+		e.Prev = ""
+	}
+	if e.Hash, err = hash(e); err != nil {
+		return err
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return f.Sync()
+}
+
+// Read returns all entries, oldest first.
+func Read() ([]Entry, error) {
+	auditFile, err := FilePath()
+	if err != nil {
+		return nil, err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	f, err := os.Open(auditFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var e Entry
+		// unlike the history, a malformed line is an error, it may have
+		// been tampered with
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%w: line %d is malformed: %v", ErrBroken, line, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// Verify checks that the entries form an unbroken chain from the first entry
+// of the log.
+func Verify(entries []Entry) error {
+	prev := ""
+	for i, e := range entries {
+		if e.Seq != i+1 {
+			return fmt.Errorf("%w: entry %d has sequence number %d", ErrBroken, i+1, e.Seq)
+		}
+		if e.Prev != prev {
+			return fmt.Errorf("%w: entry %d doesn't follow the previous entry", ErrBroken, e.Seq)
+		}
+		h, err := hash(e)
+		if err != nil {
+			return err
+		}
+		if h != e.Hash {
+			return fmt.Errorf("%w: entry %d was modified", ErrBroken, e.Seq)
+		}
+		prev = e.Hash
+	}
+	return nil
+}
+
+func hash(e Entry) (string, error) {
+	e.Hash = ""
+	// maps are encoded with sorted keys, so the encoding is stable
+	b, err := json.Marshal(e)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// lastEntry reads the last entry of the log from its end, nil if it's empty.
+func lastEntry(f *os.File) (*Entry, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	size := fi.Size()
+	if size == 0 {
+		return nil, nil
+	}
+	// entries are small, a long prompt and many settings fit in 1 MiB
+	chunk := min(size, 1<<20)
+	b := make([]byte, chunk)
+	if _, err := f.ReadAt(b, size-chunk); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	b = bytes.TrimRight(b, "\n")
+	if i := bytes.LastIndexByte(b, '\n'); i >= 0 {
+		b = b[i+1:]
+	} else if chunk < size {
+		return nil, fmt.Errorf("%w: the last entry is too long", ErrBroken)
+	}
+	var e Entry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, fmt.Errorf("%w: the last entry is malformed: %v", ErrBroken, err)
+	}
+	return &e, nil
+}
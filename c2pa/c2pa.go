@@ -0,0 +1,539 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package c2pa
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+// Report is the provenance of a file.
+type Report struct {
+	// Manifests are the manifests in the file, the active one, which
+	// describes the file itself, is last. The others describe ingredients.
+	Manifests []Manifest `json:"manifests"`
+	// ContentHash is "match" if the file wasn't changed after signing,
+	// "mismatch" if it was and "unchecked" if the manifest has no data hash
+	// of a supported kind.
+	ContentHash string `json:"content_hash"`
+}
+
+// Active returns the manifest that describes the file.
+func (r Report) Active() Manifest {
+	return r.Manifests[len(r.Manifests)-1]
+}
+
+// AIGenerated reports if an action of the active manifest declares the
+// content as generated or edited by a trained model.
+func (r Report) AIGenerated() bool {
+	for _, a := range r.Active().Actions {
+		if strings.HasSuffix(a.DigitalSourceType, "trainedAlgorithmicMedia") {
+			return true
+		}
+	}
+	return false
+}
+
+// Manifest is one C2PA manifest.
+type Manifest struct {
+	Label          string    `json:"label"`
+	ClaimGenerator string    `json:"claim_generator,omitempty"`
+	Title          string    `json:"title,omitempty"`
+	Actions        []Action  `json:"actions,omitempty"`
+	Ingredients    []string  `json:"ingredients,omitempty"`
+	Assertions     []string  `json:"assertions"`
+	Signature      Signature `json:"signature"`
+}
+
+// Action is an entry of the actions assertion, e.g. "c2pa.created".
+type Action struct {
+	Action            string `json:"action"`
+	DigitalSourceType string `json:"digital_source_type,omitempty"`
+	SoftwareAgent     string `json:"software_agent,omitempty"`
+	When              string `json:"when,omitempty"`
+}
+
+// Signature is the signature of a claim.
+type Signature struct {
+	// Valid is set if the signature matches the claim and the certificate.
+	Valid bool `json:"valid"`
+	// Error is why the signature couldn't be checked or is invalid.
+	Error  string `json:"error,omitempty"`
+	Signer string `json:"signer,omitempty"`
+	Issuer string `json:"issuer,omitempty"`
+	// Trusted is set if the certificate chains to a root of the system's
+	// trust store. C2PA signers often use their own roots, so an untrusted
+	// signature isn't necessarily forged.
+	Trusted   bool      `json:"trusted"`
+	NotBefore time.Time `json:"not_before,omitzero"`
+	NotAfter  time.Time `json:"not_after,omitzero"`
+}
+
+// Verify reads the content credentials of a PNG or JPEG file and checks
+// their signatures and the data hash. It returns ErrNoManifest if there are
+// none.
+func Verify(path string) (Report, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to read file: %w", err)
+	}
+	store, err := extract(b)
+	if err != nil {
+		return Report{}, err
+	}
+	boxes, err := parseBoxes(store, 0)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to parse manifest store: %w", err)
+	}
+	if len(boxes) == 0 || boxes[0].label != "c2pa" {
+		return Report{}, errors.New("invalid manifest store")
+	}
+	var report Report
+	var active box
+	for _, m := range boxes[0].children {
+		if m.typ != "jumb" {
+			continue
+		}
+		report.Manifests = append(report.Manifests, readManifest(m))
+		active = m
+	}
+	if len(report.Manifests) == 0 {
+		return Report{}, ErrNoManifest
+	}
+	report.ContentHash = checkDataHash(b, active)
+	return report, nil
+}
+
+func readManifest(m box) Manifest {
+	manifest := Manifest{Label: m.label}
+	if assertions, ok := m.child("c2pa.assertions"); ok {
+		for _, a := range assertions.children {
+			if a.typ != "jumb" {
+				continue
+			}
+			manifest.Assertions = append(manifest.Assertions, a.label)
+			label := baseLabel(a.label)
+			switch {
+			case label == "c2pa.actions" || label == "c2pa.actions.v2":
+				manifest.Actions = append(manifest.Actions, readActions(a)...)
+			case strings.HasPrefix(label, "c2pa.ingredient"):
+				if v, ok := cborContent(a); ok {
+					manifest.Ingredients = append(manifest.Ingredients, cborString(cborMap(v, "dc:title")))
+				}
+			}
+		}
+	}
+
+	claimBox, ok := m.child("c2pa.claim.v2")
+	if !ok {
+		claimBox, ok = m.child("c2pa.claim")
+	}
+	if !ok {
+		manifest.Signature.Error = "manifest has no claim"
+		return manifest
+	}
+	claimBytes, _ := claimBox.content("cbor")
+	claim, err := decodeCBOR(claimBytes)
+	if err != nil {
+		manifest.Signature.Error = fmt.Sprintf("invalid claim: %v", err)
+		return manifest
+	}
+	manifest.Title = cborString(cborMap(claim, "dc:title"))
+	manifest.ClaimGenerator = cborString(cborMap(claim, "claim_generator"))
+	if manifest.ClaimGenerator == "" {
+		manifest.ClaimGenerator = generatorInfo(cborMap(claim, "claim_generator_info"))
+	}
+
+	sigBox, ok := m.child("c2pa.signature")
+	if !ok {
+		manifest.Signature.Error = "manifest is not signed"
+		return manifest
+	}
+	sigBytes, _ := sigBox.content("cbor")
+	manifest.Signature = verifySignature(sigBytes, claimBytes)
+	if manifest.Signature.Valid {
+		// the signature only covers the assertions through their hashes in
+		// the claim
+		if err := checkAssertionHashes(claim, m); err != nil {
+			manifest.Signature.Valid = false
+			manifest.Signature.Error = err.Error()
+		}
+	}
+	return manifest
+}
+
+// checkAssertionHashes compares the hashes of the assertions referenced by
+// the claim with the assertion boxes of the manifest. Assertions that aren't
+// referenced are not covered by the signature and fail the check too.
+func checkAssertionHashes(claim any, m box) error {
+	var refs []any
+	for _, key := range []string{"assertions", "created_assertions", "gathered_assertions"} {
+		list, _ := cborMap(claim, key).([]any)
+		refs = append(refs, list...)
+	}
+	assertions, _ := m.child("c2pa.assertions")
+	referenced := make(map[string]bool)
+	for _, ref := range refs {
+		url := cborString(cborMap(ref, "url"))
+		label, ok := assertionLabel(url, m.label)
+		if !ok {
+			return fmt.Errorf("claim references an assertion outside the manifest: %q", url)
+		}
+		a, ok := assertions.child(label)
+		if !ok {
+			return fmt.Errorf("assertion %s is missing", label)
+		}
+		alg := cborString(cborMap(ref, "alg"))
+		if alg == "" {
+			alg = cborString(cborMap(claim, "alg"))
+		}
+		hasher := newHash(alg)
+		if hasher == nil {
+			return fmt.Errorf("unsupported hash algorithm %q of assertion %s", alg, label)
+		}
+		// the hash covers the contents of the superbox without its header
+		hasher.Write(a.payload)
+		want, _ := cborMap(ref, "hash").([]byte)
+		if string(hasher.Sum(nil)) != string(want) {
+			return fmt.Errorf("assertion %s was changed after signing", label)
+		}
+		referenced[label] = true
+	}
+	for _, a := range assertions.children {
+		if a.typ == "jumb" && !referenced[a.label] {
+			return fmt.Errorf("assertion %s is not referenced by the claim", a.label)
+		}
+	}
+	return nil
+}
+
+// assertionLabel returns the label of the assertion a JUMBF URI like
+// "self#jumbf=c2pa.assertions/c2pa.actions" or
+// "self#jumbf=/c2pa/<manifest>/c2pa.assertions/c2pa.actions" points to.
+func assertionLabel(url string, manifestLabel string) (string, bool) {
+	_, path, ok := strings.Cut(url, "jumbf=")
+	if !ok {
+		return "", false
+	}
+	parts := strings.Split(path, "/")
+	if strings.HasPrefix(path, "/") {
+		if len(parts) != 5 || parts[1] != "c2pa" || parts[2] != manifestLabel {
+			return "", false
+		}
+		parts = parts[3:]
+	}
+	if len(parts) != 2 || parts[0] != "c2pa.assertions" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// newHash returns the hash of a C2PA algorithm name, SHA-256 if empty, or nil
+// if it is not supported.
+func newHash(alg string) hash.Hash {
+	switch alg {
+	case "sha256", "":
+		return sha256.New()
+	case "sha384":
+		return sha512.New384()
+	case "sha512":
+		return sha512.New()
+	}
+	return nil
+}
+
+// baseLabel removes the instance suffix of a label like "c2pa.ingredient__1".
+func baseLabel(label string) string {
+	base, _, _ := strings.Cut(label, "__")
+	return base
+}
+
+func cborContent(b box) (any, bool) {
+	raw, ok := b.content("cbor")
+	if !ok {
+		return nil, false
+	}
+	v, err := decodeCBOR(raw)
+	return v, err == nil
+}
+
+func readActions(b box) []Action {
+	v, ok := cborContent(b)
+	if !ok {
+		return nil
+	}
+	list, _ := cborMap(v, "actions").([]any)
+	var actions []Action
+	for _, a := range list {
+		action := Action{
+			Action:            cborString(cborMap(a, "action")),
+			DigitalSourceType: cborString(cborMap(a, "digitalSourceType")),
+			When:              cborString(cborMap(a, "when")),
+		}
+		switch agent := cborMap(a, "softwareAgent").(type) {
+		case string:
+			action.SoftwareAgent = agent
+		case map[any]any:
+			action.SoftwareAgent = generatorInfo(agent)
+		}
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+// generatorInfo formats a claim generator info map or a list of them.
+func generatorInfo(v any) string {
+	if list, ok := v.([]any); ok {
+		var names []string
+		for _, info := range list {
+			if name := generatorInfo(info); name != "" {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+	name := cborString(cborMap(v, "name"))
+	if version := cborString(cborMap(v, "version")); version != "" {
+		name += " " + version
+	}
+	return name
+}
+
+// COSE header labels and algorithms.
+const (
+	coseAlg     = 1
+	coseX5Chain = 33
+
+	coseES256 = -7
+	coseES384 = -35
+	coseES512 = -36
+	coseEdDSA = -8
+	cosePS256 = -37
+	cosePS384 = -38
+	cosePS512 = -39
+)
+
+// verifySignature checks the COSE_Sign1 signature of a claim, whose payload
+// is detached.
+func verifySignature(sigBytes, claim []byte) Signature {
+	var sig Signature
+	v, err := decodeCBOR(sigBytes)
+	if err != nil {
+		sig.Error = fmt.Sprintf("invalid signature: %v", err)
+		return sig
+	}
+	if tag, ok := v.(cborTag); ok {
+		v = tag.content
+	}
+	parts, ok := v.([]any)
+	if !ok || len(parts) != 4 {
+		sig.Error = "invalid signature structure"
+		return sig
+	}
+	protectedBytes, _ := parts[0].([]byte)
+	signature, _ := parts[3].([]byte)
+	protected, err := decodeCBOR(protectedBytes)
+	if err != nil {
+		sig.Error = fmt.Sprintf("invalid signature header: %v", err)
+		return sig
+	}
+	header, _ := protected.(map[any]any)
+	unprotected, _ := parts[1].(map[any]any)
+
+	alg, _ := header[int64(coseAlg)].(int64)
+	chain := certChain(header[int64(coseX5Chain)])
+	if chain == nil {
+		chain = certChain(unprotected[int64(coseX5Chain)])
+	}
+	if chain == nil {
+		// older manifests use the string label
+		chain = certChain(unprotected["x5chain"])
+	}
+	if len(chain) == 0 {
+		sig.Error = "signature has no certificate"
+		return sig
+	}
+	cert, err := x509.ParseCertificate(chain[0])
+	if err != nil {
+		sig.Error = fmt.Sprintf("invalid certificate: %v", err)
+		return sig
+	}
+	sig.Signer = certName(cert.Subject.CommonName, cert.Subject.Organization)
+	sig.Issuer = certName(cert.Issuer.CommonName, cert.Issuer.Organization)
+	sig.NotBefore = cert.NotBefore
+	sig.NotAfter = cert.NotAfter
+
+	intermediates := x509.NewCertPool()
+	for _, der := range chain[1:] {
+		if c, err := x509.ParseCertificate(der); err == nil {
+			intermediates.AddCert(c)
+		}
+	}
+	// the signing time isn't known without checking the timestamp, the
+	// validity is checked at the start of the certificate instead
+	_, err = cert.Verify(x509.VerifyOptions{
+		Intermediates: intermediates,
+		CurrentTime:   cert.NotBefore.Add(time.Second),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	sig.Trusted = err == nil
+
+	// Sig_structure = ["Signature1", protected, external_aad, payload]
+	var msg []byte
+	msg = appendCBORHeader(msg, 4, 4)
+	msg = appendCBORHeader(msg, 3, uint64(len("Signature1")))
+	msg = append(msg, "Signature1"...)
+	msg = appendCBORHeader(msg, 2, uint64(len(protectedBytes)))
+	msg = append(msg, protectedBytes...)
+	msg = appendCBORHeader(msg, 2, 0)
+	msg = appendCBORHeader(msg, 2, uint64(len(claim)))
+	msg = append(msg, claim...)
+
+	if err := verifyCOSE(alg, cert.PublicKey, msg, signature); err != nil {
+		sig.Error = err.Error()
+		return sig
+	}
+	sig.Valid = true
+	return sig
+}
+
+func verifyCOSE(alg int64, key crypto.PublicKey, msg, signature []byte) error {
+	var h crypto.Hash
+	switch alg {
+	case coseES256, cosePS256:
+		h = crypto.SHA256
+	case coseES384, cosePS384:
+		h = crypto.SHA384
+	case coseES512, cosePS512:
+		h = crypto.SHA512
+	case coseEdDSA:
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok || !ed25519.Verify(pub, msg, signature) {
+			return errors.New("signature doesn't match")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported signature algorithm %d", alg)
+	}
+	hasher := h.New()
+	hasher.Write(msg)
+	digest := hasher.Sum(nil)
+
+	switch pub := key.(type) {
+	case *ecdsa.PublicKey:
+		if alg != coseES256 && alg != coseES384 && alg != coseES512 {
+			return errors.New("signature algorithm doesn't match the key")
+		}
+		// COSE uses the concatenated r and s instead of ASN.1
+		half := len(signature) / 2
+		r := new(big.Int).SetBytes(signature[:half])
+		s := new(big.Int).SetBytes(signature[half:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return errors.New("signature doesn't match")
+		}
+	case *rsa.PublicKey:
+		if alg != cosePS256 && alg != cosePS384 && alg != cosePS512 {
+			return errors.New("signature algorithm doesn't match the key")
+		}
+		if err := rsa.VerifyPSS(pub, h, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}); err != nil {
+			return errors.New("signature doesn't match")
+		}
+	default:
+		return errors.New("unsupported certificate key")
+	}
+	return nil
+}
+
+func certChain(v any) [][]byte {
+	switch c := v.(type) {
+	case []byte:
+		return [][]byte{c}
+	case []any:
+		var chain [][]byte
+		for _, der := range c {
+			if b, ok := der.([]byte); ok {
+				chain = append(chain, b)
+			}
+		}
+		return chain
+	}
+	return nil
+}
+
+func certName(commonName string, organization []string) string {
+	if len(organization) > 0 && organization[0] != commonName {
+		if commonName == "" {
+			return organization[0]
+		}
+		return commonName + " (" + organization[0] + ")"
+	}
+	return commonName
+}
+
+// checkDataHash compares the hard binding of the manifest, the hash of the
+// file without the manifest, with the file.
+func checkDataHash(file []byte, manifest box) string {
+	assertions, ok := manifest.child("c2pa.assertions")
+	if !ok {
+		return "unchecked"
+	}
+	for _, a := range assertions.children {
+		if baseLabel(a.label) != "c2pa.hash.data" {
+			continue
+		}
+		v, ok := cborContent(a)
+		if !ok {
+			return "unchecked"
+		}
+		want, _ := cborMap(v, "hash").([]byte)
+		hasher := newHash(cborString(cborMap(v, "alg")))
+		if hasher == nil {
+			return "unchecked"
+		}
+		pos := 0
+		exclusions, _ := cborMap(v, "exclusions").([]any)
+		for _, e := range exclusions {
+			start, _ := cborMap(e, "start").(int64)
+			length, _ := cborMap(e, "length").(int64)
+			if start < int64(pos) || start+length > int64(len(file)) || length < 0 {
+				return "mismatch"
+			}
+			hasher.Write(file[pos:start])
+			pos = int(start + length)
+		}
+		hasher.Write(file[pos:])
+		if string(hasher.Sum(nil)) == string(want) {
+			return "match"
+		}
+		return "mismatch"
+	}
+	return "unchecked"
+}
@@ -0,0 +1,275 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package c2pa
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// cborTag is a tagged CBOR value.
+type cborTag struct {
+	number  uint64
+	content any
+}
+
+// cborUndefined is the CBOR undefined value.
+type cborUndefined struct{}
+
+// maxCBORDepth limits the nesting of arrays and maps.
+const maxCBORDepth = 64
+
+var errTruncatedCBOR = errors.New("truncated CBOR")
+
+// decodeCBOR decodes one CBOR value. Integers become int64 or uint64 if
+// too large, maps become map[any]any.
+func decodeCBOR(b []byte) (any, error) {
+	v, _, err := decodeCBORValue(b, 0)
+	return v, err
+}
+
+func decodeCBORValue(b []byte, depth int) (any, []byte, error) {
+	if depth > maxCBORDepth {
+		return nil, nil, errors.New("CBOR nested too deep")
+	}
+	if len(b) == 0 {
+		return nil, nil, errTruncatedCBOR
+	}
+	major, info := b[0]>>5, b[0]&0x1f
+	b = b[1:]
+
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, b, nil
+		case 21:
+			return true, b, nil
+		case 22:
+			return nil, b, nil
+		case 23:
+			return cborUndefined{}, b, nil
+		case 25:
+			if len(b) < 2 {
+				return nil, nil, errTruncatedCBOR
+			}
+			return halfToFloat(binary.BigEndian.Uint16(b)), b[2:], nil
+		case 26:
+			if len(b) < 4 {
+				return nil, nil, errTruncatedCBOR
+			}
+			return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), b[4:], nil
+		case 27:
+			if len(b) < 8 {
+				return nil, nil, errTruncatedCBOR
+			}
+			return math.Float64frombits(binary.BigEndian.Uint64(b)), b[8:], nil
+		}
+		if info < 20 {
+			return uint64(info), b, nil
+		}
+		return nil, nil, fmt.Errorf("unsupported CBOR simple value %d", info)
+	}
+
+	indefinite := info == 31
+	var n uint64
+	if !indefinite {
+		var err error
+		if n, b, err = cborArgument(info, b); err != nil {
+			return nil, nil, err
+		}
+	} else if major < 2 || major == 6 {
+		return nil, nil, errors.New("invalid indefinite CBOR length")
+	}
+
+	switch major {
+	case 0:
+		if n > math.MaxInt64 {
+			return n, b, nil
+		}
+		return int64(n), b, nil
+	case 1:
+		if n > math.MaxInt64 {
+			return nil, nil, errors.New("CBOR integer out of range")
+		}
+		return -1 - int64(n), b, nil
+	case 2, 3:
+		var s []byte
+		if indefinite {
+			for {
+				if len(b) == 0 {
+					return nil, nil, errTruncatedCBOR
+				}
+				if b[0] == 0xff {
+					b = b[1:]
+					break
+				}
+				var chunk any
+				var err error
+				if chunk, b, err = decodeCBORValue(b, depth+1); err != nil {
+					return nil, nil, err
+				}
+				switch c := chunk.(type) {
+				case []byte:
+					s = append(s, c...)
+				case string:
+					s = append(s, c...)
+				default:
+					return nil, nil, errors.New("invalid CBOR string chunk")
+				}
+			}
+		} else {
+			if n > uint64(len(b)) {
+				return nil, nil, errTruncatedCBOR
+			}
+			s = b[:n]
+			b = b[n:]
+		}
+		if major == 3 {
+			return string(s), b, nil
+		}
+		return s, b, nil
+	case 4:
+		var arr []any
+		for i := uint64(0); indefinite || i < n; i++ {
+			if indefinite && len(b) > 0 && b[0] == 0xff {
+				b = b[1:]
+				break
+			}
+			// every element takes at least one byte
+			if !indefinite && n > uint64(len(b)) {
+				return nil, nil, errTruncatedCBOR
+			}
+			var v any
+			var err error
+			if v, b, err = decodeCBORValue(b, depth+1); err != nil {
+				return nil, nil, err
+			}
+			arr = append(arr, v)
+		}
+		return arr, b, nil
+	case 5:
+		m := make(map[any]any)
+		for i := uint64(0); indefinite || i < n; i++ {
+			if indefinite && len(b) > 0 && b[0] == 0xff {
+				b = b[1:]
+				break
+			}
+			if !indefinite && n > uint64(len(b)) {
+				return nil, nil, errTruncatedCBOR
+			}
+			var k, v any
+			var err error
+			if k, b, err = decodeCBORValue(b, depth+1); err != nil {
+				return nil, nil, err
+			}
+			if v, b, err = decodeCBORValue(b, depth+1); err != nil {
+				return nil, nil, err
+			}
+			switch k.(type) {
+			case string, int64, uint64, float64, bool, nil, cborUndefined:
+			default:
+				// arrays, maps, byte strings and tags, which may contain
+				// them, are not comparable or not useful as a key
+				k = fmt.Sprint(k)
+			}
+			m[k] = v
+		}
+		return m, b, nil
+	case 6:
+		v, rest, err := decodeCBORValue(b, depth+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return cborTag{number: n, content: v}, rest, nil
+	}
+	return nil, nil, fmt.Errorf("invalid CBOR major type %d", major)
+}
+
+func cborArgument(info byte, b []byte) (uint64, []byte, error) {
+	switch {
+	case info < 24:
+		return uint64(info), b, nil
+	case info == 24 && len(b) >= 1:
+		return uint64(b[0]), b[1:], nil
+	case info == 25 && len(b) >= 2:
+		return uint64(binary.BigEndian.Uint16(b)), b[2:], nil
+	case info == 26 && len(b) >= 4:
+		return uint64(binary.BigEndian.Uint32(b)), b[4:], nil
+	case info == 27 && len(b) >= 8:
+		return binary.BigEndian.Uint64(b), b[8:], nil
+	case info > 27:
+		return 0, nil, fmt.Errorf("invalid CBOR additional info %d", info)
+	}
+	return 0, nil, errTruncatedCBOR
+}
+
+func halfToFloat(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var v float64
+	switch exp {
+	case 0:
+		v = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			v = math.Inf(1)
+		} else {
+			v = math.NaN()
+		}
+	default:
+		v = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		return -v
+	}
+	return v
+}
+
+// appendCBORHeader appends the header of a CBOR item with a major type and
+// length, which is all that's needed to encode the COSE signature input.
+func appendCBORHeader(b []byte, major byte, n uint64) []byte {
+	major <<= 5
+	switch {
+	case n < 24:
+		return append(b, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(b, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, major|26), uint32(n))
+	}
+	return binary.BigEndian.AppendUint64(append(b, major|27), n)
+}
+
+// cborMap returns the string keyed value of a decoded map.
+func cborMap(v any, key string) any {
+	m, ok := v.(map[any]any)
+	if !ok {
+		return nil
+	}
+	return m[key]
+}
+
+func cborString(v any) string {
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,203 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package c2pa reads and checks C2PA content credentials, the provenance
+// manifests some providers embed in generated images.
+package c2pa
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrNoManifest is returned for files without content credentials.
+var ErrNoManifest = errors.New("no content credentials found")
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// extract returns the JUMBF manifest store embedded in a PNG or JPEG file.
+func extract(b []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(b, pngSignature):
+		return extractPNG(b)
+	case bytes.HasPrefix(b, []byte{0xff, 0xd8}):
+		return extractJPEG(b)
+	}
+	return nil, errors.New("unsupported file format, expected PNG or JPEG")
+}
+
+// extractPNG returns the caBX chunk.
+func extractPNG(b []byte) ([]byte, error) {
+	pos := len(pngSignature)
+	for pos+8 <= len(b) {
+		length := int(binary.BigEndian.Uint32(b[pos:]))
+		typ := string(b[pos+4 : pos+8])
+		if length < 0 || pos+12+length > len(b) {
+			return nil, errors.New("truncated PNG chunk")
+		}
+		if typ == "caBX" {
+			return b[pos+8 : pos+8+length], nil
+		}
+		if typ == "IEND" {
+			break
+		}
+		pos += 12 + length
+	}
+	return nil, ErrNoManifest
+}
+
+// extractJPEG joins the JUMBF parts of the APP11 segments. Every segment
+// starts with "JP", the box instance, the sequence number and the box header,
+// which is repeated in every segment but only kept from the first one.
+func extractJPEG(b []byte) ([]byte, error) {
+	var store []byte
+	pos := 2
+	for pos+4 <= len(b) {
+		if b[pos] != 0xff {
+			return nil, errors.New("invalid JPEG marker")
+		}
+		marker := b[pos+1]
+		if marker == 0xd9 || marker == 0xda {
+			// end of image or start of scan, no more metadata
+			break
+		}
+		if marker == 0x01 || (marker >= 0xd0 && marker <= 0xd7) || marker == 0xff {
+			pos++
+			continue
+		}
+		length := int(binary.BigEndian.Uint16(b[pos+2:]))
+		if length < 2 || pos+2+length > len(b) {
+			return nil, errors.New("truncated JPEG segment")
+		}
+		seg := b[pos+4 : pos+2+length]
+		if marker == 0xeb && len(seg) >= 16 && seg[0] == 'J' && seg[1] == 'P' {
+			seq := binary.BigEndian.Uint32(seg[4:8])
+			box := seg[8:]
+			if seq == 1 || store == nil {
+				store = append(store, box...)
+			} else {
+				store = append(store, box[8:]...)
+			}
+		}
+		pos += 2 + length
+	}
+	if store == nil {
+		return nil, ErrNoManifest
+	}
+	return store, nil
+}
+
+// box is a JUMBF box.
+type box struct {
+	typ string
+	// payload is the content after the box header.
+	payload []byte
+	// label is the description label of a superbox.
+	label    string
+	children []box
+}
+
+// child returns the first child superbox with the label.
+func (b box) child(label string) (box, bool) {
+	for _, c := range b.children {
+		if c.label == label {
+			return c, true
+		}
+	}
+	return box{}, false
+}
+
+// content returns the payload of the first content box of a superbox with
+// the type, e.g. "cbor" or "json".
+func (b box) content(typ string) ([]byte, bool) {
+	for _, c := range b.children {
+		if c.typ == typ {
+			return c.payload, true
+		}
+	}
+	return nil, false
+}
+
+// maxDepth limits the nesting of superboxes.
+const maxDepth = 16
+
+func parseBoxes(b []byte, depth int) ([]box, error) {
+	if depth > maxDepth {
+		return nil, errors.New("JUMBF boxes nested too deep")
+	}
+	var boxes []box
+	for len(b) > 0 {
+		if len(b) < 8 {
+			return nil, errors.New("truncated JUMBF box")
+		}
+		size := uint64(binary.BigEndian.Uint32(b))
+		typ := string(b[4:8])
+		header := uint64(8)
+		switch size {
+		case 0:
+			// the box extends to the end
+			size = uint64(len(b))
+		case 1:
+			if len(b) < 16 {
+				return nil, errors.New("truncated JUMBF box")
+			}
+			size = binary.BigEndian.Uint64(b[8:])
+			header = 16
+		}
+		if size < header || size > uint64(len(b)) {
+			return nil, fmt.Errorf("invalid size of JUMBF box %q", typ)
+		}
+		bx := box{typ: typ, payload: b[header:size]}
+		if typ == "jumb" {
+			children, err := parseBoxes(bx.payload, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			bx.children = children
+			if len(children) > 0 && children[0].typ == "jumd" {
+				bx.label = descriptionLabel(children[0].payload)
+			}
+		}
+		boxes = append(boxes, bx)
+		b = b[size:]
+	}
+	return boxes, nil
+}
+
+// descriptionLabel returns the label of a description box, which follows
+// the 16 byte type UUID and the toggles.
+func descriptionLabel(b []byte) string {
+	if len(b) < 17 || b[16]&0x02 == 0 {
+		return ""
+	}
+	label, _, _ := bytes.Cut(b[17:], []byte{0})
+	return string(label)
+}
+
+// Has reports if the file has content credentials.
+func Has(path string) bool {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	_, err = extract(b)
+	return err == nil
+}
@@ -0,0 +1,193 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package checksums keeps SHA-256 manifests of the generated files, so asset
+// pipelines can verify their integrity and detect accidental edits. The
+// manifests use the format of sha256sum and can be checked with
+// "sha256sum -c SHA256SUMS" as well.
+package checksums
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/bloodmagesoftware/climage/filelock"
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+// FileName is the name of the manifest in every directory with generated
+// files.
+const FileName = "SHA256SUMS"
+
+// Status is the result of checking one file.
+type Status string
+
+const (
+	StatusOK       Status = "ok"
+	StatusModified Status = "modified"
+	StatusMissing  Status = "missing"
+)
+
+// Result is the status of a file listed in a manifest.
+type Result struct {
+	Path   string
+	Status Status
+}
+
+var mu sync.Mutex
+
+// Update sets the hashes of the files in the manifests in their directories.
+// A file that is already listed, e.g. a rewritten sidecar, keeps its line.
+func Update(paths ...string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, path := range paths {
+		sum, err := HashFile(path)
+		if err != nil {
+			return err
+		}
+		if err := update(filepath.Join(filepath.Dir(path), FileName), filepath.Base(path), sum); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func update(manifest string, name string, sum string) error {
+	// other processes may write to the same directory
+	unlock, err := filelock.Lock(manifest + ".lock")
+	if err != nil {
+		return fmt.Errorf("failed to lock checksum manifest: %w", err)
+	}
+	defer unlock()
+	b, err := os.ReadFile(manifest)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read checksum manifest: %w", err)
+	}
+	line := sum + "  " + name
+	var lines []string
+	found := false
+	for _, l := range strings.Split(strings.TrimSuffix(string(b), "\n"), "\n") {
+		if _, n, ok := strings.Cut(l, "  "); ok && n == name {
+			if found {
+				// drop the duplicates written by earlier versions
+				continue
+			}
+			l = line
+			found = true
+		}
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+	if !found {
+		lines = append(lines, line)
+	}
+	if err := providers.WriteFileAtomic(manifest, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write checksum manifest: %w", err)
+	}
+	return nil
+}
+
+// Verify checks the files listed in the manifest. A file listed more than
+// once, which earlier versions wrote for rewritten sidecars, is checked
+// against its last hash.
+func Verify(manifest string) ([]Result, error) {
+	f, err := os.Open(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checksum manifest: %w", err)
+	}
+	defer f.Close()
+	sums := make(map[string]string)
+	var names []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		sum, name, ok := strings.Cut(scanner.Text(), "  ")
+		if !ok {
+			continue
+		}
+		if _, seen := sums[name]; !seen {
+			names = append(names, name)
+		}
+		sums[name] = sum
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read checksum manifest: %w", err)
+	}
+
+	dir := filepath.Dir(manifest)
+	results := make([]Result, 0, len(names))
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		sum, err := HashFile(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			results = append(results, Result{Path: path, Status: StatusMissing})
+		case err != nil:
+			return results, err
+		case sum != sums[name]:
+			results = append(results, Result{Path: path, Status: StatusModified})
+		default:
+			results = append(results, Result{Path: path, Status: StatusOK})
+		}
+	}
+	return results, nil
+}
+
+// Find returns the manifests in dir and its subdirectories.
+func Find(dir string) ([]string, error) {
+	var manifests []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && d.Name() == FileName {
+			manifests = append(manifests, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find checksum manifests: %w", err)
+	}
+	slices.Sort(manifests)
+	return manifests, nil
+}
+
+// HashFile returns the hex encoded SHA-256 of the file. A missing file is
+// reported with an error matching os.ErrNotExist.
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
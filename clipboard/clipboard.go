@@ -0,0 +1,49 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package clipboard
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrUnsupported is returned on systems the clipboard can't be read on.
+	ErrUnsupported = errors.New("reading the clipboard is not supported on this system")
+	// ErrNoImage is returned if the clipboard doesn't contain an image.
+	ErrNoImage = errors.New("the clipboard doesn't contain an image")
+)
+
+// Image returns the image in the system clipboard, e.g. a screenshot, as PNG
+// or in the format it was copied in.
+func Image() ([]byte, error) {
+	b, err := readImage()
+	if err != nil {
+		if errors.Is(err, ErrUnsupported) || errors.Is(err, ErrNoImage) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read clipboard: %w", err)
+	}
+	if len(b) == 0 || !strings.HasPrefix(http.DetectContentType(b), "image/") {
+		return nil, ErrNoImage
+	}
+	return b, nil
+}
@@ -0,0 +1,41 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package clipboard
+
+import (
+	"encoding/hex"
+	"os/exec"
+	"strings"
+)
+
+func readImage() ([]byte, error) {
+	// AppleScript prints the data as «data PNGf89504E47...», screenshots are
+	// converted to PNG by the clipboard
+	out, err := exec.Command("osascript", "-e", "the clipboard as «class PNGf»").Output()
+	if err != nil {
+		// osascript fails if the clipboard can't be converted to PNG
+		return nil, ErrNoImage
+	}
+	data := strings.TrimSpace(string(out))
+	data, ok := strings.CutPrefix(data, "«data PNGf")
+	if !ok {
+		return nil, ErrNoImage
+	}
+	return hex.DecodeString(strings.TrimSuffix(data, "»"))
+}
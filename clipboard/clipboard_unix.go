@@ -0,0 +1,77 @@
+//go:build linux || freebsd || openbsd || netbsd || dragonfly
+
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package clipboard
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+func readImage() ([]byte, error) {
+	// Wayland compositors only share the clipboard with wl-clipboard, X11 and
+	// XWayland use xclip
+	pkg := "wl-clipboard"
+	list, read := []string{"wl-paste", "--list-types"}, []string{"wl-paste", "--no-newline", "--type"}
+	if os.Getenv("WAYLAND_DISPLAY") == "" {
+		if os.Getenv("DISPLAY") == "" {
+			return nil, ErrUnsupported
+		}
+		pkg = "xclip"
+		list, read = []string{"xclip", "-selection", "clipboard", "-target", "TARGETS", "-out"}, []string{"xclip", "-selection", "clipboard", "-out", "-target"}
+	}
+	if _, err := exec.LookPath(list[0]); err != nil {
+		return nil, fmt.Errorf("%w, install %s", ErrUnsupported, pkg)
+	}
+	types, err := run(list[0], list[1:]...)
+	if err != nil {
+		// both fail if nothing was copied
+		return nil, ErrNoImage
+	}
+	var mimeType string
+	for _, t := range strings.Fields(string(types)) {
+		// PNG is lossless, otherwise the first image type is taken
+		if t == "image/png" {
+			mimeType = t
+			break
+		}
+		if mimeType == "" && strings.HasPrefix(t, "image/") {
+			mimeType = t
+		}
+	}
+	if mimeType == "" {
+		return nil, ErrNoImage
+	}
+	return run(read[0], append(read[1:], mimeType)...)
+}
+
+func run(name string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
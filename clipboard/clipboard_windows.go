@@ -0,0 +1,53 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package clipboard
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// clipboardScript writes the clipboard image as PNG to stdout and exits with 2
+// if there is none. The clipboard is only accessible from an STA thread.
+const clipboardScript = `Add-Type -AssemblyName System.Windows.Forms, System.Drawing
+$img = [System.Windows.Forms.Clipboard]::GetImage()
+if ($img -eq $null) { exit 2 }
+$buf = New-Object System.IO.MemoryStream
+$img.Save($buf, [System.Drawing.Imaging.ImageFormat]::Png)
+$out = [Console]::OpenStandardOutput()
+$out.Write($buf.ToArray(), 0, $buf.Length)
+$out.Flush()`
+
+func readImage() ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("powershell.exe", "-NoProfile", "-NonInteractive", "-STA", "-Command", clipboardScript)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 2 {
+		return nil, ErrNoImage
+	}
+	if err != nil {
+		return nil, fmt.Errorf("powershell: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,212 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/user"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/audit"
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/spf13/cobra"
+)
+
+var auditExportFlags struct {
+	format string
+	output string
+	since  string
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Export and verify the audit log of generation requests",
+	Long: `With "audit_log": true in the config every generation request is recorded in an append-only audit log with the user, host, model, prompt, settings, saved files and errors. The request is recorded before it is sent, a generation fails if the audit log can't be written. Every entry contains the SHA-256 hash of the previous one, so modified, inserted or removed entries are detected by 'climage audit verify'.
+
+Without a subcommand the log is verified.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return auditVerifyCmd.RunE(cmd, args)
+	},
+}
+
+var auditVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check the hash chain of the audit log",
+	Long:  `Check that no entry of the audit log was modified, inserted or removed since it was written. Truncating the end of the log can't be detected from the log alone, compare the last hash with an exported copy for that.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := audit.Read()
+		if err != nil {
+			return err
+		}
+		if err := audit.Verify(entries); err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Println("the audit log is empty")
+			return nil
+		}
+		last := entries[len(entries)-1]
+		fmt.Printf("%d entries verified, last hash %s\n", len(entries), last.Hash)
+		return nil
+	},
+}
+
+var auditExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the audit log",
+	Long:  `Export the audit log after verifying it, as JSON lines with the hashes for archiving or as CSV for spreadsheets. With --since only the entries from that day on are exported, their chain can still be verified from the previous hash of the first entry.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var since time.Time
+		if auditExportFlags.since != "" {
+			var err error
+			if since, err = time.ParseInLocation(time.DateOnly, auditExportFlags.since, time.Local); err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+		}
+		entries, err := audit.Read()
+		if err != nil {
+			return err
+		}
+		if err := audit.Verify(entries); err != nil {
+			return err
+		}
+		entries = slices.DeleteFunc(entries, func(e audit.Entry) bool { return e.Time.Before(since) })
+
+		var w io.Writer = os.Stdout
+		if auditExportFlags.output != "" {
+			f, err := os.Create(auditExportFlags.output)
+			if err != nil {
+				return fmt.Errorf("failed to create export: %w", err)
+			}
+			defer f.Close()
+			w = f
+		}
+		switch auditExportFlags.format {
+		case "jsonl":
+			enc := json.NewEncoder(w)
+			for _, e := range entries {
+				if err := enc.Encode(e); err != nil {
+					return fmt.Errorf("failed to write export: %w", err)
+				}
+			}
+		case "csv":
+			if err := writeAuditCSV(w, entries); err != nil {
+				return fmt.Errorf("failed to write export: %w", err)
+			}
+		default:
+			return fmt.Errorf("invalid --format %q, expected jsonl or csv", auditExportFlags.format)
+		}
+		return nil
+	},
+}
+
+func writeAuditCSV(w io.Writer, entries []audit.Entry) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"seq", "time", "request", "user", "host", "model", "prompt", "settings", "files", "filtered", "error", "prev", "hash"})
+	for _, e := range entries {
+		settings := make([]string, 0, len(e.Settings))
+		for name, value := range e.Settings {
+			settings = append(settings, name+"="+value)
+		}
+		slices.Sort(settings)
+		_ = cw.Write([]string{
+			strconv.Itoa(e.Seq),
+			e.Time.Format(time.RFC3339),
+			strconv.FormatBool(e.Request),
+			e.User,
+			e.Host,
+			e.Model,
+			e.Prompt,
+			strings.Join(settings, "; "),
+			strings.Join(e.Files, "; "),
+			strconv.Itoa(e.Filtered),
+			e.Error,
+			e.Prev,
+			e.Hash,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func newAuditEntry(model string, prompt string, settings providers.ModelSettings) audit.Entry {
+	e := audit.Entry{Time: time.Now(), Model: model, Prompt: prompt, Settings: settings.Values()}
+	if u, err := user.Current(); err == nil {
+		e.User = u.Username
+	}
+	e.Host, _ = os.Hostname()
+	return e
+}
+
+// auditRequest appends a generation request to the audit log before the
+// provider is called, if the audit log is enabled. A request that can't be
+// recorded must not be sent.
+func auditRequest(cfg config.Config, model string, prompt string, settings providers.ModelSettings) error {
+	if !cfg.AuditLog {
+		return nil
+	}
+	e := newAuditEntry(model, prompt, settings)
+	e.Request = true
+	if err := audit.Append(e); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// recordAudit appends the outcome of a generation request to the audit log,
+// failed ones included.
+func recordAudit(model string, prompt string, settings providers.ModelSettings, images []providers.Image, err error) {
+	e := newAuditEntry(model, prompt, settings)
+	for _, img := range images {
+		if img.Safety.Filtered {
+			e.Filtered++
+		} else if img.Path != "" {
+			e.Files = append(e.Files, img.Path)
+		} else {
+			e.Files = append(e.Files, img.Uploads...)
+		}
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	if err := audit.Append(e); err != nil {
+		log.Printf("warning: failed to write audit log: %v", err)
+	}
+}
+
+func init() {
+	auditExportCmd.Flags().StringVar(&auditExportFlags.format, "format", "jsonl", "export format: \"jsonl\" or \"csv\"")
+	auditExportCmd.Flags().StringVarP(&auditExportFlags.output, "output", "o", "", "file to write the export to instead of stdout")
+	auditExportCmd.Flags().StringVar(&auditExportFlags.since, "since", "", "first day to export, e.g. 2025-01-01")
+
+	auditCmd.AddCommand(auditVerifyCmd, auditExportCmd)
+	rootCmd.AddCommand(auditCmd)
+}
@@ -19,12 +19,13 @@ along with this program.  If not, see <https://www.gnu.org/licenses/>.
 package cmd
 
 import (
+	"context"
 	"encoding/base64"
-	"fmt"
 	"io"
 	"os"
 
 	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/i18n"
 	"github.com/bloodmagesoftware/climage/providers"
 	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
@@ -41,119 +42,129 @@ var authLoginCmd = &cobra.Command{
 	Short: "Login to a provider",
 	Long:  `Login to an image generation provider by providing your credentials. This allows CLImage to generate images using the selected provider.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var providerNames []string
-
 		cfg, err := config.GetConfig()
 		if err != nil {
-			return fmt.Errorf("failed to get config: %w", err)
+			return i18n.Errorf("error.config", err)
 		}
+		return login(cmd.Context(), &cfg)
+	},
+}
 
-	full_provider_list:
-		for _, a := range providers.GetProviderNames() {
-			for _, b := range cfg.Providers {
-				if a == b.Name {
-					continue full_provider_list
-				}
+// login asks for a provider that is not logged in yet and its credentials.
+// The provider is added to cfg, which is saved.
+func login(ctx context.Context, cfg *config.Config) error {
+	if err := requireInteractive("login"); err != nil {
+		return err
+	}
+	var providerNames []string
+
+full_provider_list:
+	for _, a := range providers.GetProviderNames() {
+		for _, b := range cfg.Providers {
+			if a == b.Name {
+				continue full_provider_list
 			}
-			providerNames = append(providerNames, a)
 		}
-
-		if len(providerNames) == 0 {
-			return fmt.Errorf("no providers are available")
-		}
-
-		providerName := providerNames[0]
-
-		if err := huh.NewForm(huh.NewGroup(
-			huh.NewSelect[string]().
-				Title("Provider").
-				Description("Select a provider to login with.").
-				Options(huh.NewOptions(providerNames...)...).
+		providerNames = append(providerNames, a)
+	}
+
+	if len(providerNames) == 0 {
+		return i18n.Errorf("error.no_providers")
+	}
+
+	providerName := providerNames[0]
+
+	if err := newForm(*cfg, huh.NewGroup(
+		huh.NewSelect[string]().
+			Title(i18n.T("auth.provider.title")).
+			Description(i18n.T("auth.login.description")).
+			Options(huh.NewOptions(providerNames...)...).
+			Validate(huh.ValidateNotEmpty()).
+			Value(&providerName),
+	)).Run(); err != nil {
+		return i18n.Errorf("error.provider_form", err)
+	}
+
+	provider, err := providers.GetProviderByName(providerName)
+	if err != nil {
+		return i18n.Errorf("error.get_provider", err)
+	}
+
+	loginFields := provider.GetLoginFields()
+	credentials := make(map[string]string)
+	credentialValues := make(map[string]*string)
+	var formFields []huh.Field
+
+	for _, field := range loginFields {
+		value := ""
+		credentialValues[field.Name] = &value
+		if field.Type == "file" {
+			currentDirectory := "."
+			if homeDir, err := os.UserHomeDir(); err == nil {
+				currentDirectory = homeDir
+			}
+			formFields = append(formFields, huh.NewFilePicker().
+				DirAllowed(false).
+				ShowHidden(false).
+				Title(field.DisplayName).
 				Validate(huh.ValidateNotEmpty()).
-				Value(&providerName),
-		)).Run(); err != nil {
-			return fmt.Errorf("failed to run provider selection: %w", err)
-		}
-
-		provider, err := providers.GetProviderByName(providerName)
-		if err != nil {
-			return fmt.Errorf("failed to get provider: %w", err)
-		}
-
-		loginFields := provider.GetLoginFields()
-		credentials := make(map[string]string)
-		credentialValues := make(map[string]*string)
-		var formFields []huh.Field
-
-		for _, field := range loginFields {
-			value := ""
-			credentialValues[field.Name] = &value
-			if field.Type == "file" {
-				currentDirectory := "."
-				if homeDir, err := os.UserHomeDir(); err == nil {
-					currentDirectory = homeDir
-				}
-				formFields = append(formFields, huh.NewFilePicker().
-					DirAllowed(false).
-					ShowHidden(false).
-					Title(field.DisplayName).
-					Validate(huh.ValidateNotEmpty()).
-					CurrentDirectory(currentDirectory).
-					Value(credentialValues[field.Name]))
-			} else {
-				input := huh.NewInput().
-					Title(field.DisplayName).
-					Validate(huh.ValidateNotEmpty()).
-					Value(credentialValues[field.Name])
-				if field.Secret {
-					input = input.EchoMode(huh.EchoModePassword)
-				}
-				formFields = append(formFields, input)
+				CurrentDirectory(currentDirectory).
+				Value(credentialValues[field.Name]))
+		} else {
+			input := huh.NewInput().
+				Title(field.DisplayName).
+				Validate(huh.ValidateNotEmpty()).
+				Value(credentialValues[field.Name])
+			if field.Secret {
+				input = input.EchoMode(huh.EchoModePassword)
 			}
+			formFields = append(formFields, input)
 		}
+	}
 
-		if err := huh.NewForm(huh.NewGroup(formFields...)).Run(); err != nil {
-			return fmt.Errorf("failed to run login form: %w", err)
+	if len(formFields) > 0 {
+		if err := newForm(*cfg, huh.NewGroup(formFields...)).Run(); err != nil {
+			return i18n.Errorf("error.login_form", err)
 		}
+	}
 
-		for _, field := range loginFields {
-			if field.Type == "file" && credentialValues[field.Name] != nil {
-				// read file contents
-				f, err := os.Open(*credentialValues[field.Name])
-				if err != nil {
-					return fmt.Errorf("failed to open file: %w", err)
-				}
-				b, err := io.ReadAll(f)
-				_ = f.Close()
-				if err != nil {
-					return fmt.Errorf("failed to read file: %w", err)
-				}
-				b64 := base64.StdEncoding.EncodeToString(b)
-				credentialValues[field.Name] = &b64
+	for _, field := range loginFields {
+		if field.Type == "file" && credentialValues[field.Name] != nil {
+			// read file contents
+			f, err := os.Open(*credentialValues[field.Name])
+			if err != nil {
+				return i18n.Errorf("error.open_file", err)
 			}
+			b, err := io.ReadAll(f)
+			_ = f.Close()
+			if err != nil {
+				return i18n.Errorf("error.read_file", err)
+			}
+			b64 := base64.StdEncoding.EncodeToString(b)
+			credentialValues[field.Name] = &b64
 		}
+	}
 
-		for name, valuePtr := range credentialValues {
-			credentials[name] = *valuePtr
-		}
+	for name, valuePtr := range credentialValues {
+		credentials[name] = *valuePtr
+	}
 
-		if err := provider.Login(cmd.Context(), credentials); err != nil {
-			return fmt.Errorf("failed to login with provided credentials: %w", err)
-		}
+	if err := provider.Login(ctx, credentials); err != nil {
+		return i18n.Errorf("error.login", err)
+	}
 
-		if err := provider.SaveCredentials(credentials); err != nil {
-			return fmt.Errorf("failed to save credentials: %w", err)
-		}
+	if err := provider.SaveCredentials(credentials); err != nil {
+		return i18n.Errorf("error.save_credentials", err)
+	}
 
-		cfg.Providers = append(cfg.Providers, config.Provider{
-			providerName,
-		})
-		if err = cfg.Save(); err != nil {
-			return fmt.Errorf("failed to save config: %w", err)
-		}
+	cfg.Providers = append(cfg.Providers, config.Provider{
+		Name: providerName,
+	})
+	if err := cfg.Save(); err != nil {
+		return i18n.Errorf("error.save_config", err)
+	}
 
-		return nil
-	},
+	return nil
 }
 
 var authLogoutCmd = &cobra.Command{
@@ -163,11 +174,14 @@ var authLogoutCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := config.GetConfig()
 		if err != nil {
-			return fmt.Errorf("failed to get config: %w", err)
+			return i18n.Errorf("error.config", err)
 		}
 
 		if len(cfg.Providers) == 0 {
-			return fmt.Errorf("not logged in to any provider")
+			return i18n.Errorf("error.not_logged_in")
+		}
+		if err := requireInteractive("logout"); err != nil {
+			return err
 		}
 
 		loggedInProviders := make([]string, len(cfg.Providers))
@@ -176,23 +190,23 @@ var authLogoutCmd = &cobra.Command{
 		}
 
 		providerName := loggedInProviders[0]
-		if err := huh.NewForm(huh.NewGroup(
+		if err := newForm(cfg, huh.NewGroup(
 			huh.NewSelect[string]().
-				Title("Provider").
-				Description("Select a provider to logout from.").
+				Title(i18n.T("auth.provider.title")).
+				Description(i18n.T("auth.logout.description")).
 				Options(huh.NewOptions(loggedInProviders...)...).
 				Value(&providerName),
 		)).Run(); err != nil {
-			return fmt.Errorf("failed to run logout form: %w", err)
+			return i18n.Errorf("error.logout_form", err)
 		}
 
 		provider, err := providers.GetProviderByName(providerName)
 		if err != nil {
-			return fmt.Errorf("failed to get provider: %w", err)
+			return i18n.Errorf("error.get_provider", err)
 		}
 
 		if err := provider.DeleteCredentials(); err != nil {
-			return fmt.Errorf("failed to delete credentials: %w", err)
+			return i18n.Errorf("error.delete_credentials", err)
 		}
 
 		newProviders := make([]config.Provider, 0, len(cfg.Providers)-1)
@@ -204,7 +218,7 @@ var authLogoutCmd = &cobra.Command{
 		cfg.Providers = newProviders
 
 		if err = cfg.Save(); err != nil {
-			return fmt.Errorf("failed to save config: %w", err)
+			return i18n.Errorf("error.save_config", err)
 		}
 
 		return nil
@@ -0,0 +1,306 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/bloodmagesoftware/climage/report"
+	"github.com/bloodmagesoftware/climage/sidecar"
+	"github.com/bloodmagesoftware/climage/sprites"
+	"github.com/spf13/cobra"
+)
+
+var batchFlags struct {
+	model  string
+	preset string
+	set    []string
+	resume bool
+	json   bool
+	size   string
+	style  string
+
+	iconSet     bool
+	tile        bool
+	spriteSheet string
+	report      string
+	archive     string
+}
+
+var batchCmd = &cobra.Command{
+	Use:   "batch <prompts-file>",
+	Short: "Generate images for every prompt in a file",
+	Long:  `Generate images for every line of a prompts file. Empty lines and lines starting with '#' are skipped. Completed prompts are recorded in a checkpoint file next to the prompts file, so an interrupted run can be continued with --resume without generating finished prompts again. The checkpoint is removed once all prompts are done. With --sprite-sheet the images of all prompts, including those of a resumed run, are packed into one sheet for game prototyping. With --report a Markdown report with the prompts, the settings and relative links to the images is written, e.g. to commit an exploration session to a repo or paste it into a wiki. With --archive all outputs of the run, the images with their sidecars, tiled previews and icon sets, the sprite sheet and the report, are bundled into a zip file with a manifest.json for handoff.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+
+		modelName := cfg.DefaultModel
+		if batchFlags.model != "" {
+			modelName = batchFlags.model
+		}
+		model, modelSettings, err := resolveModel(cfg, modelName)
+		if err != nil {
+			return err
+		}
+		if batchFlags.model != "" && model != batchFlags.model {
+			return fmt.Errorf("model %q is not available", batchFlags.model)
+		}
+		modelSettings, err = applySettingFlags(cfg, modelSettings, batchFlags.preset, batchFlags.set)
+		if err != nil {
+			return err
+		}
+		post, modelSettings, err := useOutputSize(postProcessing{}, modelSettings, iconSetOutputSize(batchFlags.iconSet, batchFlags.size))
+		if err != nil {
+			return err
+		}
+		post.tile = batchFlags.tile
+		style, err := getStyle(batchFlags.style)
+		if err != nil {
+			return err
+		}
+
+		prompts, err := readPrompts(args[0])
+		if err != nil {
+			return err
+		}
+
+		checkpointPath := args[0] + ".checkpoint"
+		completed := make(map[int]batchCheckpoint)
+		if batchFlags.resume {
+			completed, err = readCheckpoint(checkpointPath)
+			if err != nil {
+				return err
+			}
+		} else if _, err := os.Stat(checkpointPath); err == nil {
+			return fmt.Errorf("checkpoint %q exists, use --resume to continue or remove it to start over", checkpointPath)
+		}
+
+		checkpoint, err := os.OpenFile(checkpointPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open checkpoint: %w", err)
+		}
+		defer checkpoint.Close()
+		enc := json.NewEncoder(checkpoint)
+		var spriteEntries []sprites.Entry
+		var reportPrompts []report.Prompt
+
+		for i, prompt := range prompts {
+			if c, ok := completed[i]; ok && c.Prompt == prompt {
+				reportPrompt := report.Prompt{Prompt: prompt}
+				for _, f := range c.Files {
+					spriteEntries = append(spriteEntries, sprites.Entry{Path: f, Prompt: prompt})
+					img := providers.Image{Path: f}
+					if meta, err := sidecar.Read(sidecar.Path(f)); err == nil {
+						img.Seed = meta.Seed
+					}
+					reportPrompt.Images = append(reportPrompt.Images, img)
+				}
+				reportPrompts = append(reportPrompts, reportPrompt)
+				if batchFlags.json {
+					continue
+				}
+				fmt.Printf("[%d/%d] skipping completed prompt %q\n", i+1, len(prompts), prompt)
+				continue
+			}
+			if !batchFlags.json {
+				fmt.Printf("[%d/%d] %s: %q\n", i+1, len(prompts), model, prompt)
+			}
+			images, err := generate(cmd.Context(), cfg, model, applyStyle(style, prompt), modelSettings, post)
+			if err != nil {
+				return fmt.Errorf("failed to generate image for prompt %d: %w", i+1, err)
+			}
+			var iconDirs []string
+			if batchFlags.iconSet {
+				if iconDirs, err = exportIconSets(images); err != nil {
+					return fmt.Errorf("failed to export icons for prompt %d: %w", i+1, err)
+				}
+			}
+			if batchFlags.json {
+				if err := json.NewEncoder(os.Stdout).Encode(batchResult{Prompt: prompt, Model: model, Images: images}); err != nil {
+					return fmt.Errorf("failed to write result: %w", err)
+				}
+			} else {
+				for _, img := range images {
+					printImage(img)
+				}
+				for _, dir := range iconDirs {
+					fmt.Println(dir)
+				}
+				if batchFlags.tile {
+					printTilePreviews(images)
+				}
+			}
+			if err := enc.Encode(batchCheckpoint{Index: i, Prompt: prompt, Files: providers.Paths(images)}); err != nil {
+				return fmt.Errorf("failed to write checkpoint: %w", err)
+			}
+			for _, f := range providers.Paths(images) {
+				spriteEntries = append(spriteEntries, sprites.Entry{Path: f, Prompt: prompt})
+			}
+			reportPrompts = append(reportPrompts, report.Prompt{Prompt: prompt, Images: images})
+			emitProgressPercent(i+1, len(prompts))
+		}
+
+		if batchFlags.spriteSheet != "" {
+			if len(spriteEntries) == 0 {
+				return errors.New("no images to pack into the sprite sheet")
+			}
+			if _, err := sprites.Write(batchFlags.spriteSheet, spriteEntries); err != nil {
+				return err
+			}
+			if !batchFlags.json {
+				fmt.Println(batchFlags.spriteSheet)
+				fmt.Println(sprites.AtlasPath(batchFlags.spriteSheet))
+			}
+		}
+
+		run := report.Run{
+			PromptsPath: args[0],
+			Model:       model,
+			Style:       style.Name,
+			Settings:    modelSettings.Values(),
+			Prompts:     reportPrompts,
+		}
+		if batchFlags.report != "" {
+			if err := report.Write(batchFlags.report, run); err != nil {
+				return err
+			}
+			if !batchFlags.json {
+				fmt.Println(batchFlags.report)
+			}
+		}
+
+		if batchFlags.archive != "" {
+			var extra []string
+			if batchFlags.spriteSheet != "" {
+				extra = append(extra, batchFlags.spriteSheet, sprites.AtlasPath(batchFlags.spriteSheet))
+			}
+			var markdown []byte
+			if batchFlags.report != "" {
+				// linking the images in the archive
+				markdown = report.Markdown(run, func(path string) string {
+					return "images/" + filepath.Base(path)
+				})
+			}
+			if err := report.WriteArchive(batchFlags.archive, run, extra, markdown); err != nil {
+				return err
+			}
+			if !batchFlags.json {
+				fmt.Println(batchFlags.archive)
+			}
+		}
+
+		_ = checkpoint.Close()
+		if err := os.Remove(checkpointPath); err != nil {
+			return fmt.Errorf("failed to remove checkpoint: %w", err)
+		}
+		return nil
+	},
+}
+
+// batchCheckpoint is one completed prompt of a batch run.
+type batchCheckpoint struct {
+	Index  int      `json:"index"`
+	Prompt string   `json:"prompt"`
+	Files  []string `json:"files"`
+}
+
+// batchResult is the --json output for one prompt.
+type batchResult struct {
+	Prompt string            `json:"prompt"`
+	Model  string            `json:"model"`
+	Images []providers.Image `json:"images"`
+}
+
+func readPrompts(filePath string) ([]string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open prompts file: %w", err)
+	}
+	defer f.Close()
+	var prompts []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		prompts = append(prompts, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read prompts file: %w", err)
+	}
+	if len(prompts) == 0 {
+		return nil, fmt.Errorf("no prompts in %q", filePath)
+	}
+	return prompts, nil
+}
+
+func readCheckpoint(filePath string) (map[int]batchCheckpoint, error) {
+	completed := make(map[int]batchCheckpoint)
+	f, err := os.Open(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return completed, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checkpoint: %w", err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var c batchCheckpoint
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			// a truncated entry is expected after a crash
+			continue
+		}
+		completed[c.Index] = c
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	return completed, nil
+}
+
+func init() {
+	batchCmd.Flags().StringVarP(&batchFlags.model, "model", "m", "", "model to generate with, e.g. google/imagen-4.0-generate-001 (defaults to the configured default model)")
+	batchCmd.Flags().StringVar(&batchFlags.preset, "preset", "", "settings preset to apply, see /preset in the interactive session")
+	batchCmd.Flags().StringArrayVar(&batchFlags.set, "set", nil, "set a model setting, e.g. --set aspect_ratio=16:9, can be repeated")
+	batchCmd.Flags().StringVar(&batchFlags.style, "style", "", "style preset added to every prompt, e.g. watercolor, see 'climage styles'")
+	batchCmd.Flags().StringVar(&batchFlags.size, "size", "", "output size preset or WIDTHxHEIGHT, the image is cropped and scaled to it, see 'climage sizes'")
+	batchCmd.Flags().BoolVar(&batchFlags.iconSet, "icon-set", false, "also export the images as favicon and app icon set, 16 to 1024 pixel PNGs, .ico and .icns, in a directory next to each image")
+	batchCmd.Flags().BoolVar(&batchFlags.tile, "tile", false, "make the images seamlessly tileable and write a 2x2 tiled preview next to each")
+	batchCmd.Flags().StringVar(&batchFlags.spriteSheet, "sprite-sheet", "", "also pack all images into this PNG sprite sheet with a JSON atlas of names and rects next to it")
+	batchCmd.Flags().StringVar(&batchFlags.report, "report", "", "also write a Markdown report with the prompts, settings and relative image links to this file, e.g. report.md")
+	batchCmd.Flags().StringVar(&batchFlags.archive, "archive", "", "also bundle all outputs with a manifest into this zip file, e.g. out.zip")
+	batchCmd.Flags().BoolVar(&batchFlags.resume, "resume", false, "continue an interrupted run from its checkpoint")
+	batchCmd.Flags().BoolVar(&batchFlags.json, "json", false, "print one JSON object per prompt with the saved images and safety filter results")
+
+	rootCmd.AddCommand(batchCmd)
+}
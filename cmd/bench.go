@@ -0,0 +1,127 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/spf13/cobra"
+)
+
+var benchFlags struct {
+	prompt string
+	models []string
+	runs   int
+}
+
+// benchResult sums up the runs of one model.
+type benchResult struct {
+	model     string
+	runs      int
+	succeeded int
+	images    int
+	latency   time.Duration
+	cost      float64
+}
+
+func (r benchResult) String() string {
+	avg := "-"
+	if r.succeeded > 0 {
+		avg = (r.latency / time.Duration(r.succeeded)).Round(100 * time.Millisecond).String()
+	}
+	success := fmt.Sprintf("%d/%d (%.0f%%)", r.succeeded, r.runs, 100*float64(r.succeeded)/float64(r.runs))
+	cost := fmt.Sprintf("$%.3f", r.cost)
+	return fmt.Sprintf("%-50s %-12s %10s %6d %9s", r.model, success, avg, r.images, cost)
+}
+
+var benchCmd = &cobra.Command{
+	Use:   "bench --prompt <prompt> --model <model>...",
+	Short: "Compare models with the same prompt",
+	Long:  `Generate the same prompt with each selected model and report the average latency of successful runs, the success rate, the number of images and the estimated cost in a table, to help choosing a default model. Every run is a real generation that is saved and billed like any other. The models use their default settings.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+		if benchFlags.prompt == "" {
+			return errors.New("--prompt is required")
+		}
+		if len(benchFlags.models) == 0 {
+			return errors.New("select the models to compare with --model, see 'climage models'")
+		}
+		if benchFlags.runs < 1 {
+			return errors.New("--runs must be at least 1")
+		}
+		settings := make(map[string]providers.ModelSettings)
+		for _, model := range benchFlags.models {
+			resolved, s, err := resolveModel(cfg, model)
+			if err != nil {
+				return err
+			}
+			if resolved != model {
+				return fmt.Errorf("model %q is not available", model)
+			}
+			settings[model] = s
+		}
+
+		var results []benchResult
+		for _, model := range benchFlags.models {
+			r := benchResult{model: model}
+			for i := range benchFlags.runs {
+				fmt.Printf("[%s %d/%d]\n", model, i+1, benchFlags.runs)
+				start := time.Now()
+				images, err := generate(cmd.Context(), cfg, model, benchFlags.prompt, settings[model].Clone(), postProcessing{})
+				r.runs++
+				if err != nil {
+					if ctxErr := cmd.Context().Err(); ctxErr != nil {
+						return ctxErr
+					}
+					log.Printf("warning: %s failed: %v", model, err)
+					continue
+				}
+				r.succeeded++
+				r.latency += time.Since(start)
+				n := len(providers.Paths(images))
+				r.images += n
+				r.cost += modelPrice(model) * float64(n)
+			}
+			results = append(results, r)
+		}
+
+		fmt.Printf("\n%-50s %-12s %10s %6s %9s\n", "model", "success", "latency", "images", "cost")
+		for _, r := range results {
+			fmt.Println(r)
+		}
+		return nil
+	},
+}
+
+func init() {
+	benchCmd.Flags().StringVarP(&benchFlags.prompt, "prompt", "p", "", "prompt to generate with every model")
+	benchCmd.Flags().StringArrayVarP(&benchFlags.models, "model", "m", nil, "model to compare, e.g. google/imagen-4.0-generate-001, can be repeated")
+	benchCmd.Flags().IntVar(&benchFlags.runs, "runs", 1, "number of generations per model")
+
+	rootCmd.AddCommand(benchCmd)
+}
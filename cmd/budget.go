@@ -0,0 +1,77 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/history"
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+// forceBudget generates even if a budget is used up, set with --force.
+var forceBudget bool
+
+// budgetWarnRatio is the share of a budget at which the user is warned.
+const budgetWarnRatio = 0.8
+
+// checkBudget refuses to generate with the provider once its daily or monthly
+// budget is used up, based on the spend estimated in the local history.
+func checkBudget(cfg config.Config, providerName string) error {
+	b, ok := cfg.Budgets[providerName]
+	if !ok {
+		return nil
+	}
+	for _, p := range budgetPeriods(b, time.Now()) {
+		spend, err := history.Spend(providerName, p.since)
+		if err != nil {
+			return fmt.Errorf("failed to get spend of %s: %w", providerName, err)
+		}
+		switch {
+		case spend >= p.limit && !forceBudget:
+			return providers.NewError(providers.ErrorKindQuota, providerName, fmt.Errorf("the %s budget of $%.2f for %s is used up ($%.2f spent), use --force to generate anyway", p.name, p.limit, providerName, spend))
+		case spend >= p.limit:
+			warnUser("the %s budget of $%.2f for %s is used up ($%.2f spent)", p.name, p.limit, providerName, spend)
+		case spend >= budgetWarnRatio*p.limit:
+			warnUser("%.0f%% of the %s budget of $%.2f for %s used ($%.2f spent)", 100*spend/p.limit, p.name, p.limit, providerName, spend)
+		}
+	}
+	return nil
+}
+
+// budgetPeriod is a capped period of a budget.
+type budgetPeriod struct {
+	name  string
+	limit float64
+	since time.Time
+}
+
+// budgetPeriods returns the periods of the budget that have a cap.
+func budgetPeriods(b config.Budget, now time.Time) []budgetPeriod {
+	var periods []budgetPeriod
+	if b.Daily > 0 {
+		periods = append(periods, budgetPeriod{"daily", b.Daily, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())})
+	}
+	if b.Monthly > 0 {
+		periods = append(periods, budgetPeriod{"monthly", b.Monthly, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())})
+	}
+	return periods
+}
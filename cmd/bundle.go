@@ -0,0 +1,223 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/checksums"
+	"github.com/bloodmagesoftware/climage/history"
+	"github.com/bloodmagesoftware/climage/report"
+	"github.com/bloodmagesoftware/climage/sidecar"
+	"github.com/spf13/cobra"
+)
+
+var bundleFlags struct {
+	output string
+}
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle <history-id|image>",
+	Short: "Bundle one generation for reproduction or audit",
+	Long: `Write a zip file with everything needed to reproduce or audit one generation: its images and sidecars, and a bundle.json with the prompt, the provider and model, the full settings, the seed and response ID of every image, the SHA-256 hashes of the images and the climage version.
+
+The generation is selected by its history ID, shown in 'climage web', or by the path of one of its images. The settings and seeds are read from the sidecars, which are written if "sidecars" is enabled in the config.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, e, err := findHistoryEntry(args[0])
+		if err != nil {
+			return err
+		}
+		output := bundleFlags.output
+		if output == "" {
+			output = fmt.Sprintf("climage-bundle-%d.zip", id)
+		}
+		if err := writeBundle(output, id, e); err != nil {
+			return err
+		}
+		fmt.Println(output)
+		return nil
+	},
+}
+
+// findHistoryEntry returns the history entry with the ID or the image path.
+func findHistoryEntry(arg string) (int, history.Entry, error) {
+	if id, err := strconv.Atoi(arg); err == nil {
+		e, err := history.Get(id)
+		return id, e, err
+	}
+	abs, err := filepath.Abs(arg)
+	if err != nil {
+		return 0, history.Entry{}, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	entries, err := history.Read()
+	if err != nil {
+		return 0, history.Entry{}, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if slices.Contains(entries[i].Images, abs) || slices.Contains(entries[i].Images, arg) {
+			return entries[i].ID, entries[i], nil
+		}
+	}
+	return 0, history.Entry{}, fmt.Errorf("%s is not in the history", arg)
+}
+
+// bundleManifest is the bundle.json of a reproducibility bundle.
+type bundleManifest struct {
+	HistoryID int       `json:"history_id"`
+	Time      time.Time `json:"time"`
+	Provider  string    `json:"provider"`
+	Model     string    `json:"model"`
+	Prompt    string    `json:"prompt"`
+	// Settings, Size and Tiled are read from the sidecar of the first image
+	// and missing without one.
+	Settings map[string]string `json:"settings,omitempty"`
+	Size     string            `json:"size,omitempty"`
+	Tiled    bool              `json:"tiled,omitempty"`
+	Images   []bundleImage     `json:"images"`
+	// Filtered is the number of images removed by the safety filter.
+	Filtered int           `json:"filtered,omitempty"`
+	Cost     float64       `json:"cost,omitempty"`
+	Climage  bundleVersion `json:"climage"`
+}
+
+type bundleImage struct {
+	// File is the path in the bundle, empty if the image no longer exists
+	// locally.
+	File       string `json:"file,omitempty"`
+	Original   string `json:"original"`
+	SHA256     string `json:"sha256,omitempty"`
+	Seed       *int64 `json:"seed,omitempty"`
+	ResponseID string `json:"response_id,omitempty"`
+	Watermark  string `json:"watermark,omitempty"`
+}
+
+type bundleVersion struct {
+	Version  string `json:"version"`
+	Revision string `json:"revision,omitempty"`
+	Go       string `json:"go"`
+}
+
+// climageVersion returns the version of this build from the build info.
+func climageVersion() bundleVersion {
+	v := bundleVersion{Version: "unknown"}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return v
+	}
+	v.Version = info.Main.Version
+	v.Go = info.GoVersion
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" {
+			v.Revision = s.Value
+		}
+	}
+	return v
+}
+
+func writeBundle(output string, id int, e history.Entry) error {
+	provider, _, _ := strings.Cut(e.Model, "/")
+	manifest := bundleManifest{
+		HistoryID: id,
+		Time:      e.Time,
+		Provider:  provider,
+		Model:     e.Model,
+		Prompt:    e.Prompt,
+		Filtered:  e.Filtered,
+		Cost:      e.Cost,
+		Climage:   climageVersion(),
+	}
+
+	tmp := output + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer os.Remove(tmp)
+	defer f.Close()
+	zw := zip.NewWriter(f)
+
+	for i, path := range e.Images {
+		img := bundleImage{Original: path}
+		if meta, err := sidecar.Read(sidecar.Path(path)); err == nil {
+			img.Seed = meta.Seed
+			img.ResponseID = meta.ResponseID
+			img.Watermark = meta.Watermark
+			if i == 0 {
+				manifest.Settings = meta.Settings
+				manifest.Size = meta.Size
+				manifest.Tiled = meta.Tiled
+			}
+			if _, err := report.AddToZip(zw, sidecar.Path(path), "images/"+filepath.Base(sidecar.Path(path))); err != nil {
+				return err
+			}
+		} else if i == 0 {
+			log.Printf("warning: no sidecar for %s, the settings and seed are unknown", path)
+		}
+		if img.SHA256, err = checksums.HashFile(path); errors.Is(err, os.ErrNotExist) {
+			log.Printf("warning: %s no longer exists", path)
+			manifest.Images = append(manifest.Images, img)
+			continue
+		} else if err != nil {
+			return err
+		}
+		img.File = "images/" + filepath.Base(path)
+		if _, err := report.AddToZip(zw, path, img.File); err != nil {
+			return err
+		}
+		manifest.Images = append(manifest.Images, img)
+	}
+
+	w, err := zw.Create("bundle.json")
+	if err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(manifest); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := os.Rename(tmp, output); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	bundleCmd.Flags().StringVarP(&bundleFlags.output, "output", "o", "", "zip file to write, climage-bundle-<id>.zip by default")
+
+	rootCmd.AddCommand(bundleCmd)
+}
@@ -0,0 +1,169 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/i18n"
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+var charactersCmd = &cobra.Command{
+	Use:   "characters",
+	Short: "Manage named characters for consistent generations",
+	Long:  `Manage a registry of named characters. A character has a description and optional reference images. Reference a character in a prompt with @name to keep it consistent across generations. Providers that support subject customization use the reference images, all others receive the description. Of the Google models only the Gemini image models take reference images, Imagen models reject characters with images.`,
+}
+
+var charactersAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add or update a character",
+	Long:  `Add a new character or update an existing one. You will be asked for a description and an optional comma separated list of reference image files.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if !isValidCharacterName(name) {
+			return i18n.Errorf("error.character_name", name)
+		}
+
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return i18n.Errorf("error.config", err)
+		}
+
+		if err := requireInteractive("characters add"); err != nil {
+			return err
+		}
+		character := cfg.Characters[name]
+		images := strings.Join(character.Images, ", ")
+		if err := newForm(cfg, huh.NewGroup(
+			huh.NewText().
+				Title(i18n.T("character.description.title")).
+				Description(i18n.T("character.description.description", name)).
+				Validate(huh.ValidateNotEmpty()).
+				Value(&character.Description),
+			huh.NewInput().
+				Title(i18n.T("character.images.title")).
+				Description(i18n.T("character.images.description")).
+				Value(&images),
+		)).Run(); err != nil {
+			return i18n.Errorf("error.character_form", err)
+		}
+
+		character.Images = nil
+		for _, imagePath := range strings.Split(images, ",") {
+			imagePath = strings.TrimSpace(imagePath)
+			if imagePath == "" {
+				continue
+			}
+			if providers.IsURL(imagePath) {
+				character.Images = append(character.Images, imagePath)
+				continue
+			}
+			absPath, err := filepath.Abs(imagePath)
+			if err != nil {
+				return i18n.Errorf("error.character_image_path", imagePath, err)
+			}
+			if _, err := os.Stat(absPath); err != nil {
+				return i18n.Errorf("error.character_image", imagePath, err)
+			}
+			character.Images = append(character.Images, absPath)
+		}
+
+		if cfg.Characters == nil {
+			cfg.Characters = make(map[string]config.Character)
+		}
+		cfg.Characters[name] = character
+		if err := cfg.Save(); err != nil {
+			return i18n.Errorf("error.save_config", err)
+		}
+		return nil
+	},
+}
+
+var charactersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all characters",
+	Long:  `List all registered characters with their description and the number of reference images.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return i18n.Errorf("error.config", err)
+		}
+		names := make([]string, 0, len(cfg.Characters))
+		for name := range cfg.Characters {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			c := cfg.Characters[name]
+			fmt.Println(i18n.T("character.list", name, len(c.Images), c.Description))
+		}
+		return nil
+	},
+}
+
+var charactersRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a character",
+	Long:  `Remove a character from the registry. Reference images on disk are not deleted.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return i18n.Errorf("error.config", err)
+		}
+		name := strings.TrimPrefix(args[0], "@")
+		if _, ok := cfg.Characters[name]; !ok {
+			return i18n.Errorf("error.character_not_found", name)
+		}
+		delete(cfg.Characters, name)
+		if err := cfg.Save(); err != nil {
+			return i18n.Errorf("error.save_config", err)
+		}
+		return nil
+	},
+}
+
+func isValidCharacterName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+func init() {
+	charactersCmd.AddCommand(charactersAddCmd)
+	charactersCmd.AddCommand(charactersListCmd)
+	charactersCmd.AddCommand(charactersRemoveCmd)
+
+	rootCmd.AddCommand(charactersCmd)
+}
@@ -0,0 +1,84 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/bloodmagesoftware/climage/checksums"
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/spf13/cobra"
+)
+
+var checksumsCmd = &cobra.Command{
+	Use:   "checksums",
+	Short: "Verify the SHA-256 manifests of the generated files",
+	Long:  `With "checksums": true in the config the SHA-256 hashes of every saved image and sidecar are appended to a SHA256SUMS file in its directory. The manifests use the format of sha256sum, so asset pipelines can check them with "sha256sum -c SHA256SUMS" or 'climage checksums verify'.`,
+}
+
+var checksumsVerifyCmd = &cobra.Command{
+	Use:   "verify [dir]...",
+	Short: "Check the generated files against their manifests",
+	Long:  `Check the files listed in the SHA256SUMS manifests in the directories and their subdirectories, the output directory by default. Modified and missing files are listed, the command fails if there are any.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dirs := args
+		if len(dirs) == 0 {
+			if _, err := config.GetConfig(); err != nil {
+				return fmt.Errorf("failed to get config: %w", err)
+			}
+			dir, err := providers.OutDir()
+			if err != nil {
+				return err
+			}
+			dirs = []string{dir}
+		}
+		ok, bad := 0, 0
+		for _, dir := range dirs {
+			manifests, err := checksums.Find(dir)
+			if err != nil {
+				return err
+			}
+			for _, manifest := range manifests {
+				results, err := checksums.Verify(manifest)
+				if err != nil {
+					return err
+				}
+				for _, r := range results {
+					if r.Status == checksums.StatusOK {
+						ok++
+						continue
+					}
+					bad++
+					fmt.Printf("%s: %s\n", r.Status, r.Path)
+				}
+			}
+		}
+		if bad > 0 {
+			return fmt.Errorf("%d files are modified or missing, %d are unchanged", bad, ok)
+		}
+		fmt.Printf("%d files are unchanged\n", ok)
+		return nil
+	},
+}
+
+func init() {
+	checksumsCmd.AddCommand(checksumsVerifyCmd)
+	rootCmd.AddCommand(checksumsCmd)
+}
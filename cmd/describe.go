@@ -0,0 +1,121 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// describeInstruction asks for a description and a prompt that
+	// reconstructs the image.
+	describeInstruction = `Describe this image in detail: the subject, composition, camera angle, lighting, colors, medium and artistic style. Then write a single prompt for a text-to-image model that would recreate it, on its own line starting with "Prompt:".`
+	// describePromptInstruction asks for the prompt only.
+	describePromptInstruction = `Write a single detailed prompt for a text-to-image model that would recreate this image, covering the subject, composition, camera angle, lighting, colors, medium and artistic style. Answer with the prompt only.`
+)
+
+var describeFlags struct {
+	model       string
+	prompt      bool
+	instruction string
+}
+
+var describeCmd = &cobra.Command{
+	Use:   "describe <image|url>",
+	Short: "Describe an image with a vision model",
+	Long: `Send an existing image file or an image at an http or https URL to a vision model and print a detailed description with a reconstructed prompt, e.g. to reverse-engineer the prompt of reference art. With --prompt only the prompt is printed, so it can be passed on to 'climage' directly.
+
+The model is --model, the "vision_model" of the config or the default vision model of the default model's provider, e.g. google-ai/gemini-2.5-flash.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+		image, err := providers.ReadInputImage(cmd.Context(), args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read image: %w", err)
+		}
+		instruction := describeInstruction
+		switch {
+		case describeFlags.instruction != "":
+			instruction = describeFlags.instruction
+		case describeFlags.prompt:
+			instruction = describePromptInstruction
+		}
+		description, err := describeImage(cmd.Context(), cfg, describeFlags.model, image, instruction)
+		if err != nil {
+			return fmt.Errorf("failed to describe image: %w", err)
+		}
+		fmt.Println(description)
+		return nil
+	},
+}
+
+func describeImage(ctx context.Context, cfg config.Config, model string, image []byte, instruction string) (string, error) {
+	if model == "" {
+		model = cfg.VisionModel
+	}
+	if model == "" {
+		defaultModel, _, err := resolveModel(cfg, cfg.DefaultModel)
+		if err != nil {
+			return "", err
+		}
+		model, _, _ = strings.Cut(defaultModel, "/")
+	}
+	providerName, modelName, _ := strings.Cut(model, "/")
+	pp, err := providers.GetProviderByName(providerName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get provider: %w", err)
+	}
+	dp, ok := pp.(providers.DescribeProvider)
+	if !ok {
+		return "", providers.NewError(providers.ErrorKindInvalidSettings, providerName, fmt.Errorf("provider %s has no vision model, set one with --model", providerName))
+	}
+	if modelName == "" {
+		modelName = dp.VisionModels()[0]
+	}
+	if !slices.Contains(dp.VisionModels(), modelName) {
+		return "", providers.NewError(providers.ErrorKindInvalidSettings, providerName, fmt.Errorf("provider %s has the vision models %s, got %q", providerName, strings.Join(dp.VisionModels(), ", "), modelName))
+	}
+	if err := checkBudget(cfg, providerName); err != nil {
+		return "", err
+	}
+	release, err := providers.Schedule(ctx, providerName)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+	return dp.DescribeImage(ctx, modelName, image, instruction)
+}
+
+func init() {
+	describeCmd.Flags().StringVarP(&describeFlags.model, "model", "m", "", "vision model or provider to describe with, e.g. google-ai/gemini-2.5-flash")
+	describeCmd.Flags().BoolVar(&describeFlags.prompt, "prompt", false, "print only a prompt that recreates the image")
+	describeCmd.Flags().StringVar(&describeFlags.instruction, "instruction", "", "ask the vision model something else about the image")
+
+	rootCmd.AddCommand(describeCmd)
+}
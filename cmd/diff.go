@@ -0,0 +1,129 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"os"
+
+	"github.com/bloodmagesoftware/climage/imagediff"
+	"github.com/bloodmagesoftware/climage/sizes"
+	"github.com/spf13/cobra"
+)
+
+var diffFlags struct {
+	heatmap string
+	json    bool
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <a> <b>",
+	Short: "Compare two images",
+	Long: `Compare two images, e.g. generations with different settings or seeds. Both images and a heatmap of their perceptual difference are shown side by side in the terminal, and the mean and largest color difference (CIE76 ΔE) and the share of noticeably changed pixels are printed. A ΔE below 2.3 is barely noticeable.
+
+If the sizes differ, the second image is cropped and scaled to the first. Save the heatmap with --heatmap.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		a, err := decodeImageFile(args[0])
+		if err != nil {
+			return err
+		}
+		b, err := decodeImageFile(args[1])
+		if err != nil {
+			return err
+		}
+		if a.Bounds().Size() != b.Bounds().Size() {
+			b = sizes.Resize(b, sizes.Size{Width: a.Bounds().Dx(), Height: a.Bounds().Dy()})
+		}
+		res := imagediff.Compare(a, b)
+
+		if diffFlags.heatmap != "" {
+			if err := writePNG(diffFlags.heatmap, res.Heatmap); err != nil {
+				return err
+			}
+		}
+		if diffFlags.json {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(res)
+		}
+
+		showPreview(imagediff.SideBySide(a, b, res.Heatmap))
+		fmt.Printf("mean ΔE:  %.2f\n", res.MeanDelta)
+		fmt.Printf("max ΔE:   %.2f\n", res.MaxDelta)
+		fmt.Printf("changed:  %.1f%% of pixels\n", res.Changed*100)
+		return nil
+	},
+}
+
+func decodeImageFile(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image: %w", err)
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return img, nil
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// showPreview shows an image that isn't saved in the terminal.
+func showPreview(img image.Image) {
+	if noColor() {
+		return
+	}
+	f, err := os.CreateTemp("", "climage-diff-*.png")
+	if err != nil {
+		log.Printf("warning: failed to create preview: %v", err)
+		return
+	}
+	defer os.Remove(f.Name())
+	err = png.Encode(f, img)
+	f.Close()
+	if err != nil {
+		log.Printf("warning: failed to write preview: %v", err)
+		return
+	}
+	showImage(f.Name())
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffFlags.heatmap, "heatmap", "", "save the difference heatmap as PNG to this file")
+	diffCmd.Flags().BoolVar(&diffFlags.json, "json", false, "print the difference as JSON")
+
+	rootCmd.AddCommand(diffCmd)
+}
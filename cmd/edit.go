@@ -0,0 +1,205 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bloodmagesoftware/climage/clipboard"
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/spf13/cobra"
+)
+
+var editFlags struct {
+	model  string
+	preset string
+	set    []string
+	images []string
+	mask   string
+	mode   string
+	size   string
+	style  string
+
+	refClipboard bool
+
+	wallpaper bool
+	iconSet   bool
+	tile      bool
+}
+
+var editCmd = &cobra.Command{
+	Use:   "edit --image <file> <prompt>",
+	Short: "Edit existing images with a prompt",
+	Long: `Edit one or more input images as described by the prompt, e.g. "make the sky stormy" or "put the cat from the first image into the second". Only models that accept input images can edit, like Gemini 2.5 Flash Image.
+
+Imagen models support mask based edit modes instead, selected with --mode:
+  inpaint          insert what the prompt describes into the masked area
+  remove           remove the content of the masked area
+  outpaint         extend the image into the masked area
+  background-swap  replace the background, the mask is optional
+  recontext        place the product of up to 3 images into the described scene
+
+Masks are images of the input's size with the area to edit in white. Input images and masks can be files or http and https URLs, which are downloaded and cached for a day. With --ref-clipboard a copied image or screenshot is used as input without saving it first.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+
+		modelName := cfg.DefaultModel
+		if editFlags.model != "" {
+			modelName = editFlags.model
+		}
+		model, modelSettings, err := resolveModel(cfg, modelName)
+		if err != nil {
+			return err
+		}
+		if editFlags.model != "" && model != editFlags.model {
+			return fmt.Errorf("model %q is not available", editFlags.model)
+		}
+		modelSettings, err = applySettingFlags(cfg, modelSettings, editFlags.preset, editFlags.set)
+		if err != nil {
+			return err
+		}
+		post, modelSettings, err := useOutputSize(postProcessing{}, modelSettings, iconSetOutputSize(editFlags.iconSet, editFlags.size))
+		if err != nil {
+			return err
+		}
+		post.tile = editFlags.tile
+		style, err := getStyle(editFlags.style)
+		if err != nil {
+			return err
+		}
+		prompt := applyStyle(style, strings.Join(args, " "))
+
+		ctx := cmd.Context()
+		req := providers.EditRequest{Prompt: prompt, Mode: editFlags.mode}
+		if editFlags.mask != "" {
+			if req.Mask, err = providers.ReadInputImage(ctx, editFlags.mask); err != nil {
+				return fmt.Errorf("failed to read mask: %w", err)
+			}
+		}
+		for _, imagePath := range editFlags.images {
+			b, err := providers.ReadInputImage(ctx, imagePath)
+			if err != nil {
+				return fmt.Errorf("failed to read input image: %w", err)
+			}
+			req.Images = append(req.Images, b)
+		}
+		if editFlags.refClipboard {
+			b, err := clipboard.Image()
+			if err != nil {
+				return err
+			}
+			req.Images = append(req.Images, b)
+		}
+		if len(req.Images) == 0 {
+			return errors.New("no input image, use --image or --ref-clipboard")
+		}
+
+		images, err := editImages(ctx, cfg, model, req, modelSettings)
+		images, err = finishImages(ctx, cfg, model, req.Prompt, modelSettings, post, images, err)
+		if err != nil {
+			return fmt.Errorf("failed to edit image: %w", err)
+		}
+		for _, img := range images {
+			printImage(img)
+			if img.Path != "" {
+				showImage(img.Path)
+			}
+		}
+		if editFlags.tile {
+			printTilePreviews(images)
+		}
+		if editFlags.iconSet {
+			dirs, err := exportIconSets(images)
+			if err != nil {
+				return err
+			}
+			for _, dir := range dirs {
+				fmt.Println(dir)
+			}
+		}
+		if editFlags.wallpaper {
+			return setWallpaper(images)
+		}
+		return nil
+	},
+}
+
+func editImages(ctx context.Context, cfg config.Config, model string, req providers.EditRequest, settings providers.ModelSettings) ([]providers.Image, error) {
+	providerName, modelName, ok := strings.Cut(model, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid model: %q", model)
+	}
+	pp, err := providers.GetProviderByName(providerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider: %w", err)
+	}
+	ep, ok := pp.(providers.EditProvider)
+	if !ok {
+		return nil, providers.NewError(providers.ErrorKindInvalidSettings, providerName, fmt.Errorf("provider %s can't edit images", providerName))
+	}
+	m, err := providers.FindModel(pp, modelName)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBudget(cfg, providerName); err != nil {
+		return nil, err
+	}
+	if err := requireOutDir(); err != nil {
+		return nil, err
+	}
+	release, err := providers.Schedule(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	req.Prompt, _ = cfg.ExpandCharacters(req.Prompt, false)
+	if err := errors.Join(m.Validate(req.Prompt, settings), m.ValidateEdit(req)); err != nil {
+		return nil, providers.NewError(providers.ErrorKindInvalidSettings, providerName, err)
+	}
+	if err := auditRequest(cfg, model, req.Prompt, settings); err != nil {
+		return nil, err
+	}
+	emitProgress(progressEvent{Event: progressSubmitted, Model: model, Prompt: req.Prompt})
+	return ep.EditImage(ctx, modelName, req, settings)
+}
+
+func init() {
+	editCmd.Flags().StringVarP(&editFlags.model, "model", "m", "", "model to edit with, e.g. google/gemini-2.5-flash-image")
+	editCmd.Flags().StringArrayVarP(&editFlags.images, "image", "i", nil, "input image file or http(s) URL, can be repeated")
+	editCmd.Flags().BoolVar(&editFlags.refClipboard, "ref-clipboard", false, "use the image in the clipboard, e.g. a screenshot, as input image after the --image files")
+	editCmd.Flags().StringVar(&editFlags.mask, "mask", "", "mask image file or http(s) URL, the area to edit is white")
+	editCmd.Flags().StringVar(&editFlags.mode, "mode", "", "edit mode: edit, inpaint, remove, outpaint, background-swap or recontext (defaults to edit)")
+	editCmd.Flags().StringVar(&editFlags.preset, "preset", "", "settings preset to apply, see /preset in the interactive session")
+	editCmd.Flags().StringArrayVar(&editFlags.set, "set", nil, "set a model setting, e.g. --set aspect_ratio=16:9, can be repeated")
+	editCmd.Flags().StringVar(&editFlags.style, "style", "", "style preset added to the prompt, e.g. watercolor, see 'climage styles'")
+	editCmd.Flags().StringVar(&editFlags.size, "size", "", "output size preset or WIDTHxHEIGHT, the image is cropped and scaled to it, see 'climage sizes'")
+	editCmd.Flags().BoolVar(&editFlags.iconSet, "icon-set", false, "also export the images as favicon and app icon set, 16 to 1024 pixel PNGs, .ico and .icns, in a directory next to each image")
+	editCmd.Flags().BoolVar(&editFlags.tile, "tile", false, "make the images seamlessly tileable and write a 2x2 tiled preview next to each")
+	editCmd.Flags().BoolVar(&editFlags.wallpaper, "set-wallpaper", false, "set the generated image as the desktop background")
+
+	rootCmd.AddCommand(editCmd)
+}
@@ -0,0 +1,89 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/history"
+	"github.com/bloodmagesoftware/climage/web"
+	"github.com/spf13/cobra"
+)
+
+var galleryExportFlags struct {
+	title string
+	since string
+	model string
+	match string
+	last  int
+}
+
+var galleryCmd = &cobra.Command{
+	Use:   "gallery",
+	Short: "Export galleries of the generation history",
+	Long:  `Export galleries of the generation history. To browse the whole history on this machine use 'climage web'.`,
+}
+
+var galleryExportCmd = &cobra.Command{
+	Use:   "export <dir>",
+	Short: "Export a static HTML gallery",
+	Long: `Export the generations to a self-contained static HTML gallery in the directory, e.g. to publish it or hand it to a client: an index.html with thumbnails and prompts, a page per generation with its metadata and copies of the images. Costs are left out. The files work without a server and without network access.
+
+All generations are exported, newest first, unless they are selected with --since, --model, --match and --last. Deleted images and generations without any remaining image are skipped.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var since time.Time
+		if galleryExportFlags.since != "" {
+			var err error
+			if since, err = time.ParseInLocation(time.DateOnly, galleryExportFlags.since, time.Local); err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+		}
+		match := strings.ToLower(galleryExportFlags.match)
+		selected := 0
+		n, err := web.Export(args[0], galleryExportFlags.title, func(e history.Entry) bool {
+			if e.Time.Before(since) ||
+				galleryExportFlags.model != "" && e.Model != galleryExportFlags.model ||
+				!strings.Contains(strings.ToLower(e.Prompt), match) ||
+				galleryExportFlags.last > 0 && selected >= galleryExportFlags.last {
+				return false
+			}
+			selected++
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("exported %d generations to %s\n", n, args[0])
+		return nil
+	},
+}
+
+func init() {
+	galleryExportCmd.Flags().StringVar(&galleryExportFlags.title, "title", "Gallery", "title of the gallery")
+	galleryExportCmd.Flags().StringVar(&galleryExportFlags.since, "since", "", "first day to export, e.g. 2025-01-01")
+	galleryExportCmd.Flags().StringVarP(&galleryExportFlags.model, "model", "m", "", "only export the generations of this model, e.g. google/imagen-4.0-generate-001")
+	galleryExportCmd.Flags().StringVar(&galleryExportFlags.match, "match", "", "only export the generations whose prompt contains this text, ignoring case")
+	galleryExportCmd.Flags().IntVar(&galleryExportFlags.last, "last", 0, "only export the latest n of the selected generations")
+
+	galleryCmd.AddCommand(galleryExportCmd)
+	rootCmd.AddCommand(galleryCmd)
+}
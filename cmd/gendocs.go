@@ -0,0 +1,84 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+var genDocsFlags struct {
+	man         bool
+	completions bool
+}
+
+var genDocsCmd = &cobra.Command{
+	Use:   "gen-docs <dir>",
+	Short: "Generate man pages and shell completions",
+	Long:  `Generate man pages and shell completion scripts into the given directory, so packages can ship them with the binary. Man pages are written to <dir>/man1, completions for bash, zsh, fish and PowerShell to <dir>/completions. Without --man or --completions both are generated.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := args[0]
+		all := !genDocsFlags.man && !genDocsFlags.completions
+
+		if all || genDocsFlags.man {
+			manDir := filepath.Join(dir, "man1")
+			if err := os.MkdirAll(manDir, 0755); err != nil {
+				return fmt.Errorf("failed to create man page dir: %w", err)
+			}
+			header := &doc.GenManHeader{Title: "CLIMAGE", Section: "1", Source: "CLImage"}
+			if err := doc.GenManTree(rootCmd, header, manDir); err != nil {
+				return fmt.Errorf("failed to generate man pages: %w", err)
+			}
+		}
+
+		if all || genDocsFlags.completions {
+			completionDir := filepath.Join(dir, "completions")
+			if err := os.MkdirAll(completionDir, 0755); err != nil {
+				return fmt.Errorf("failed to create completion dir: %w", err)
+			}
+			generators := []struct {
+				file string
+				gen  func(string) error
+			}{
+				{"climage.bash", func(p string) error { return rootCmd.GenBashCompletionFileV2(p, true) }},
+				{"_climage", rootCmd.GenZshCompletionFile},
+				{"climage.fish", func(p string) error { return rootCmd.GenFishCompletionFile(p, true) }},
+				{"climage.ps1", rootCmd.GenPowerShellCompletionFileWithDesc},
+			}
+			for _, g := range generators {
+				if err := g.gen(filepath.Join(completionDir, g.file)); err != nil {
+					return fmt.Errorf("failed to generate %s: %w", g.file, err)
+				}
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	genDocsCmd.Flags().BoolVar(&genDocsFlags.man, "man", false, "generate man pages")
+	genDocsCmd.Flags().BoolVar(&genDocsFlags.completions, "completions", false, "generate shell completions")
+
+	rootCmd.AddCommand(genDocsCmd)
+}
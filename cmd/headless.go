@@ -0,0 +1,80 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+// headless is set with --headless or CLIMAGE_HEADLESS for containers and CI:
+// prompts fail instead of waiting for input, credentials are only read from
+// the environment and --out is required to generate.
+var headless bool
+
+// outDir is the directory generated images are saved to, set with --out.
+var outDir string
+
+// setupHeadless applies --headless and --out. Called before every command.
+func setupHeadless() error {
+	if !headless {
+		if v := os.Getenv("CLIMAGE_HEADLESS"); v != "" {
+			h, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("invalid CLIMAGE_HEADLESS %q: %w", v, err)
+			}
+			headless = h
+		}
+	}
+	if outDir != "" {
+		dir, err := expandPath(outDir)
+		if err != nil {
+			return err
+		}
+		providers.OverrideOutDir(dir)
+	}
+	if headless {
+		// no colors, inline images and TUI
+		plainMode = true
+	}
+	providers.SetHeadless(headless)
+	return nil
+}
+
+// requireInteractive fails in headless mode, where nobody can answer the
+// prompts of what.
+func requireInteractive(what string) error {
+	if headless {
+		return fmt.Errorf("%s needs prompts, which are disabled in headless mode", what)
+	}
+	return nil
+}
+
+// requireOutDir fails in headless mode if --out is not set, so images aren't
+// saved to a downloads dir that doesn't exist in a container.
+func requireOutDir() error {
+	if headless && outDir == "" {
+		return errors.New("headless mode requires --out to set the directory images are saved to")
+	}
+	return nil
+}
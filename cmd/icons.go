@@ -0,0 +1,59 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bloodmagesoftware/climage/icons"
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+// iconSetSize is the output size for --icon-set without --size, the largest
+// icon.
+const iconSetSize = "1024x1024"
+
+// iconSetOutputSize returns the output size for a request, square for icon
+// sets unless a size was given.
+func iconSetOutputSize(iconSet bool, size string) string {
+	if iconSet && size == "" {
+		return iconSetSize
+	}
+	return size
+}
+
+// exportIconSets exports the icon set of every image into a directory next to
+// it, named like the image with an "_icons" suffix, and returns the
+// directories.
+func exportIconSets(images []providers.Image) ([]string, error) {
+	var dirs []string
+	for _, img := range images {
+		if img.Path == "" {
+			continue
+		}
+		dir := strings.TrimSuffix(img.Path, filepath.Ext(img.Path)) + "_icons"
+		if _, err := icons.Export(img.Path, dir); err != nil {
+			return dirs, fmt.Errorf("failed to export icon set: %w", err)
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs, nil
+}
@@ -0,0 +1,143 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/sidecar"
+	"github.com/spf13/cobra"
+)
+
+var infoFlags struct {
+	json bool
+}
+
+// fileInfo is the metadata found for a file.
+type fileInfo struct {
+	File string `json:"file"`
+	// Metadata is read from the sidecar.
+	Metadata *sidecar.Metadata `json:"metadata,omitempty"`
+	// Embedded are the text chunks of a PNG image.
+	Embedded map[string]string `json:"embedded,omitempty"`
+}
+
+var infoCmd = &cobra.Command{
+	Use:   "info <file>",
+	Short: "Show how an image was generated",
+	Long:  `Show the metadata of a generated image: prompt, model, settings, seed and generation time from its sidecar file, and the text embedded in PNG images, e.g. the parameters written by other generation tools. The path can be the image or its .json sidecar. Use --json for machine readable output.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info, err := readFileInfo(args[0])
+		if err != nil {
+			return err
+		}
+		if infoFlags.json {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(info)
+		}
+		printFileInfo(info)
+		return nil
+	},
+}
+
+// readFileInfo reads the sidecar and the embedded metadata of a file. It
+// fails if neither is found.
+func readFileInfo(path string) (fileInfo, error) {
+	info := fileInfo{File: path}
+	sidecarPath, imagePath := path, path
+	if filepath.Ext(path) == ".json" {
+		imagePath = ""
+	} else {
+		sidecarPath = sidecar.Path(path)
+	}
+	meta, err := sidecar.Read(sidecarPath)
+	switch {
+	case err == nil:
+		info.Metadata = &meta
+		if imagePath == "" && meta.Image != "" {
+			imagePath = filepath.Join(filepath.Dir(sidecarPath), meta.Image)
+		}
+	case !errors.Is(err, os.ErrNotExist) || imagePath == "":
+		return info, err
+	}
+	if imagePath != "" {
+		embedded, err := sidecar.ReadPNGText(imagePath)
+		switch {
+		case err == nil:
+			if len(embedded) > 0 {
+				info.Embedded = embedded
+			}
+		case errors.Is(err, sidecar.ErrNotPNG):
+		case info.Metadata == nil || !errors.Is(err, os.ErrNotExist):
+			return info, err
+		}
+	}
+	if info.Metadata == nil && info.Embedded == nil {
+		return info, fmt.Errorf("no metadata found for %q", path)
+	}
+	return info, nil
+}
+
+func printFileInfo(info fileInfo) {
+	fmt.Printf("file:     %s\n", info.File)
+	if m := info.Metadata; m != nil {
+		fmt.Printf("prompt:   %s\n", m.Prompt)
+		fmt.Printf("model:    %s\n", m.Model)
+		fmt.Printf("time:     %s\n", m.Time.Local().Format(time.DateTime))
+		if m.Seed != nil {
+			fmt.Printf("seed:     %d\n", *m.Seed)
+		}
+		if m.ResponseID != "" {
+			fmt.Printf("response: %s\n", m.ResponseID)
+		}
+		if m.Cost > 0 {
+			fmt.Printf("cost:     $%.2f\n", m.Cost)
+		}
+		if m.Watermark != "" {
+			fmt.Printf("watermark: %s\n", m.Watermark)
+		}
+		if len(m.Settings) > 0 {
+			fmt.Println("settings:")
+			for _, name := range slices.Sorted(maps.Keys(m.Settings)) {
+				fmt.Printf("  %s: %s\n", name, m.Settings[name])
+			}
+		}
+	}
+	if len(info.Embedded) > 0 {
+		fmt.Println("embedded:")
+		for _, key := range slices.Sorted(maps.Keys(info.Embedded)) {
+			fmt.Printf("  %s: %s\n", key, info.Embedded[key])
+		}
+	}
+}
+
+func init() {
+	infoCmd.Flags().BoolVar(&infoFlags.json, "json", false, "print the metadata as JSON")
+
+	rootCmd.AddCommand(infoCmd)
+}
@@ -0,0 +1,201 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/i18n"
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+type jobStatus uint8
+
+const (
+	jobRunning jobStatus = iota
+	jobDone
+	jobFailed
+	jobCancelled
+)
+
+func (s jobStatus) String() string {
+	switch s {
+	case jobRunning:
+		return i18n.T("job.running")
+	case jobDone:
+		return i18n.T("job.done")
+	case jobFailed:
+		return i18n.T("job.failed")
+	case jobCancelled:
+		return i18n.T("job.cancelled")
+	default:
+		return "unknown"
+	}
+}
+
+// job is a single generation request submitted from the interactive session.
+type job struct {
+	id       int
+	model    string
+	prompt   string
+	started  time.Time
+	finished time.Time
+	status   jobStatus
+	images   []providers.Image
+	err      error
+	reported bool
+	cancel   context.CancelFunc
+}
+
+// jobList runs generations in the background so the prompt stays usable
+// while a generation is in flight.
+type jobList struct {
+	mu   sync.Mutex
+	wg   sync.WaitGroup
+	jobs []*job
+}
+
+func (l *jobList) start(ctx context.Context, model string, prompt string, generate func(ctx context.Context) ([]providers.Image, error)) {
+	ctx, cancel := context.WithCancel(ctx)
+	l.mu.Lock()
+	j := &job{
+		id:      len(l.jobs) + 1,
+		model:   model,
+		prompt:  prompt,
+		started: time.Now(),
+		status:  jobRunning,
+		cancel:  cancel,
+	}
+	l.jobs = append(l.jobs, j)
+	l.mu.Unlock()
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		defer cancel()
+		images, err := generate(ctx)
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		j.finished = time.Now()
+		j.images = images
+		j.err = err
+		if errors.Is(err, context.Canceled) {
+			j.status = jobCancelled
+		} else if err != nil {
+			j.status = jobFailed
+		} else {
+			j.status = jobDone
+		}
+	}()
+}
+
+func (l *jobList) running() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := 0
+	for _, j := range l.jobs {
+		if j.status == jobRunning {
+			n++
+		}
+	}
+	return n
+}
+
+// takeFinished returns all finished jobs that were not reported yet and marks
+// them as reported.
+func (l *jobList) takeFinished() []job {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var finished []job
+	for _, j := range l.jobs {
+		if j.status != jobRunning && !j.reported {
+			j.reported = true
+			finished = append(finished, *j)
+		}
+	}
+	return finished
+}
+
+func (l *jobList) printPanel() {
+	summaries := l.summaries()
+	if len(summaries) == 0 {
+		fmt.Println(i18n.T("no_jobs"))
+		return
+	}
+	for _, s := range summaries {
+		fmt.Println(s)
+	}
+}
+
+// summaries returns the summaries of all jobs, oldest first.
+func (l *jobList) summaries() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var summaries []string
+	for _, j := range l.jobs {
+		summaries = append(summaries, j.summary())
+	}
+	return summaries
+}
+
+// cancelAll cancels all running jobs and returns how many were cancelled.
+func (l *jobList) cancelAll() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := 0
+	for _, j := range l.jobs {
+		if j.status == jobRunning {
+			j.cancel()
+			n++
+		}
+	}
+	return n
+}
+
+// cancelLatest cancels the most recently started running job and returns it.
+func (l *jobList) cancelLatest() (job, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, j := range slices.Backward(l.jobs) {
+		if j.status == jobRunning {
+			j.cancel()
+			return *j, true
+		}
+	}
+	return job{}, false
+}
+
+func (l *jobList) wait() {
+	l.wg.Wait()
+}
+
+func (j job) summary() string {
+	var elapsed time.Duration
+	if j.status == jobRunning {
+		elapsed = time.Since(j.started)
+	} else {
+		elapsed = j.finished.Sub(j.started)
+	}
+	return fmt.Sprintf("#%d %-14s %6s %s: %q", j.id, j.status, elapsed.Round(time.Second), j.model, j.prompt)
+}
@@ -0,0 +1,83 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/huh"
+)
+
+// formKeyMap returns the default key map with the configured bindings.
+func formKeyMap(k config.KeyBindings) *huh.KeyMap {
+	km := huh.NewDefaultKeyMap()
+	if len(k.Submit) > 0 {
+		submit := binding(k.Submit, "submit")
+		km.Input.Submit = submit
+		km.Text.Submit = submit
+		km.Select.Submit = submit
+		km.MultiSelect.Submit = submit
+		km.Confirm.Submit = submit
+		km.Note.Submit = submit
+		km.FilePicker.Submit = submit
+		next := binding(append([]string{"tab"}, k.Submit...), "next")
+		km.Input.Next = next
+		km.Text.Next = next
+	}
+	if len(k.NewLine) > 0 {
+		km.Text.NewLine = binding(k.NewLine, "new line")
+	}
+	if len(k.Abort) > 0 {
+		km.Quit = binding(k.Abort, "abort")
+	}
+	return km
+}
+
+// tuiKeyMap are the keys of the interactive session.
+type tuiKeyMap struct {
+	submit  key.Binding
+	newLine key.Binding
+	abort   key.Binding
+	refine  key.Binding
+}
+
+// newTUIKeyMap returns the default keys of the interactive session with the
+// configured bindings.
+func newTUIKeyMap(k config.KeyBindings) tuiKeyMap {
+	return tuiKeyMap{
+		submit:  binding(keysOr(k.Submit, "enter"), "send"),
+		newLine: binding(keysOr(k.NewLine, "alt+enter", "ctrl+j"), "new line"),
+		abort:   binding(keysOr(k.Abort, "ctrl+c"), "cancel/quit"),
+		refine:  binding(keysOr(k.Refine, "esc"), "refine"),
+	}
+}
+
+// keysOr returns the configured keys or the defaults if none are configured.
+func keysOr(keys []string, defaults ...string) []string {
+	if len(keys) > 0 {
+		return keys
+	}
+	return defaults
+}
+
+func binding(keys []string, help string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(strings.Join(keys, " / "), help))
+}
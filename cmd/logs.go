@@ -0,0 +1,191 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/logs"
+	"github.com/spf13/cobra"
+)
+
+// logFile is the log file all log output is written to, nil if it couldn't
+// be opened.
+var logFile *logs.File
+
+// openLogFile writes the log output to the log file in addition to stderr.
+func openLogFile() {
+	f, err := logs.Open()
+	if err != nil {
+		log.Printf("warning: %v", err)
+		return
+	}
+	logFile = f
+	log.SetOutput(io.MultiWriter(os.Stderr, f))
+}
+
+// closeLogFile writes the log output to stderr only and closes the log file.
+func closeLogFile() {
+	if logFile != nil {
+		log.SetOutput(os.Stderr)
+		_ = logFile.Close()
+		logFile = nil
+	}
+}
+
+// quietLogs writes the log output only to the log file, so it doesn't
+// disturb the interactive session.
+func quietLogs() {
+	if logFile != nil {
+		log.SetOutput(logFile)
+		userWarnings.Lock()
+		userWarnings.quiet = true
+		userWarnings.Unlock()
+	}
+}
+
+// userWarnings are the warnings of warnUser that the interactive session
+// hasn't shown yet, as its log output only goes to the log file.
+var userWarnings struct {
+	sync.Mutex
+	quiet   bool
+	pending []string
+}
+
+// warnUser logs a warning the user should see even in the interactive
+// session, which shows it with the results of its jobs.
+func warnUser(format string, args ...any) {
+	msg := "warning: " + fmt.Sprintf(format, args...)
+	log.Print(msg)
+	userWarnings.Lock()
+	defer userWarnings.Unlock()
+	if userWarnings.quiet {
+		userWarnings.pending = append(userWarnings.pending, msg)
+	}
+}
+
+// takeUserWarnings returns the warnings that weren't shown yet.
+func takeUserWarnings() []string {
+	userWarnings.Lock()
+	defer userWarnings.Unlock()
+	pending := userWarnings.pending
+	userWarnings.pending = nil
+	return pending
+}
+
+var logsFlags struct {
+	lines  int
+	follow bool
+	path   bool
+}
+
+// logsPollInterval is how often the log file is checked for new lines with
+// --follow.
+const logsPollInterval = 500 * time.Millisecond
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Show the log file",
+	Long:  `Show the last lines of the climage log file. Warnings and diagnostics are written to the log file, in the interactive session only there, apart from budget warnings. Use --follow to keep printing new lines and --path to print the location of the log file. The log file is rotated at 1 MiB and the last three rotated files are kept.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := logs.Path()
+		if err != nil {
+			return err
+		}
+		if logsFlags.path {
+			fmt.Println(path)
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			if !logsFlags.follow {
+				fmt.Println("no logs yet")
+				return nil
+			}
+		} else if err != nil {
+			return fmt.Errorf("failed to read log file: %w", err)
+		}
+		_, _ = os.Stdout.Write(lastLines(b, logsFlags.lines))
+		if !logsFlags.follow {
+			return nil
+		}
+
+		offset := int64(len(b))
+		for {
+			select {
+			case <-cmd.Context().Done():
+				return nil
+			case <-time.After(logsPollInterval):
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			if info.Size() < offset {
+				// the file was rotated
+				offset = 0
+			}
+			if info.Size() == offset {
+				continue
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				continue
+			}
+			n, _ := io.Copy(os.Stdout, io.NewSectionReader(f, offset, info.Size()-offset))
+			_ = f.Close()
+			offset += n
+		}
+	},
+}
+
+// lastLines returns the last n lines of b.
+func lastLines(b []byte, n int) []byte {
+	end := len(b)
+	if end > 0 && b[end-1] == '\n' {
+		end--
+	}
+	start := end
+	for i := 0; i < n && start > 0; i++ {
+		start = bytes.LastIndexByte(b[:start], '\n')
+		if start < 0 {
+			return b
+		}
+	}
+	if start < end && b[start] == '\n' {
+		start++
+	}
+	return b[start:]
+}
+
+func init() {
+	logsCmd.Flags().IntVarP(&logsFlags.lines, "lines", "n", 50, "number of lines to show")
+	logsCmd.Flags().BoolVarP(&logsFlags.follow, "follow", "f", false, "keep printing new lines")
+	logsCmd.Flags().BoolVar(&logsFlags.path, "path", false, "print the path of the log file")
+
+	rootCmd.AddCommand(logsCmd)
+}
@@ -0,0 +1,95 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/spf13/cobra"
+)
+
+var modelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "List the available models",
+	Long:  `List the models of all logged in providers, including the models found by 'climage models refresh'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+		for modelName, m := range cfg.GetModels() {
+			media := m.Media
+			if media == "" {
+				media = providers.MediaImage
+			}
+			price := "unknown"
+			if m.PricePerImage > 0 {
+				price = fmt.Sprintf("$%.3f", m.PricePerImage)
+			}
+			fmt.Printf("%-50s %-30s %-5s %s\n", modelName, m.DisplayName, media, price)
+		}
+		return nil
+	},
+}
+
+var modelsRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Discover new models from the provider APIs",
+	Long:  `Query the model lists of all logged in providers that support it and cache the models that are not built into climage, so new model versions can be used without waiting for a climage release. The cache is kept until the next refresh.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+		var errs []error
+		for _, p := range cfg.Providers {
+			pp, err := p.Get()
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if _, ok := pp.(providers.ModelLister); !ok {
+				continue
+			}
+			if updated, ok := providers.ModelsUpdated(pp); ok {
+				log.Printf("%s models were last refreshed %s", pp.GetName(), updated.Format(time.DateTime))
+			}
+			models, err := providers.RefreshModels(cmd.Context(), pp)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to refresh models of %s: %w", pp.GetName(), err))
+				continue
+			}
+			fmt.Printf("%s: %d additional models\n", pp.GetName(), len(models))
+			for _, m := range models {
+				fmt.Printf("  %s/%s\n", pp.GetName(), m.Name)
+			}
+		}
+		return errors.Join(errs...)
+	},
+}
+
+func init() {
+	modelsCmd.AddCommand(modelsRefreshCmd)
+	rootCmd.AddCommand(modelsCmd)
+}
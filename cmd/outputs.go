@@ -0,0 +1,177 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/checksums"
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/history"
+	"github.com/bloodmagesoftware/climage/notify"
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/bloodmagesoftware/climage/sidecar"
+	"github.com/bloodmagesoftware/climage/sign"
+	"github.com/bloodmagesoftware/climage/upload"
+)
+
+// processOutputs runs the configured post generation steps on the saved
+// images. Sidecars and signatures are uploaded and removed together with their
+// image.
+func processOutputs(ctx context.Context, cfg config.Config, model string, prompt string, settings providers.ModelSettings, post postProcessing, images []providers.Image) ([]providers.Image, error) {
+	if !cfg.Sidecars && !cfg.Checksums && !cfg.Signing.Enabled() && len(cfg.UploadTargets) == 0 {
+		return images, nil
+	}
+	now := time.Now()
+	size := ""
+	if post.size != nil {
+		size = post.size.String()
+	}
+	manifests := make(map[string]bool)
+	for i, img := range images {
+		if img.Path == "" {
+			continue
+		}
+		// the files that belong to the image
+		var companions []string
+		if cfg.Sidecars {
+			sidecarPath, err := sidecar.Write(img.Path, sidecar.Metadata{
+				Time:       now,
+				Model:      model,
+				Prompt:     prompt,
+				Settings:   settings.Values(),
+				Seed:       img.Seed,
+				ResponseID: img.ResponseID,
+				Cost:       modelPrice(model),
+				Size:       size,
+				Tiled:      post.tile,
+				Watermark:  img.Watermark,
+			})
+			if err != nil {
+				return images, err
+			}
+			companions = append(companions, sidecarPath)
+		}
+		keepLocal := cfg.KeepLocalFiles == nil || *cfg.KeepLocalFiles || len(cfg.UploadTargets) == 0
+		if cfg.Checksums && keepLocal {
+			if err := checksums.Update(append([]string{img.Path}, companions...)...); err != nil {
+				return images, err
+			}
+			manifests[filepath.Join(filepath.Dir(img.Path), checksums.FileName)] = true
+		}
+		if cfg.Signing.Enabled() {
+			for _, f := range append([]string{img.Path}, companions...) {
+				sigPath, err := sign.File(ctx, cfg.Signing, f)
+				if err != nil {
+					return images, err
+				}
+				companions = append(companions, sigPath)
+			}
+		}
+		if len(cfg.UploadTargets) == 0 {
+			continue
+		}
+		for _, t := range cfg.UploadTargets {
+			location, err := upload.Upload(ctx, t, img.Path)
+			if err != nil {
+				return images, err
+			}
+			images[i].Uploads = append(images[i].Uploads, location)
+			for _, f := range companions {
+				if _, err := upload.Upload(ctx, t, f); err != nil {
+					return images, err
+				}
+			}
+		}
+		if !keepLocal {
+			for _, f := range append([]string{img.Path}, companions...) {
+				if err := os.Remove(f); err != nil {
+					return images, fmt.Errorf("failed to remove local file: %w", err)
+				}
+			}
+			// the image is only available at its upload locations now
+			images[i].Path = ""
+		}
+	}
+	if cfg.Signing.Enabled() {
+		for manifest := range manifests {
+			if _, err := sign.File(ctx, cfg.Signing, manifest); err != nil {
+				return images, err
+			}
+		}
+	}
+	return images, nil
+}
+
+// notifyWebhooks posts the result to the configured webhooks. Failures are
+// only logged, the images are already saved.
+func notifyWebhooks(ctx context.Context, cfg config.Config, model string, prompt string, images []providers.Image) {
+	for _, w := range cfg.Webhooks {
+		if err := notify.Post(ctx, w, model, prompt, images); err != nil {
+			log.Printf("warning: %v", err)
+		}
+	}
+}
+
+// recordHistory adds the generation to the local history. Failures are only
+// logged.
+func recordHistory(model string, prompt string, images []providers.Image) {
+	e := history.Entry{Time: time.Now(), Model: model, Prompt: prompt}
+	for _, img := range images {
+		switch {
+		case img.Safety.Filtered:
+			e.Filtered++
+		case img.Path != "":
+			e.Images = append(e.Images, img.Path)
+		case len(img.Uploads) > 0:
+			e.Uploads = append(e.Uploads, img.Uploads[0])
+		}
+	}
+	e.Cost = modelPrice(model) * float64(len(e.Images)+len(e.Uploads))
+	if err := history.Append(e); err != nil {
+		log.Printf("warning: failed to record history: %v", err)
+	}
+}
+
+// modelPrice returns the estimated price of one image of the model, zero if
+// unknown. Upscales are recorded as the model "<provider>/upscale".
+func modelPrice(model string) float64 {
+	providerName, modelName, ok := strings.Cut(model, "/")
+	if !ok {
+		return 0
+	}
+	pp, err := providers.GetProviderByName(providerName)
+	if err != nil {
+		return 0
+	}
+	if up, ok := pp.(providers.UpscaleProvider); ok && modelName == upscaleModel {
+		return up.UpscalePrice()
+	}
+	m, err := providers.FindModel(pp, modelName)
+	if err != nil {
+		return 0
+	}
+	return m.PricePerImage
+}
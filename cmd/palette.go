@@ -0,0 +1,124 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"os"
+	"strings"
+
+	"github.com/bloodmagesoftware/climage/history"
+	"github.com/bloodmagesoftware/climage/palette"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var paletteFlags struct {
+	count int
+	json  bool
+}
+
+// paletteColor is the --json output of one color.
+type paletteColor struct {
+	Hex        string  `json:"hex"`
+	Hue        float64 `json:"hue"`
+	Saturation float64 `json:"saturation"`
+	Lightness  float64 `json:"lightness"`
+	Share      float64 `json:"share"`
+}
+
+var paletteCmd = &cobra.Command{
+	Use:   "palette <file|last>",
+	Short: "Extract the dominant colors of an image",
+	Long:  `Extract the dominant colors of an image and print them as hex and HSL with a color swatch, most common first. "last" is the latest generated image from the history.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if paletteFlags.count < 1 {
+			return errors.New("--count must be at least 1")
+		}
+		imagePath := args[0]
+		if imagePath == "last" {
+			var err error
+			if imagePath, err = lastImagePath(); err != nil {
+				return err
+			}
+		}
+		f, err := os.Open(imagePath)
+		if err != nil {
+			return fmt.Errorf("failed to open image: %w", err)
+		}
+		img, _, err := image.Decode(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to decode image: %w", err)
+		}
+
+		colors := palette.Extract(img, paletteFlags.count)
+		if paletteFlags.json {
+			out := make([]paletteColor, len(colors))
+			for i, c := range colors {
+				h, s, l := c.HSL()
+				out[i] = paletteColor{Hex: c.Hex(), Hue: h, Saturation: s, Lightness: l, Share: c.Share}
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(out)
+		}
+		for _, c := range colors {
+			h, s, l := c.HSL()
+			swatch := ""
+			if !noColor() {
+				swatch = lipgloss.NewStyle().Background(lipgloss.Color(c.Hex())).Render("      ") + "  "
+			}
+			fmt.Printf("%s%s  hsl(%3.0f, %3.0f%%, %3.0f%%)  %5.1f%%\n", swatch, c.Hex(), h, s, l, c.Share*100)
+		}
+		return nil
+	},
+}
+
+// lastImagePath returns the latest generated image from the history that
+// still exists locally.
+func lastImagePath() (string, error) {
+	entries, err := history.Read()
+	if err != nil {
+		return "", fmt.Errorf("failed to read history: %w", err)
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		images := entries[i].Images
+		for j := len(images) - 1; j >= 0; j-- {
+			if strings.Contains(images[j], "://") {
+				continue
+			}
+			if _, err := os.Stat(images[j]); err == nil {
+				return images[j], nil
+			}
+		}
+	}
+	return "", errors.New("no generated image found in the history")
+}
+
+func init() {
+	paletteCmd.Flags().IntVarP(&paletteFlags.count, "count", "n", 6, "number of colors")
+	paletteCmd.Flags().BoolVar(&paletteFlags.json, "json", false, "print the colors as JSON")
+
+	rootCmd.AddCommand(paletteCmd)
+}
@@ -0,0 +1,105 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+const (
+	progressSubmitted  = "submitted"
+	progressProgress   = "progress"
+	progressImageSaved = "image_saved"
+	progressDone       = "done"
+	progressError      = "error"
+)
+
+// progressEvent is a single line of the --progress=ndjson stream.
+type progressEvent struct {
+	Time      time.Time               `json:"time"`
+	Event     string                  `json:"event"`
+	Model     string                  `json:"model,omitempty"`
+	Prompt    string                  `json:"prompt,omitempty"`
+	Percent   *float64                `json:"percent,omitempty"`
+	Path      string                  `json:"path,omitempty"`
+	Uploads   []string                `json:"uploads,omitempty"`
+	Safety    *providers.SafetyResult `json:"safety,omitempty"`
+	Error     string                  `json:"error,omitempty"`
+	ErrorKind string                  `json:"error_kind,omitempty"`
+}
+
+var (
+	progressFormat string
+	progressMu     sync.Mutex
+)
+
+func validateProgressFormat() error {
+	switch progressFormat {
+	case "", "none", "ndjson":
+		return nil
+	default:
+		return fmt.Errorf("invalid progress format %q: expected \"ndjson\" or \"none\"", progressFormat)
+	}
+}
+
+func emitProgress(e progressEvent) {
+	if progressFormat != "ndjson" {
+		return
+	}
+	e.Time = time.Now()
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	_ = json.NewEncoder(os.Stderr).Encode(e)
+}
+
+func emitProgressPercent(done int, total int) {
+	percent := 100 * float64(done) / float64(total)
+	emitProgress(progressEvent{Event: progressProgress, Percent: &percent})
+}
+
+func emitProgressResult(model string, prompt string, images []providers.Image, err error) {
+	if err != nil {
+		emitProgress(progressEvent{
+			Event:     progressError,
+			Model:     model,
+			Prompt:    prompt,
+			Error:     err.Error(),
+			ErrorKind: providers.KindOf(err).String(),
+		})
+		return
+	}
+	for _, img := range images {
+		safety := img.Safety
+		emitProgress(progressEvent{
+			Event:   progressImageSaved,
+			Model:   model,
+			Prompt:  prompt,
+			Path:    img.Path,
+			Uploads: img.Uploads,
+			Safety:  &safety,
+		})
+	}
+	emitProgress(progressEvent{Event: progressDone, Model: model, Prompt: prompt})
+}
@@ -0,0 +1,139 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/history"
+	"github.com/bloodmagesoftware/climage/logs"
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/bloodmagesoftware/climage/sidecar"
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+var purgeFlags struct {
+	images bool
+	yes    bool
+}
+
+var purgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Delete all data climage stored on this machine",
+	Long: `Delete everything climage stored on this machine, e.g. before handing it over or to erase personal data: the credentials in the OS keyring and the secrets file, the config, the history, queue, schedule and audit log, the caches and the logs. With --images the generated images and videos in the history and their sidecars are deleted as well, other files in the output directory are kept.
+
+The files are listed and must be confirmed before anything is deleted, unless --yes is set. Uploaded and shared copies are not deleted.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// the config may be broken, which is a reason to purge it
+		cfg, _ := config.GetConfig()
+
+		var dirs []string
+		if dir, err := config.Dir(); err == nil {
+			dirs = append(dirs, dir)
+		}
+		if dir, err := os.UserCacheDir(); err == nil {
+			dirs = append(dirs, filepath.Join(dir, "climage"))
+		}
+		if dir, err := logs.Dir(); err == nil {
+			dirs = append(dirs, dir)
+		}
+		var files []string
+		if purgeFlags.images {
+			entries, err := history.Read()
+			if err != nil {
+				return err
+			}
+			for _, e := range entries {
+				for _, img := range e.Images {
+					files = append(files, img, sidecar.Path(img))
+				}
+			}
+		}
+
+		fmt.Println("credentials of climage in the OS keyring")
+		for _, dir := range dirs {
+			fmt.Println(dir)
+		}
+		if purgeFlags.images {
+			fmt.Printf("%d generated files and their sidecars\n", len(files)/2)
+		}
+		if !purgeFlags.yes {
+			if headless {
+				return errors.New("purge needs a confirmation, use --yes in headless mode")
+			}
+			confirmed := false
+			if err := newForm(cfg, huh.NewGroup(
+				huh.NewConfirm().
+					Title("Delete all of the above?").
+					Description("This can't be undone.").
+					Value(&confirmed),
+			)).Run(); err != nil {
+				return fmt.Errorf("failed to run confirmation: %w", err)
+			}
+			if !confirmed {
+				return errors.New("purge cancelled")
+			}
+		}
+
+		var errs []error
+		if err := providers.PurgeSecrets(); err != nil {
+			errs = append(errs, err)
+		}
+		// the images first, their list is in the history
+		removed := 0
+		for _, f := range files {
+			err := os.Remove(f)
+			switch {
+			case err == nil:
+				removed++
+			case !errors.Is(err, os.ErrNotExist):
+				errs = append(errs, fmt.Errorf("failed to delete %s: %w", f, err))
+			}
+		}
+		// an open log file can't be deleted on Windows and would get the
+		// warnings of the purge on other systems
+		closeLogFile()
+		for _, dir := range dirs {
+			if err := os.RemoveAll(dir); err != nil {
+				errs = append(errs, fmt.Errorf("failed to delete %s: %w", dir, err))
+			}
+		}
+		if purgeFlags.images {
+			fmt.Printf("deleted %d files\n", removed)
+		}
+		if err := errors.Join(errs...); err != nil {
+			return err
+		}
+		fmt.Println("all climage data deleted")
+		return nil
+	},
+}
+
+func init() {
+	purgeCmd.Flags().BoolVar(&purgeFlags.images, "images", false, "also delete the generated images and videos recorded in the history and their sidecars")
+	purgeCmd.Flags().BoolVarP(&purgeFlags.yes, "yes", "y", false, "don't ask for confirmation")
+
+	rootCmd.AddCommand(purgeCmd)
+}
@@ -0,0 +1,248 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/bloodmagesoftware/climage/queue"
+	"github.com/spf13/cobra"
+)
+
+var queueAddFlags struct {
+	model  string
+	preset string
+	set    []string
+	size   string
+	style  string
+
+	priority int
+}
+
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Queue prompts to generate later",
+	Long:  `Queue prompts while offline or while a provider is down and generate them later with 'climage queue run'. The queue is kept in the data dir and survives restarts. Without a subcommand the queued prompts are listed.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return queueListCmd.RunE(cmd, args)
+	},
+}
+
+var queueAddCmd = &cobra.Command{
+	Use:   "add <prompt>",
+	Short: "Add a prompt to the queue",
+	Long:  `Add a prompt to the queue. The model and the settings from --preset and --set are checked now and used when the queue is run, the default model at that time is used without --model. Prompts with a higher --priority are generated first, prompts of the same priority in the order they were added.`,
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+		modelName := cfg.DefaultModel
+		if queueAddFlags.model != "" {
+			modelName = queueAddFlags.model
+		}
+		model, original, err := resolveModel(cfg, modelName)
+		if err != nil {
+			return err
+		}
+		if queueAddFlags.model != "" && model != queueAddFlags.model {
+			return fmt.Errorf("model %q is not available", queueAddFlags.model)
+		}
+		settings, err := applySettingFlags(cfg, original, queueAddFlags.preset, queueAddFlags.set)
+		if err != nil {
+			return err
+		}
+		if _, _, err := useOutputSize(postProcessing{}, settings, queueAddFlags.size); err != nil {
+			return err
+		}
+		style, err := getStyle(queueAddFlags.style)
+		if err != nil {
+			return err
+		}
+		prompt := applyStyle(style, strings.Join(args, " "))
+		item := queue.Item{
+			Added:    time.Now(),
+			Prompt:   prompt,
+			Model:    queueAddFlags.model,
+			Size:     queueAddFlags.size,
+			Priority: queueAddFlags.priority,
+		}
+		if values := changedSettingValues(original, settings); len(values) > 0 {
+			item.Settings = values
+		}
+		if err := queue.Add(item); err != nil {
+			return err
+		}
+		items, err := queue.Read()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("queued, %d prompts waiting\n", len(items))
+		return nil
+	},
+}
+
+var queueListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the queued prompts",
+	Long:  `List the queued prompts in the order they are generated, with their number, the time they were added, their model and their priority if it isn't 0.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		items, err := queue.Read()
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			fmt.Println("the queue is empty")
+			return nil
+		}
+		for i, item := range items {
+			model := item.Model
+			if model == "" {
+				model = "default model"
+			}
+			priority := ""
+			if item.Priority != 0 {
+				priority = fmt.Sprintf(" (priority %d)", item.Priority)
+			}
+			fmt.Printf("%3d  %s  %-40s %q%s\n", i+1, item.Added.Local().Format(time.DateTime), model, item.Prompt, priority)
+		}
+		return nil
+	},
+}
+
+var queueCancelCmd = &cobra.Command{
+	Use:     "cancel <number>",
+	Aliases: []string{"remove"},
+	Short:   "Remove a prompt from the queue",
+	Long:    `Remove the prompt with the number shown by 'climage queue list' from the queue without generating it.`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid number %q", args[0])
+		}
+		item, err := queue.Remove(n - 1)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("cancelled %q\n", item.Prompt)
+		return nil
+	},
+}
+
+var queueRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Generate the queued prompts",
+	Long:  `Generate the queued prompts in order of their priority. Generated prompts are removed from the queue, failed prompts stay queued. Prompts cancelled during the run are skipped. The run stops at the first network error or provider outage, as the remaining prompts would fail as well.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+		items, err := queue.Read()
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			fmt.Println("the queue is empty")
+			return nil
+		}
+
+		var failed []queue.Item
+		var errs []error
+		for i, item := range items {
+			// the queue may have changed since it was read
+			if queued, err := queue.Queued(item); err != nil {
+				return err
+			} else if !queued {
+				fmt.Printf("skipping %q, it is no longer queued\n", item.Prompt)
+				continue
+			}
+			images, err := runQueueItem(cmd, cfg, item)
+			if err != nil {
+				failed = append(failed, item)
+				kind := providers.KindOf(err)
+				if kind == providers.ErrorKindNetwork || kind == providers.ErrorKindOutage || cmd.Context().Err() != nil {
+					// the prompts that were not tried stay queued as well
+					failed = append(failed, items[i+1:]...)
+					errs = append(errs, err)
+					break
+				}
+				log.Printf("warning: %q stays queued: %v", item.Prompt, err)
+				errs = append(errs, err)
+				continue
+			}
+			for _, img := range images {
+				printImage(img)
+			}
+			// remove the prompt right away so an interrupted run doesn't
+			// repeat it, prompts added meanwhile are kept
+			if err := queue.Done(item); err != nil {
+				return err
+			}
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("%d prompts stay queued: %w", len(failed), errors.Join(errs...))
+		}
+		return nil
+	},
+}
+
+func runQueueItem(cmd *cobra.Command, cfg config.Config, item queue.Item) ([]providers.Image, error) {
+	modelName := item.Model
+	if modelName == "" {
+		modelName = cfg.DefaultModel
+	}
+	model, settings, err := resolveModel(cfg, modelName)
+	if err != nil {
+		return nil, err
+	}
+	if model != modelName {
+		return nil, fmt.Errorf("model %q is not available", modelName)
+	}
+	fmt.Printf("%s: %q\n", model, item.Prompt)
+	post, settings, err := useOutputSize(postProcessing{}, applySettingValues(settings, item.Settings), item.Size)
+	if err != nil {
+		return nil, err
+	}
+	return generate(cmd.Context(), cfg, model, item.Prompt, settings, post)
+}
+
+func init() {
+	queueAddCmd.Flags().StringVarP(&queueAddFlags.model, "model", "m", "", "model to generate with (defaults to the default model when the queue is run)")
+	queueAddCmd.Flags().StringVar(&queueAddFlags.preset, "preset", "", "settings preset to apply, see /preset in the interactive session")
+	queueAddCmd.Flags().StringVar(&queueAddFlags.style, "style", "", "style preset added to the prompt, e.g. watercolor, see 'climage styles'")
+	queueAddCmd.Flags().StringVar(&queueAddFlags.size, "size", "", "output size preset or WIDTHxHEIGHT, the image is cropped and scaled to it, see 'climage sizes'")
+	queueAddCmd.Flags().StringArrayVar(&queueAddFlags.set, "set", nil, "set a model setting, e.g. --set aspect_ratio=16:9, can be repeated")
+	queueAddCmd.Flags().IntVar(&queueAddFlags.priority, "priority", 0, "prompts with a higher priority are generated first, can be negative")
+
+	queueCmd.AddCommand(queueAddCmd, queueListCmd, queueCancelCmd, queueRunCmd)
+	rootCmd.AddCommand(queueCmd)
+}
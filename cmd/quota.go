@@ -0,0 +1,132 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/history"
+	"github.com/bloodmagesoftware/climage/i18n"
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/spf13/cobra"
+)
+
+var quotaCmd = &cobra.Command{
+	Use:   "quota",
+	Short: "Show the remaining budgets and quotas of the providers",
+	Long:  `Show for every configured provider what is left of its daily and monthly budget, its rate limits and the credits or quota the provider reports, if it exposes them. Allowances that are nearly used up are marked. The same is shown by /quota in the interactive session.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return i18n.Errorf("error.config", err)
+		}
+		printQuota(cmd.Context(), cmd.OutOrStdout(), cfg)
+		return nil
+	},
+}
+
+// printQuota prints the budgets, rate limits and provider quotas of the
+// configured providers.
+func printQuota(ctx context.Context, w io.Writer, cfg config.Config) {
+	if len(cfg.Providers) == 0 {
+		fmt.Fprintln(w, i18n.T("quota.no_providers"))
+		return
+	}
+	now := time.Now()
+	for _, p := range cfg.Providers {
+		fmt.Fprintln(w, p.Name)
+
+		periods := budgetPeriods(cfg.Budgets[p.Name], now)
+		if len(periods) == 0 {
+			fmt.Fprintln(w, "  "+i18n.T("quota.no_budget"))
+		}
+		for _, period := range periods {
+			spend, err := history.Spend(p.Name, period.since)
+			if err != nil {
+				fmt.Fprintln(w, "  "+i18n.T("quota.failed", err))
+				continue
+			}
+			left := period.limit - spend
+			fmt.Fprintln(w, "  "+i18n.T("quota.budget."+period.name, max(left, 0), period.limit, spend)+quotaMarker(left, period.limit))
+		}
+
+		var limits []string
+		if n := p.Limits.RequestsPerMinute; n > 0 {
+			limits = append(limits, i18n.T("quota.per_minute", n))
+		}
+		if n := p.Limits.MaxInFlight; n > 0 {
+			limits = append(limits, i18n.T("quota.in_flight", n))
+		}
+		if len(limits) > 0 {
+			fmt.Fprintln(w, "  "+i18n.T("quota.limits", strings.Join(limits, ", ")))
+		}
+
+		pp, err := p.Get()
+		if err != nil {
+			continue
+		}
+		qp, ok := pp.(providers.QuotaProvider)
+		if !ok {
+			continue
+		}
+		quotas, err := qp.Quota(ctx)
+		if err != nil {
+			fmt.Fprintln(w, "  "+i18n.T("quota.failed", err))
+			continue
+		}
+		for _, q := range quotas {
+			line := i18n.T("quota.remaining", q.Name, formatAmount(q.Remaining), q.Unit)
+			if q.Limit > 0 {
+				line = i18n.T("quota.remaining_of", q.Name, formatAmount(q.Remaining), formatAmount(q.Limit), q.Unit)
+			}
+			if !q.Reset.IsZero() {
+				line += " " + i18n.T("quota.reset", q.Reset.Local().Format(time.DateTime))
+			}
+			fmt.Fprintln(w, "  "+line+quotaMarker(q.Remaining, q.Limit))
+		}
+	}
+}
+
+// quotaMarker marks allowances that are used up or nearly, at the same ratio
+// generations warn at.
+func quotaMarker(left float64, limit float64) string {
+	switch {
+	case limit > 0 && left <= 0:
+		return " " + i18n.T("quota.used_up")
+	case limit > 0 && left <= (1-budgetWarnRatio)*limit:
+		return " " + i18n.T("quota.low")
+	}
+	return ""
+}
+
+// formatAmount formats credits without needless decimals.
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func init() {
+	rootCmd.AddCommand(quotaCmd)
+}
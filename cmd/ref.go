@@ -0,0 +1,82 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/bloodmagesoftware/climage/clipboard"
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/i18n"
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+// readReference reads a reference image from a file, a URL or, for
+// "clipboard", from the system clipboard.
+func readReference(ctx context.Context, arg string) ([]byte, error) {
+	if arg == "clipboard" {
+		return clipboard.Image()
+	}
+	b, err := providers.ReadInputImage(ctx, arg)
+	if err != nil {
+		return nil, i18n.Errorf("error.read_file", err)
+	}
+	if !strings.HasPrefix(http.DetectContentType(b), "image/") {
+		return nil, i18n.Errorf("error.not_image", arg)
+	}
+	return b, nil
+}
+
+// runRefCommand adds a reference image of the interactive session, clears
+// them or shows how many are used.
+func runRefCommand(ctx context.Context, w io.Writer, refs *[][]byte, arg string) {
+	switch arg = strings.TrimSpace(arg); arg {
+	case "":
+		if len(*refs) == 0 {
+			fmt.Fprintln(w, i18n.T("ref.none"))
+		} else {
+			fmt.Fprintln(w, i18n.T("ref.count", len(*refs)))
+		}
+	case "clear":
+		*refs = nil
+		fmt.Fprintln(w, i18n.T("ref.cleared"))
+	default:
+		b, err := readReference(ctx, arg)
+		if err != nil {
+			fmt.Fprintln(w, i18n.T("ref.failed", err))
+			return
+		}
+		*refs = append(*refs, b)
+		fmt.Fprintln(w, i18n.T("ref.added", len(*refs)))
+	}
+}
+
+// generateWithReferences generates the prompt, or edits the reference images
+// with it if there are any.
+func generateWithReferences(ctx context.Context, cfg config.Config, model string, prompt string, settings providers.ModelSettings, refs [][]byte) ([]providers.Image, error) {
+	if len(refs) == 0 {
+		return generate(ctx, cfg, model, prompt, settings, postProcessing{})
+	}
+	images, err := editImages(ctx, cfg, model, providers.EditRequest{Prompt: prompt, Images: refs}, settings)
+	return finishImages(ctx, cfg, model, prompt, settings, postProcessing{}, images, err)
+}
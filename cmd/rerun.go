@@ -0,0 +1,144 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/sidecar"
+	"github.com/spf13/cobra"
+)
+
+var rerunFlags struct {
+	model     string
+	preset    string
+	set       []string
+	newSeed   bool
+	size      string
+	wallpaper bool
+	iconSet   bool
+	tile      bool
+}
+
+var rerunCmd = &cobra.Command{
+	Use:   "rerun <image-or-sidecar>",
+	Short: "Generate again from a sidecar file",
+	Long:  `Replay a generation from its sidecar metadata file with the same prompt, model and settings. The path can be the .json sidecar or the image next to it, sidecars are written if "sidecars" is enabled in the config. The recorded seed is reused unless --new-seed is set. Settings can be overridden with --preset and --set and the model with --model.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+		sidecarPath := args[0]
+		if filepath.Ext(sidecarPath) != ".json" {
+			sidecarPath = sidecar.Path(sidecarPath)
+		}
+		meta, err := sidecar.Read(sidecarPath)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("no sidecar found for %q, enable \"sidecars\" in the config to record generations", args[0])
+			}
+			return err
+		}
+
+		modelName := meta.Model
+		if rerunFlags.model != "" {
+			modelName = rerunFlags.model
+		}
+		model, settings, err := resolveModel(cfg, modelName)
+		if err != nil {
+			return err
+		}
+		if model != modelName {
+			return fmt.Errorf("model %q is not available", modelName)
+		}
+		settings = applySettingValues(settings, sidecarSettingValues(meta, rerunFlags.newSeed))
+		settings, err = applySettingFlags(cfg, settings, rerunFlags.preset, rerunFlags.set)
+		if err != nil {
+			return err
+		}
+		size := meta.Size
+		if rerunFlags.size != "" {
+			size = rerunFlags.size
+		}
+		post, settings, err := useOutputSize(postProcessing{}, settings, iconSetOutputSize(rerunFlags.iconSet, size))
+		if err != nil {
+			return err
+		}
+		tile := meta.Tiled || rerunFlags.tile
+		post.tile = tile
+
+		images, err := generate(cmd.Context(), cfg, model, meta.Prompt, settings, post)
+		if err != nil {
+			return fmt.Errorf("failed to generate image: %w", err)
+		}
+		for _, img := range images {
+			printImage(img)
+		}
+		if tile {
+			printTilePreviews(images)
+		}
+		if rerunFlags.iconSet {
+			dirs, err := exportIconSets(images)
+			if err != nil {
+				return err
+			}
+			for _, dir := range dirs {
+				fmt.Println(dir)
+			}
+		}
+		if rerunFlags.wallpaper {
+			return setWallpaper(images)
+		}
+		return nil
+	},
+}
+
+// sidecarSettingValues returns the recorded setting values of a sidecar,
+// including the recorded seed unless newSeed is set.
+func sidecarSettingValues(meta sidecar.Metadata, newSeed bool) map[string]string {
+	values := maps.Clone(meta.Settings)
+	if meta.Seed != nil && !newSeed {
+		if values == nil {
+			values = make(map[string]string)
+		}
+		values["seed"] = strconv.FormatInt(*meta.Seed, 10)
+	}
+	return values
+}
+
+func init() {
+	rerunCmd.Flags().StringVarP(&rerunFlags.model, "model", "m", "", "model to generate with instead of the recorded one")
+	rerunCmd.Flags().StringVar(&rerunFlags.preset, "preset", "", "settings preset to apply on top of the recorded settings")
+	rerunCmd.Flags().StringArrayVar(&rerunFlags.set, "set", nil, "override a model setting, e.g. --set aspect_ratio=16:9, can be repeated")
+	rerunCmd.Flags().BoolVar(&rerunFlags.newSeed, "new-seed", false, "don't reuse the recorded seed")
+	rerunCmd.Flags().StringVar(&rerunFlags.size, "size", "", "output size instead of the recorded one, see 'climage sizes'")
+	rerunCmd.Flags().BoolVar(&rerunFlags.iconSet, "icon-set", false, "also export the images as favicon and app icon set, 16 to 1024 pixel PNGs, .ico and .icns, in a directory next to each image")
+	rerunCmd.Flags().BoolVar(&rerunFlags.tile, "tile", false, "make the images seamlessly tileable and write a 2x2 tiled preview next to each")
+	rerunCmd.Flags().BoolVar(&rerunFlags.wallpaper, "set-wallpaper", false, "set the generated image as the desktop background")
+
+	rootCmd.AddCommand(rerunCmd)
+}
@@ -19,19 +19,29 @@ along with this program.  If not, see <https://www.gnu.org/licenses/>.
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"image"
 	_ "image/gif"
 	_ "image/jpeg"
 	_ "image/png"
+	"io"
 	"log"
 	"os"
 	"os/exec"
+	"os/signal"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/i18n"
 	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/bloodmagesoftware/climage/sizes"
+	"github.com/bloodmagesoftware/climage/styles"
+	"github.com/bloodmagesoftware/climage/upload"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
 )
@@ -41,80 +51,137 @@ var rootCmd = &cobra.Command{
 	Short: "Generate images from text prompts using AI",
 	Long:  `CLImage is a command-line tool for generating images from text prompts using various AI providers. Run without arguments to start an interactive session where you can enter prompts, switch models, adjust settings, and view generated images.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireInteractive("climage without a command"); err != nil {
+			return err
+		}
 		cfg, err := config.GetConfig()
 		if err != nil {
-			return fmt.Errorf("failed to get config: %w", err)
+			return i18n.Errorf("error.config", err)
+		}
+		if isFirstRun(cfg) {
+			if cfg, err = runSetup(cmd.Context()); err != nil {
+				return err
+			}
 		}
 
+		quietLogs()
 		errExit := errors.New("exit")
 
 		prompt := ""
 		lastPrompt := ""
-		model := cfg.DefaultModel
-		var modelSettings providers.ModelSettings
-
-		// check if default model is valid
-		{
-			found := false
-			for modelName, pm := range cfg.GetModels() {
-				if model == modelName {
-					found = true
-					modelSettings = pm.Settings
-					break
-				}
+		var lastImages []providers.Image
+		var refs [][]byte
+		var style styles.Style
+		model, modelSettings, err := resolveModel(cfg, cfg.DefaultModel)
+		if err != nil {
+			return err
+		}
+		// Models of one provider may share their settings schema, so every
+		// model gets its own copy to remember its values during the session.
+		modelSettings = modelSettings.Clone()
+		settingsByModel := map[string]providers.ModelSettings{model: modelSettings}
+
+		var jobs jobList
+		// interrupts are handled per job, see below
+		jobsCtx := context.WithoutCancel(cmd.Context())
+
+		// An interrupt cancels the running generations instead of killing
+		// the interactive session.
+		interrupts := make(chan os.Signal, 1)
+		signal.Notify(interrupts, os.Interrupt)
+		defer signal.Stop(interrupts)
+		go func() {
+			for range interrupts {
+				jobs.cancelAll()
 			}
-			// if not found, set to first provider's default model
-			if !found {
-				model = ""
-				for modelName, pm := range cfg.GetModels() {
-					model = modelName
-					modelSettings = pm.Settings
-					break
+		}()
+
+		run := func() error {
+			for _, w := range takeUserWarnings() {
+				fmt.Println(w)
+			}
+			for _, j := range jobs.takeFinished() {
+				fmt.Println(j.summary())
+				if j.status == jobCancelled {
+					continue
+				}
+				if j.err != nil {
+					fmt.Println(i18n.T("generate_failed", j.err))
+					continue
 				}
+				for _, img := range j.images {
+					printImage(img)
+					showMedia(img)
+				}
+				lastImages = j.images
 			}
-		}
-		if model == "" {
-			return fmt.Errorf("no model is available")
-		}
 
-		run := func() error {
-			if err := huh.NewForm(huh.NewGroup(
+			description := i18n.T("prompt.description", model)
+			if n := jobs.running(); n > 0 {
+				description += " " + i18n.T("prompt.jobs_running", n)
+			}
+			if err := newForm(cfg, huh.NewGroup(
 				huh.NewText().
-					Title("Prompt").
-					Description("Enter your prompt for " + model + ".").
+					Title(i18n.T("prompt.title")).
+					Description(description).
 					Validate(huh.ValidateNotEmpty()).
 					Value(&prompt),
 			)).Run(); err != nil {
-				return fmt.Errorf("failed to run prompt form: %w", err)
+				return i18n.Errorf("error.prompt_form", err)
 			}
 
-			switch prompt {
+			command, commandArg, _ := strings.Cut(strings.TrimSpace(prompt), " ")
+			switch command {
 			case "/models":
 				var modelOptions []huh.Option[string]
 				for modelName, model := range cfg.GetModels() {
 					modelOptions = append(modelOptions, huh.NewOption(model.DisplayName, modelName))
 				}
-				if err := huh.NewForm(huh.NewGroup(
+				if err := newForm(cfg, huh.NewGroup(
 					huh.NewSelect[string]().
-						Title("Model").
-						Description("Select a model to generate an image with.").
+						Title(i18n.T("model.title")).
+						Description(i18n.T("model.description")).
 						Options(modelOptions...).
 						Value(&model),
 				)).Run(); err != nil {
-					return fmt.Errorf("failed to run model form: %w", err)
+					return i18n.Errorf("error.model_form", err)
 				}
-				// update model settings
-				for modelName, model := range cfg.GetModels() {
-					if model.Name == modelName {
-						modelSettings = model.Settings
+				modelSettings = sessionSettings(cfg, settingsByModel, model)
+
+			case "/settings":
+				if err := newForm(cfg, modelSettings.HuhGroups()...).Run(); err != nil {
+					return i18n.Errorf("error.settings_form", err)
+				}
+
+			case "/jobs":
+				jobs.printPanel()
+
+			case "/preset":
+				if err := runPresetCommand(os.Stdout, &cfg, commandArg, modelSettings); err != nil {
+					return err
+				}
+
+			case "/quota":
+				printQuota(cmd.Context(), os.Stdout, cfg)
+
+			case "/style":
+				runStyleCommand(os.Stdout, &style, commandArg)
+
+			case "/ref":
+				runRefCommand(cmd.Context(), os.Stdout, &refs, commandArg)
+
+			case "/share":
+				var expires time.Duration
+				if commandArg != "" {
+					if expires, err = time.ParseDuration(strings.TrimSpace(commandArg)); err != nil {
+						fmt.Println(i18n.T("invalid_expiry", err))
 						break
 					}
 				}
+				shareLastImages(cmd.Context(), os.Stdout, cfg, lastImages, expires)
 
-			case "/settings":
-				if err := huh.NewForm(modelSettings.HuhGroup()).Run(); err != nil {
-					return fmt.Errorf("failed to run settings form: %w", err)
-				}
+			case "/help":
+				printHelp(os.Stdout)
 
 			case "/exit":
 				return errExit
@@ -125,74 +192,429 @@ var rootCmd = &cobra.Command{
 
 			default:
 				if strings.HasPrefix(prompt, "/") {
-					fmt.Printf("invalid command: %q\n", prompt)
+					fmt.Println(i18n.T("invalid_command", prompt))
 					break
 				}
-				modelParts := strings.SplitN(model, "/", 2)
-				if len(modelParts) != 2 {
-					return fmt.Errorf("invalid model: %q", model)
-				}
-				providerName := modelParts[0]
-				modelName := modelParts[1]
-
-				pp, err := providers.GetProviderByName(providerName)
-				if err != nil {
-					return fmt.Errorf("failed to get provider: %w", err)
-				}
-				out, err := pp.GenerateImage(cmd.Context(), modelName, prompt, modelSettings)
-				if err != nil {
-					return fmt.Errorf("failed to generate image: %w", err)
-				}
+				generatePrompt, generateSettings := applyStyle(style, prompt), modelSettings.Clone()
+				generateModel := model
+				generateRefs := slices.Clone(refs)
 				lastPrompt = prompt
 				log.Println(prompt)
-				for _, filePath := range out {
-					fmt.Println(filePath)
-					b := bounds(filePath)
-					var cmd *exec.Cmd
-					width := b.Dx()
-					height := b.Dy()
-					if width > height {
-						cmd = exec.Command("viu", "--width", "80", filePath)
-					} else {
-						cmd = exec.Command("viu", "--height", "25", filePath)
-					}
-					cmd.Stdin = os.Stdin
-					cmd.Stdout = os.Stdout
-					cmd.Stderr = os.Stderr
-					_ = cmd.Run()
-				}
+				jobs.start(jobsCtx, generateModel, prompt, func(ctx context.Context) ([]providers.Image, error) {
+					return generateWithReferences(ctx, cfg, generateModel, generatePrompt, generateSettings, generateRefs)
+				})
 			}
 
 			return nil
 		}
 
-		for {
-			if err := run(); err != nil {
-				if errors.Is(err, errExit) {
-					break
-				}
-				// is huh user abort
-				if errors.Is(err, huh.ErrUserAborted) {
-					break
+		// The full screen session needs a terminal, the line based prompts
+		// stay for --plain, screen readers and pipes.
+		if !plainMode && isTerminal() {
+			m := newTUIModel(cmd.Context(), jobsCtx, cfg, &jobs, model, settingsByModel)
+			if _, err := tea.NewProgram(m, tea.WithAltScreen()).Run(); err != nil {
+				return i18n.Errorf("error.tui", err)
+			}
+		} else {
+			for {
+				if err := run(); err != nil {
+					if errors.Is(err, errExit) {
+						break
+					}
+					// is huh user abort
+					if errors.Is(err, huh.ErrUserAborted) {
+						// the first abort only cancels running generations
+						if n := jobs.cancelAll(); n > 0 {
+							fmt.Println(i18n.T("jobs_cancelled", n))
+							prompt = ""
+							continue
+						}
+						break
+					}
+					return err
 				}
-				return err
+				prompt = ""
+			}
+		}
+
+		if n := jobs.running(); n > 0 {
+			fmt.Println(i18n.T("jobs_waiting", n))
+		}
+		jobs.wait()
+		for _, j := range jobs.takeFinished() {
+			fmt.Println(j.summary())
+			for _, img := range j.images {
+				printImage(img)
 			}
-			prompt = ""
 		}
 
 		return nil
 	},
 }
 
+// sessionSettings returns the settings of the model in an interactive
+// session, a copy of the model's settings the first time.
+func sessionSettings(cfg config.Config, settingsByModel map[string]providers.ModelSettings, model string) providers.ModelSettings {
+	if settings, ok := settingsByModel[model]; ok {
+		return settings
+	}
+	for modelName, pm := range cfg.GetModels() {
+		if model == modelName {
+			settingsByModel[model] = pm.Settings.Clone()
+			return settingsByModel[model]
+		}
+	}
+	return nil
+}
+
+// resolveModel returns the model with the given name and its settings. If the
+// model is not available, the first available model is used instead.
+func resolveModel(cfg config.Config, model string) (string, providers.ModelSettings, error) {
+	for modelName, pm := range cfg.GetModels() {
+		if model == modelName {
+			return modelName, pm.Settings, nil
+		}
+	}
+	for modelName, pm := range cfg.GetModels() {
+		return modelName, pm.Settings, nil
+	}
+	return "", nil, i18n.Errorf("error.no_model")
+}
+
+func generate(ctx context.Context, cfg config.Config, model string, prompt string, settings providers.ModelSettings, post postProcessing) ([]providers.Image, error) {
+	images, err := generateImages(ctx, cfg, model, prompt, settings)
+	for _, fallback := range cfg.Fallbacks[model] {
+		if kind := providers.KindOf(err); (kind != providers.ErrorKindQuota && kind != providers.ErrorKindOutage) || ctx.Err() != nil {
+			break
+		}
+		fallbackSettings, ok := fallbackSettings(cfg, model, settings, fallback)
+		if !ok {
+			log.Printf("warning: ignoring fallback %s of %s, the model is not available", fallback, model)
+			continue
+		}
+		log.Printf("warning: %s failed, falling back to %s: %v", model, fallback, err)
+		images, err = generateImages(ctx, cfg, fallback, prompt, fallbackSettings)
+		if err == nil {
+			model, settings = fallback, fallbackSettings
+		}
+	}
+	return finishImages(ctx, cfg, model, prompt, settings, post, images, err)
+}
+
+// fallbackSettings returns the settings of the fallback model with the values
+// changed from the defaults of model applied, as far as the fallback has the
+// same settings.
+func fallbackSettings(cfg config.Config, model string, settings providers.ModelSettings, fallback string) (providers.ModelSettings, bool) {
+	var original, fallbackOriginal providers.ModelSettings
+	found := false
+	for modelName, pm := range cfg.GetModels() {
+		if modelName == model {
+			original = pm.Settings
+		}
+		if modelName == fallback {
+			fallbackOriginal = pm.Settings
+			found = true
+		}
+	}
+	if !found {
+		return nil, false
+	}
+	if len(original) != len(settings) {
+		return fallbackOriginal.Clone(), true
+	}
+	return applySettingValues(fallbackOriginal, changedSettingValues(original, settings)), true
+}
+
+// postProcessing are the changes to the saved images of a request, see
+// useOutputSize. The zero value keeps the images as they are.
+type postProcessing struct {
+	// size is the size the images are cropped and scaled to.
+	size *sizes.Size
+	// tile makes the images tileable, see tileImages.
+	tile bool
+}
+
+// finishImages runs the output steps on the images of a finished request and
+// reports the result.
+func finishImages(ctx context.Context, cfg config.Config, model string, prompt string, settings providers.ModelSettings, post postProcessing, images []providers.Image, err error) ([]providers.Image, error) {
+	for i := range images {
+		images[i].Model = model
+	}
+	if err == nil {
+		err = fitImages(post.size, images)
+	}
+	if err == nil && post.tile {
+		err = tileImages(images)
+	}
+	if err == nil {
+		images, err = processOutputs(ctx, cfg, model, prompt, settings, post, images)
+	}
+	if err == nil {
+		recordHistory(model, prompt, images)
+		notifyWebhooks(ctx, cfg, model, prompt, images)
+	}
+	if cfg.AuditLog {
+		recordAudit(model, prompt, settings, images, err)
+	}
+	emitProgressResult(model, prompt, images, err)
+	return images, err
+}
+
+func generateImages(ctx context.Context, cfg config.Config, model string, prompt string, settings providers.ModelSettings) ([]providers.Image, error) {
+	modelParts := strings.SplitN(model, "/", 2)
+	if len(modelParts) != 2 {
+		return nil, fmt.Errorf("invalid model: %q", model)
+	}
+	providerName := modelParts[0]
+	modelName := modelParts[1]
+
+	pp, err := providers.GetProviderByName(providerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider: %w", err)
+	}
+	m, err := providers.FindModel(pp, modelName)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBudget(cfg, providerName); err != nil {
+		return nil, err
+	}
+	if err := requireOutDir(); err != nil {
+		return nil, err
+	}
+	release, err := providers.Schedule(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if m.Media == providers.MediaVideo {
+		vp, ok := pp.(providers.VideoProvider)
+		if !ok {
+			return nil, providers.NewError(providers.ErrorKindInvalidSettings, providerName, fmt.Errorf("provider %s can't generate videos", providerName))
+		}
+		expandedPrompt, _ := cfg.ExpandCharacters(prompt, false)
+		if err := m.Validate(expandedPrompt, settings); err != nil {
+			return nil, providers.NewError(providers.ErrorKindInvalidSettings, providerName, err)
+		}
+		if err := auditRequest(cfg, model, prompt, settings); err != nil {
+			return nil, err
+		}
+		emitProgress(progressEvent{Event: progressSubmitted, Model: model, Prompt: prompt})
+		return vp.GenerateVideo(ctx, modelName, expandedPrompt, settings)
+	}
+	sp, supportsSubjects := pp.(providers.SubjectProvider)
+	expandedPrompt, subjects := cfg.ExpandCharacters(prompt, supportsSubjects)
+	if err := m.Validate(expandedPrompt, settings); err != nil {
+		return nil, providers.NewError(providers.ErrorKindInvalidSettings, providerName, err)
+	}
+	if err := auditRequest(cfg, model, prompt, settings); err != nil {
+		return nil, err
+	}
+	emitProgress(progressEvent{Event: progressSubmitted, Model: model, Prompt: prompt})
+	if supportsSubjects && len(subjects) > 0 {
+		return sp.GenerateImageWithSubjects(ctx, modelName, expandedPrompt, subjects, settings)
+	}
+	return pp.GenerateImage(ctx, modelName, expandedPrompt, settings)
+}
+
+// printImage prints the path of a generated image or why it was filtered.
+func printImage(img providers.Image) {
+	fprintImage(os.Stdout, img)
+}
+
+func fprintImage(w io.Writer, img providers.Image) {
+	if img.Safety.Filtered {
+		fmt.Fprintln(w, i18n.T("image.filtered", img.Safety.Reason))
+		if len(img.Safety.Categories) > 0 {
+			fmt.Fprintln(w, i18n.T("image.categories", strings.Join(img.Safety.Categories, ", ")))
+		}
+		return
+	}
+	// images that were not kept locally are printed with their first upload
+	// location
+	location := img.Path
+	if location == "" && len(img.Uploads) > 0 {
+		location = img.Uploads[0]
+	}
+	fmt.Fprintln(w, location)
+	if img.Watermark != "" {
+		fmt.Fprintln(w, i18n.T("image.watermark", img.Watermark))
+	}
+	for _, upload := range img.Uploads {
+		if upload != location {
+			fmt.Fprintln(w, i18n.T("image.uploaded", upload))
+		}
+	}
+}
+
+// replCommands are the slash commands of the interactive session with the
+// message keys of their help texts.
+var replCommands = []struct{ name, help string }{
+	{"/models", "help.models"},
+	{"/settings", "help.settings"},
+	{"/jobs", "help.jobs"},
+	{"/retry", "help.retry"},
+	{"/share [duration]", "help.share"},
+	{"/preset save|apply <name>", "help.preset"},
+	{"/quota", "help.quota"},
+	{"/style <name>|none", "help.style"},
+	{"/ref clipboard|<file|url>", "help.ref"},
+	{"/help", "help.help"},
+	{"/exit", "help.exit"},
+}
+
+func printHelp(w io.Writer) {
+	fmt.Fprintln(w, i18n.T("help.commands"))
+	for _, c := range replCommands {
+		fmt.Fprintf(w, "  %-26s %s\n", c.name, i18n.T(c.help))
+	}
+}
+
+// runPresetCommand saves the settings as preset, applies a preset to them or
+// lists the presets.
+func runPresetCommand(w io.Writer, cfg *config.Config, arg string, settings providers.ModelSettings) error {
+	action, name, _ := strings.Cut(strings.TrimSpace(arg), " ")
+	name = strings.TrimSpace(name)
+	switch {
+	case action == "" || action == "list":
+		names := cfg.PresetNames()
+		if len(names) == 0 {
+			fmt.Fprintln(w, i18n.T("preset.none"))
+		}
+		for _, n := range names {
+			fmt.Fprintln(w, n)
+		}
+	case action == "save" && name != "":
+		if cfg.Presets == nil {
+			cfg.Presets = make(map[string]config.Preset)
+		}
+		cfg.Presets[name] = config.NewPreset(settings)
+		if err := cfg.Save(); err != nil {
+			return i18n.Errorf("error.save_config", err)
+		}
+		fmt.Fprintln(w, i18n.T("preset.saved", name))
+	case action == "apply" && name != "":
+		if err := cfg.ApplyPreset(name, settings); err != nil {
+			fmt.Fprintln(w, i18n.T("preset.failed", err))
+			break
+		}
+		fmt.Fprintln(w, i18n.T("preset.applied", name))
+	default:
+		fmt.Fprintln(w, i18n.T("preset.usage"))
+	}
+	return nil
+}
+
+// shareLastImages creates share links for the images of the last finished
+// generation.
+func shareLastImages(ctx context.Context, w io.Writer, cfg config.Config, images []providers.Image, expires time.Duration) {
+	if len(images) == 0 {
+		fmt.Fprintln(w, i18n.T("share.no_result"))
+		return
+	}
+	found := false
+	for _, img := range images {
+		if img.Safety.Filtered {
+			continue
+		}
+		if _, err := os.Stat(img.Path); err != nil {
+			continue
+		}
+		found = true
+		shareURL, err := upload.Share(ctx, cfg.Share, img.Path, expires)
+		if err != nil {
+			fmt.Fprintln(w, err)
+			continue
+		}
+		fmt.Fprintln(w, i18n.T("share.shared", shareURL))
+	}
+	if !found {
+		fmt.Fprintln(w, i18n.T("share.no_local_image"))
+	}
+}
+
+// showMedia renders a generated image or a preview of a generated video
+// inline. Filtered results have nothing to show.
+func showMedia(img providers.Image) {
+	if img.Path == "" {
+		return
+	}
+	if img.Media == providers.MediaVideo {
+		showVideo(img.Path)
+		return
+	}
+	showImage(img.Path)
+}
+
+// showImage renders the image inline with viu. Nothing is rendered in plain
+// or no-color mode.
+func showImage(filePath string) {
+	if noColor() {
+		return
+	}
+	b := bounds(filePath)
+	var cmd *exec.Cmd
+	width := b.Dx()
+	height := b.Dy()
+	if width > height {
+		cmd = exec.Command("viu", "--width", "80", filePath)
+	} else {
+		cmd = exec.Command("viu", "--height", "25", filePath)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	_ = cmd.Run()
+}
+
+// debugHTTP is the file the provider HTTP requests are logged to, set with
+// --debug-http.
+var debugHTTP string
+
 func Execute() {
-	err := rootCmd.Execute()
+	// CLIMAGE_CASSETTE records the provider HTTP traffic to a file or replays
+	// it, selected with CLIMAGE_CASSETTE_MODE "record" or "replay".
+	if path := os.Getenv("CLIMAGE_CASSETTE"); path != "" {
+		if err := providers.UseCassette(providers.CassetteMode(os.Getenv("CLIMAGE_CASSETTE_MODE")), path); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	openLogFile()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	if saveErr := providers.SaveCassette(); saveErr != nil {
+		log.Printf("warning: %v", saveErr)
+	}
+	if closeErr := providers.CloseHTTPDebug(); closeErr != nil {
+		log.Printf("warning: failed to close HTTP debug log: %v", closeErr)
+	}
+	closeLogFile()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(providers.ExitCode(err))
 	}
 }
 
 func init() {
 	rootCmd.SilenceUsage = true
+	rootCmd.PersistentFlags().BoolVar(&plainMode, "plain", false, "use line based prompts without colors and inline images, for screen readers and logs")
+	rootCmd.PersistentFlags().BoolVar(&forceBudget, "force", false, "generate even if the budget of the provider is used up")
+	rootCmd.PersistentFlags().StringVar(&progressFormat, "progress", "none", "progress event format written to stderr: \"ndjson\" or \"none\"")
+	rootCmd.PersistentFlags().StringVar(&debugHTTP, "debug-http", "", "log the provider HTTP requests with secrets redacted to this file")
+	rootCmd.PersistentFlags().BoolVar(&headless, "headless", false, "never prompt and read provider credentials only from the environment, for containers and CI (or set CLIMAGE_HEADLESS=1)")
+	rootCmd.PersistentFlags().StringVar(&outDir, "out", "", "directory generated images are saved to instead of the configured one, required with --headless")
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := setupHeadless(); err != nil {
+			return err
+		}
+		if debugHTTP != "" {
+			if err := providers.EnableHTTPDebug(debugHTTP); err != nil {
+				return err
+			}
+		}
+		return validateProgressFormat()
+	}
 }
 
 func aspectRatio(imageFilePath string) float64 {
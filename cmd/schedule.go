@@ -0,0 +1,285 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/bloodmagesoftware/climage/schedule"
+	"github.com/spf13/cobra"
+)
+
+var scheduleAddFlags struct {
+	model     string
+	preset    string
+	set       []string
+	size      string
+	style     string
+	wallpaper bool
+}
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Generate images on a recurring schedule",
+	Long: `Schedule recurring generations like a daily wallpaper or refreshed placeholders. Jobs are run by 'climage schedule run-due', e.g. every minute from cron or a systemd timer, or by 'climage schedule daemon' which keeps running. Without a subcommand the jobs are listed.
+
+Schedules are cron expressions with the fields minute, hour, day of month, month and day of week, e.g. "0 9 * * *" for 9:00 every day or "*/30 8-18 * * 1-5" for every half hour during work hours. The macros @hourly, @daily, @weekly, @monthly and @yearly are supported as well. Times are local.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return scheduleListCmd.RunE(cmd, args)
+	},
+}
+
+var scheduleAddCmd = &cobra.Command{
+	Use:   "add <name> <cron> <prompt>",
+	Short: "Add a recurring generation",
+	Long:  `Add a job that generates the prompt whenever the cron expression matches, e.g. climage schedule add daily-wallpaper "0 9 * * *" "a misty forest at dawn" --preset wallpaper. The model and the settings from --preset and --set are checked now, the default model at run time is used without --model. With --set-wallpaper every generated image becomes the desktop background.`,
+	Args:  cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := schedule.Parse(args[1]); err != nil {
+			return err
+		}
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+		modelName := cfg.DefaultModel
+		if scheduleAddFlags.model != "" {
+			modelName = scheduleAddFlags.model
+		}
+		model, original, err := resolveModel(cfg, modelName)
+		if err != nil {
+			return err
+		}
+		if scheduleAddFlags.model != "" && model != scheduleAddFlags.model {
+			return fmt.Errorf("model %q is not available", scheduleAddFlags.model)
+		}
+		settings, err := applySettingFlags(cfg, original, scheduleAddFlags.preset, scheduleAddFlags.set)
+		if err != nil {
+			return err
+		}
+		if _, _, err := useOutputSize(postProcessing{}, settings, scheduleAddFlags.size); err != nil {
+			return err
+		}
+		style, err := getStyle(scheduleAddFlags.style)
+		if err != nil {
+			return err
+		}
+		prompt := applyStyle(style, strings.Join(args[2:], " "))
+		job := schedule.Job{
+			Name:      args[0],
+			Cron:      args[1],
+			Prompt:    prompt,
+			Model:     scheduleAddFlags.model,
+			Size:      scheduleAddFlags.size,
+			Wallpaper: scheduleAddFlags.wallpaper,
+			Added:     time.Now(),
+		}
+		if values := changedSettingValues(original, settings); len(values) > 0 {
+			job.Settings = values
+		}
+		if err := schedule.Add(job); err != nil {
+			return err
+		}
+		next, _ := job.Next()
+		fmt.Printf("scheduled %s, next run %s\n", job.Name, next.Format(time.DateTime))
+		return nil
+	},
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the scheduled jobs",
+	Long:  `List the scheduled jobs with their schedule, the next and the last run.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jobs, err := schedule.Read()
+		if err != nil {
+			return err
+		}
+		if len(jobs) == 0 {
+			fmt.Println("no jobs scheduled")
+			return nil
+		}
+		for _, job := range jobs {
+			next := "never"
+			if t, err := job.Next(); err != nil {
+				next = "invalid schedule"
+			} else if !t.IsZero() {
+				next = t.Format(time.DateTime)
+			}
+			last := "never"
+			if !job.LastRun.IsZero() {
+				last = job.LastRun.Local().Format(time.DateTime)
+			}
+			model := job.Model
+			if model == "" {
+				model = "default model"
+			}
+			wallpaper := ""
+			if job.Wallpaper {
+				wallpaper = " (wallpaper)"
+			}
+			fmt.Printf("%s  %q%s\n  next %s, last %s, %s: %q\n", job.Name, job.Cron, wallpaper, next, last, model, job.Prompt)
+		}
+		return nil
+	},
+}
+
+var scheduleRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a scheduled job",
+	Long:  `Remove the scheduled job with the name.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return schedule.Remove(args[0])
+	},
+}
+
+var scheduleRunDueCmd = &cobra.Command{
+	Use:   "run-due",
+	Short: "Run the jobs that are due",
+	Long: `Run every job whose scheduled time has passed since its last run. Missed runs are caught up once, not once per missed time. Run it every minute from an external scheduler, e.g. with the crontab entry:
+
+  * * * * * climage schedule run-due`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDueJobs(cmd.Context())
+	},
+}
+
+var scheduleDaemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Keep running and run jobs when they are due",
+	Long:  `Keep running and run the jobs when they are due, checked at the start of every minute. Stop it with Ctrl+C. Use 'climage schedule run-due' instead to run the jobs from cron or a systemd timer.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		for {
+			if err := runDueJobs(ctx); err != nil {
+				log.Printf("warning: %v", err)
+			}
+			now := time.Now()
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(now.Truncate(time.Minute).Add(time.Minute).Sub(now)):
+			}
+		}
+	},
+}
+
+// runDueJobs runs the jobs that are due. A job that fails with a network error
+// or provider outage stays due and is retried on the next call.
+func runDueJobs(ctx context.Context) error {
+	jobs, err := schedule.Read()
+	if err != nil {
+		return err
+	}
+	var cfg config.Config
+	loaded := false
+	var errs []error
+	for _, job := range jobs {
+		now := time.Now()
+		if !job.Due(now) {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		// claim the run before generating so a daemon and a cron job running
+		// at the same time don't both generate (and bill) the job
+		job, claimed, err := schedule.Claim(job.Name, now)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+		if !loaded {
+			// the config is read on every call so changes apply to a running
+			// daemon
+			if cfg, err = config.GetConfig(); err != nil {
+				return fmt.Errorf("failed to get config: %w", err)
+			}
+			loaded = true
+		}
+		images, err := runScheduledJob(ctx, cfg, job)
+		if err != nil {
+			err = fmt.Errorf("job %s failed: %w", job.Name, err)
+			errs = append(errs, err)
+			kind := providers.KindOf(err)
+			if kind == providers.ErrorKindNetwork || kind == providers.ErrorKindOutage || ctx.Err() != nil {
+				// give the claim back so the job stays due
+				if err := schedule.SetLastRun(job.Name, job.LastRun); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		for _, img := range images {
+			printImage(img)
+		}
+		if err == nil && job.Wallpaper {
+			if err := setWallpaper(images); err != nil {
+				errs = append(errs, fmt.Errorf("job %s: %w", job.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func runScheduledJob(ctx context.Context, cfg config.Config, job schedule.Job) ([]providers.Image, error) {
+	modelName := job.Model
+	if modelName == "" {
+		modelName = cfg.DefaultModel
+	}
+	model, settings, err := resolveModel(cfg, modelName)
+	if err != nil {
+		return nil, err
+	}
+	if model != modelName {
+		return nil, fmt.Errorf("model %q is not available", modelName)
+	}
+	fmt.Printf("%s %s: %q\n", job.Name, model, job.Prompt)
+	post, settings, err := useOutputSize(postProcessing{}, applySettingValues(settings, job.Settings), job.Size)
+	if err != nil {
+		return nil, err
+	}
+	return generate(ctx, cfg, model, job.Prompt, settings, post)
+}
+
+func init() {
+	scheduleAddCmd.Flags().StringVarP(&scheduleAddFlags.model, "model", "m", "", "model to generate with (defaults to the default model at run time)")
+	scheduleAddCmd.Flags().StringVar(&scheduleAddFlags.preset, "preset", "", "settings preset to apply, see /preset in the interactive session")
+	scheduleAddCmd.Flags().StringVar(&scheduleAddFlags.style, "style", "", "style preset added to the prompt, e.g. watercolor, see 'climage styles'")
+	scheduleAddCmd.Flags().StringVar(&scheduleAddFlags.size, "size", "", "output size preset or WIDTHxHEIGHT, the image is cropped and scaled to it, see 'climage sizes'")
+	scheduleAddCmd.Flags().StringArrayVar(&scheduleAddFlags.set, "set", nil, "set a model setting, e.g. --set aspect_ratio=16:9, can be repeated")
+	scheduleAddCmd.Flags().BoolVar(&scheduleAddFlags.wallpaper, "set-wallpaper", false, "set the generated image as the desktop background")
+
+	scheduleCmd.AddCommand(scheduleAddCmd, scheduleListCmd, scheduleRemoveCmd, scheduleRunDueCmd, scheduleDaemonCmd)
+	rootCmd.AddCommand(scheduleCmd)
+}
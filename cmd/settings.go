@@ -0,0 +1,97 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+// applySettingFlags applies the --preset and then the --set flags to a copy
+// of the settings. Every --set is a name=value pair.
+func applySettingFlags(cfg config.Config, settings providers.ModelSettings, preset string, sets []string) (providers.ModelSettings, error) {
+	settings = settings.Clone()
+	if preset != "" {
+		if err := cfg.ApplyPreset(preset, settings); err != nil {
+			return nil, err
+		}
+	}
+	for _, set := range sets {
+		name, value, ok := strings.Cut(set, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --set %q, expected name=value", set)
+		}
+		name = strings.TrimSpace(name)
+		var setting *providers.ModelSetting
+		var names []string
+		for _, s := range settings {
+			names = append(names, s.Name)
+			if s.Name == name {
+				setting = s
+			}
+		}
+		if setting == nil {
+			return nil, fmt.Errorf("unknown setting %q, the model has %s", name, strings.Join(names, ", "))
+		}
+		if setting.Type != nil {
+			if err := setting.Type.Validate(value); err != nil {
+				return nil, fmt.Errorf("invalid value %q for setting %q: %w", value, name, err)
+			}
+		}
+		setting.Value = value
+	}
+	return settings, nil
+}
+
+// applySettingValues sets recorded values on a copy of the settings. Values
+// for settings the model doesn't have are skipped, invalid values are skipped
+// with a warning.
+func applySettingValues(settings providers.ModelSettings, values map[string]string) providers.ModelSettings {
+	settings = settings.Clone()
+	for _, s := range settings {
+		v, ok := values[s.Name]
+		if !ok {
+			continue
+		}
+		if s.Type != nil {
+			if err := s.Type.Validate(v); err != nil {
+				log.Printf("warning: ignoring value %q of setting %q: %v", v, s.Name, err)
+				continue
+			}
+		}
+		s.Value = v
+	}
+	return settings
+}
+
+// changedSettingValues returns the values of settings that differ from the
+// original settings.
+func changedSettingValues(original, settings providers.ModelSettings) map[string]string {
+	values := make(map[string]string)
+	for i, s := range settings {
+		if s.Value != original[i].Value {
+			values[s.Name] = s.Value
+		}
+	}
+	return values
+}
@@ -0,0 +1,165 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/i18n"
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+var setupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Run the setup wizard",
+	Long:  `Run the setup wizard that guides you through logging in to a provider, choosing the default model and the output directory, and a test generation. The wizard starts automatically on the first run.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := runSetup(cmd.Context())
+		return err
+	},
+}
+
+// isFirstRun reports whether climage was never configured.
+func isFirstRun(cfg config.Config) bool {
+	return !config.Exists() && len(cfg.Providers) == 0
+}
+
+// runSetup runs the setup wizard and returns the saved config.
+func runSetup(ctx context.Context) (config.Config, error) {
+	if err := requireInteractive("setup"); err != nil {
+		return config.Config{}, err
+	}
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return cfg, i18n.Errorf("error.config", err)
+	}
+
+	fmt.Println(i18n.T("setup.welcome"))
+	if len(cfg.Providers) == 0 {
+		if err := login(ctx, &cfg); err != nil {
+			return cfg, err
+		}
+		// reload to apply the options of the new provider
+		if cfg, err = config.GetConfig(); err != nil {
+			return cfg, i18n.Errorf("error.config", err)
+		}
+	}
+
+	var modelOptions []huh.Option[string]
+	for modelName, model := range cfg.GetModels() {
+		modelOptions = append(modelOptions, huh.NewOption(model.DisplayName, modelName))
+	}
+	if len(modelOptions) == 0 {
+		return cfg, i18n.Errorf("error.no_model")
+	}
+	model, _, err := resolveModel(cfg, cfg.DefaultModel)
+	if err != nil {
+		return cfg, err
+	}
+
+	outputDir := cfg.OutputDir
+	defaultOutputDir, err := providers.DefaultOutDir()
+	if err != nil {
+		return cfg, err
+	}
+	if outputDir == "" {
+		outputDir = defaultOutputDir
+	}
+	testGeneration := true
+
+	if err := newForm(cfg,
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(i18n.T("setup.model.title")).
+				Description(i18n.T("setup.model.description")).
+				Options(modelOptions...).
+				Value(&model),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title(i18n.T("setup.output_dir.title")).
+				Description(i18n.T("setup.output_dir.description")).
+				Validate(huh.ValidateNotEmpty()).
+				Value(&outputDir),
+			huh.NewConfirm().
+				Title(i18n.T("setup.test.title")).
+				Value(&testGeneration),
+		),
+	).Run(); err != nil {
+		return cfg, i18n.Errorf("error.setup_form", err)
+	}
+
+	outputDir, err = expandPath(outputDir)
+	if err != nil {
+		return cfg, err
+	}
+	if outputDir == defaultOutputDir {
+		// keep following the default
+		outputDir = ""
+	}
+	cfg.DefaultModel = model
+	cfg.OutputDir = outputDir
+	providers.SetOutDir(outputDir)
+	if err := cfg.Save(); err != nil {
+		return cfg, i18n.Errorf("error.save_config", err)
+	}
+
+	if testGeneration {
+		_, settings, err := resolveModel(cfg, model)
+		if err != nil {
+			return cfg, err
+		}
+		fmt.Println(i18n.T("setup.test.running", model))
+		images, err := generate(ctx, cfg, model, i18n.T("setup.test.prompt"), settings.Clone(), postProcessing{})
+		if err != nil {
+			return cfg, i18n.Errorf("error.setup_test", err)
+		}
+		for _, img := range images {
+			printImage(img)
+			showMedia(img)
+		}
+	}
+
+	fmt.Println(i18n.T("setup.done"))
+	return cfg, nil
+}
+
+// expandPath expands a leading ~ to the home dir and makes the path absolute.
+func expandPath(p string) (string, error) {
+	p = strings.TrimSpace(p)
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home dir: %w", err)
+		}
+		p = filepath.Join(home, p[1:])
+	}
+	return filepath.Abs(p)
+}
+
+func init() {
+	rootCmd.AddCommand(setupCmd)
+}
@@ -0,0 +1,61 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/sign"
+	"github.com/spf13/cobra"
+)
+
+var signCmd = &cobra.Command{
+	Use:   "sign <file>...",
+	Short: "Sign files with the configured key",
+	Long: `Sign files with the key configured in "signing", e.g. a sprite sheet, a batch archive or a checksum manifest. Generated images, their sidecars and the checksum manifests are signed automatically once signing is configured:
+
+  "signing": {"tool": "gpg", "key": "pipeline@example.com"}
+  "signing": {"tool": "minisign", "key": "/path/to/minisign.key"}
+
+The detached signature is written next to the file with .asc for GPG and .minisig for minisign appended. Check it with gpg --verify file.asc or minisign -V -p minisign.pub -m file. Minisign keys must not be password protected.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+		if !cfg.Signing.Enabled() {
+			return errors.New("signing is not configured, set \"signing\" in the config")
+		}
+		for _, f := range args {
+			sigPath, err := sign.File(cmd.Context(), cfg.Signing, f)
+			if err != nil {
+				return err
+			}
+			fmt.Println(sigPath)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(signCmd)
+}
@@ -0,0 +1,124 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/bloodmagesoftware/climage/sizes"
+	"github.com/spf13/cobra"
+)
+
+var sizesCmd = &cobra.Command{
+	Use:   "sizes",
+	Short: "List the output size presets",
+	Long:  `List the named output sizes for --size. The model generates the nearest aspect ratio it supports and the images are cropped and scaled to the exact size afterwards. Custom sizes are given as WIDTHxHEIGHT, e.g. --size 800x600.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, s := range sizes.Presets {
+			fmt.Printf("%-20s %4d x %d\n", s.Name, s.Width, s.Height)
+		}
+		return nil
+	},
+}
+
+// useOutputSize prepares a request for the output size, a preset name or
+// WIDTHxHEIGHT. The aspect ratio of the settings is set to the nearest the
+// model supports and the size is added to the post-processing for fitImages.
+// An empty size leaves everything unchanged.
+func useOutputSize(post postProcessing, settings providers.ModelSettings, size string) (postProcessing, providers.ModelSettings, error) {
+	if size == "" {
+		return post, settings, nil
+	}
+	s, err := sizes.Parse(size)
+	if err != nil {
+		return post, settings, err
+	}
+	settings = settings.Clone()
+	var width, height *providers.ModelSetting
+	for _, setting := range settings {
+		switch setting.Name {
+		case "aspect_ratio":
+			if enum, ok := setting.Type.(providers.EnumSetting); ok {
+				if ratio := s.NearestAspectRatio(enum.Options); ratio != "" {
+					setting.Value = ratio
+				}
+			}
+		case "width":
+			width = setting
+		case "height":
+			height = setting
+		}
+	}
+	if width != nil && height != nil {
+		setDimensions(settings, width, height, s)
+	}
+	post.size = &s
+	return post, settings, nil
+}
+
+// setDimensions sets width and height settings to the aspect ratio of the
+// size, keeping the longer side of the current values.
+func setDimensions(settings providers.ModelSettings, width, height *providers.ModelSetting, s sizes.Size) {
+	long := max(providers.GetModelSettingInt(settings, width.Name, 0), providers.GetModelSettingInt(settings, height.Name, 0))
+	if long <= 0 {
+		return
+	}
+	w, h := long, long
+	if s.Width > s.Height {
+		h = long * s.Height / s.Width
+	} else {
+		w = long * s.Width / s.Height
+	}
+	width.Value = fmt.Sprint(fitDimension(width, w))
+	height.Value = fmt.Sprint(fitDimension(height, h))
+}
+
+// fitDimension rounds to a multiple of 8, which diffusion models need, within
+// the bounds of the setting.
+func fitDimension(setting *providers.ModelSetting, v int) int {
+	v = max(v/8*8, 8)
+	if r, ok := setting.Type.(providers.IntSetting); ok {
+		v = min(max(v, r.Min), r.Max)
+	}
+	return v
+}
+
+// fitImages crops and scales the saved images to the output size, if there is
+// one. Videos are kept as they are.
+func fitImages(s *sizes.Size, images []providers.Image) error {
+	if s == nil {
+		return nil
+	}
+	for _, img := range images {
+		if img.Path == "" || img.Media == providers.MediaVideo {
+			continue
+		}
+		warnContentCredentials(img.Path)
+		if err := sizes.Fit(img.Path, *s); err != nil {
+			return fmt.Errorf("failed to fit %s to %s: %w", img.Path, s, err)
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(sizesCmd)
+}
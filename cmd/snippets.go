@@ -0,0 +1,120 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/spf13/cobra"
+)
+
+var snippetsCmd = &cobra.Command{
+	Use:   "snippets",
+	Short: "Manage reusable prompt snippets",
+	Long:  `Manage named prompt snippets like lighting or style descriptions. Reference a snippet in a prompt with @name, e.g. "a harbor at dusk, @lighting-golden-hour @style-ukiyoe", and it is replaced with its text before the prompt is sent. A snippet may reference characters. If a character has the same name, the character is used. Without a subcommand the snippets are listed.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return snippetsListCmd.RunE(cmd, args)
+	},
+}
+
+var snippetsAddCmd = &cobra.Command{
+	Use:   "add <name> <text>",
+	Short: "Add or update a snippet",
+	Long:  `Add a snippet or replace the text of an existing one, e.g. climage snippets add lighting-golden-hour "warm low sun, long soft shadows, golden rim light". Names may contain letters, digits, '-' and '_'.`,
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.TrimPrefix(args[0], "@")
+		if !isValidCharacterName(name) {
+			return fmt.Errorf("invalid snippet name %q: only letters, digits, '-' and '_' are allowed", name)
+		}
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+		if _, ok := cfg.Characters[name]; ok {
+			return fmt.Errorf("a character is named %q, @%s would reference the character", name, name)
+		}
+		if cfg.Snippets == nil {
+			cfg.Snippets = make(map[string]string)
+		}
+		cfg.Snippets[name] = strings.Join(args[1:], " ")
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		return nil
+	},
+}
+
+var snippetsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the snippets",
+	Long:  `List the snippets with their text.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+		if len(cfg.Snippets) == 0 {
+			fmt.Println("no snippets, add one with 'climage snippets add <name> <text>'")
+			return nil
+		}
+		names := make([]string, 0, len(cfg.Snippets))
+		for name := range cfg.Snippets {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			fmt.Printf("@%s: %s\n", name, cfg.Snippets[name])
+		}
+		return nil
+	},
+}
+
+var snippetsRemoveCmd = &cobra.Command{
+	Use:     "rm <name>",
+	Aliases: []string{"remove"},
+	Short:   "Remove a snippet",
+	Long:    `Remove the snippet with the name. Prompts that still reference it keep the @name.`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+		name := strings.TrimPrefix(args[0], "@")
+		if _, ok := cfg.Snippets[name]; !ok {
+			return fmt.Errorf("snippet %q not found", name)
+		}
+		delete(cfg.Snippets, name)
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	snippetsCmd.AddCommand(snippetsAddCmd, snippetsListCmd, snippetsRemoveCmd)
+	rootCmd.AddCommand(snippetsCmd)
+}
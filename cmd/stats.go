@@ -0,0 +1,117 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/history"
+	"github.com/spf13/cobra"
+)
+
+var statsFlags struct {
+	weeks int
+}
+
+// usage sums up a set of history entries.
+type usage struct {
+	generations int
+	images      int
+	filtered    int
+	cost        float64
+}
+
+func (u *usage) add(e history.Entry) {
+	u.generations++
+	u.images += len(e.Images) + len(e.Uploads)
+	u.filtered += e.Filtered
+	u.cost += e.Cost
+}
+
+func (u usage) String() string {
+	return fmt.Sprintf("%5d generations %6d images  $%8.2f", u.generations, u.images, u.cost)
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show local usage statistics",
+	Long:  `Show statistics of your generations: the number of generations and images, the usage per model and the estimated spend, broken down by week. The statistics are computed from the local history and never leave your machine. The spend is estimated from list prices and may differ from your bill.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := history.Read()
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Println("no generations recorded yet")
+			return nil
+		}
+
+		var total usage
+		byModel := make(map[string]*usage)
+		byWeek := make(map[string]*usage)
+		for _, e := range entries {
+			total.add(e)
+			if byModel[e.Model] == nil {
+				byModel[e.Model] = &usage{}
+			}
+			byModel[e.Model].add(e)
+			year, week := e.Time.ISOWeek()
+			key := fmt.Sprintf("%d-W%02d", year, week)
+			if byWeek[key] == nil {
+				byWeek[key] = &usage{}
+			}
+			byWeek[key].add(e)
+		}
+
+		fmt.Printf("generations:     %d\n", total.generations)
+		fmt.Printf("images:          %d (%d filtered)\n", total.images, total.filtered)
+		fmt.Printf("estimated spend: $%.2f\n", total.cost)
+
+		fmt.Println("\nper model:")
+		models := make([]string, 0, len(byModel))
+		for m := range byModel {
+			models = append(models, m)
+		}
+		slices.Sort(models)
+		for _, m := range models {
+			fmt.Printf("  %-40s %s\n", m, byModel[m])
+		}
+
+		fmt.Println("\nper week:")
+		now := time.Now()
+		for i := statsFlags.weeks - 1; i >= 0; i-- {
+			year, week := now.AddDate(0, 0, -7*i).ISOWeek()
+			key := fmt.Sprintf("%d-W%02d", year, week)
+			u := byWeek[key]
+			if u == nil {
+				u = &usage{}
+			}
+			fmt.Printf("  %-10s %s\n", key, u)
+		}
+		return nil
+	},
+}
+
+func init() {
+	statsCmd.Flags().IntVar(&statsFlags.weeks, "weeks", 8, "number of weeks to show")
+
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,92 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bloodmagesoftware/climage/i18n"
+	"github.com/bloodmagesoftware/climage/styles"
+	"github.com/spf13/cobra"
+)
+
+var stylesCmd = &cobra.Command{
+	Use:   "styles",
+	Short: "List the style presets",
+	Long:  `List the style presets for --style and /style. A style appends its modifier text to the prompt.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, s := range styles.Presets {
+			fmt.Printf("%-16s %s\n", s.Name, s.Modifier)
+		}
+		return nil
+	},
+}
+
+// getStyle returns the style with the name, no style for an empty name.
+func getStyle(name string) (styles.Style, error) {
+	if name == "" {
+		return styles.Style{}, nil
+	}
+	return styles.Get(name)
+}
+
+// applyStyle appends the modifier of the style to the prompt. No style leaves
+// the prompt unchanged.
+func applyStyle(style styles.Style, prompt string) string {
+	if style.Name == "" {
+		return prompt
+	}
+	return style.Apply(prompt)
+}
+
+// runStyleCommand selects the style of the interactive session, clears it or
+// lists the styles.
+func runStyleCommand(w io.Writer, style *styles.Style, arg string) {
+	switch arg = strings.TrimSpace(arg); arg {
+	case "":
+		for _, s := range styles.Presets {
+			marker := " "
+			if s.Name == style.Name {
+				marker = "*"
+			}
+			fmt.Fprintf(w, "%s %s\n", marker, s.Name)
+		}
+		if style.Name == "" {
+			fmt.Fprintln(w, i18n.T("style.none"))
+		}
+	case "none":
+		*style = styles.Style{}
+		fmt.Fprintln(w, i18n.T("style.cleared"))
+	default:
+		s, err := styles.Get(arg)
+		if err != nil {
+			fmt.Fprintln(w, i18n.T("style.failed", err))
+			return
+		}
+		*style = s
+		fmt.Fprintln(w, i18n.T("style.selected", s.Name))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(stylesCmd)
+}
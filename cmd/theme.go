@@ -0,0 +1,101 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// plainMode replaces the TUI forms with line based prompts and disables
+// inline images, for screen readers and captured logs.
+var plainMode bool
+
+// noColor reports whether colored output is disabled, see https://no-color.org.
+func noColor() bool {
+	return plainMode || os.Getenv("NO_COLOR") != ""
+}
+
+// newForm creates a form styled with the configured theme and key bindings.
+func newForm(cfg config.Config, groups ...*huh.Group) *huh.Form {
+	theme := formTheme(cfg.Theme)
+	if noColor() {
+		theme = huh.ThemeBase()
+	}
+	return huh.NewForm(groups...).
+		WithTheme(theme).
+		WithKeyMap(formKeyMap(cfg.Keys)).
+		WithAccessible(plainMode)
+}
+
+func formTheme(t config.Theme) *huh.Theme {
+	switch t.Background {
+	case "dark":
+		lipgloss.SetHasDarkBackground(true)
+	case "light":
+		lipgloss.SetHasDarkBackground(false)
+	}
+
+	var theme *huh.Theme
+	switch t.Name {
+	case "dracula":
+		theme = huh.ThemeDracula()
+	case "catppuccin":
+		theme = huh.ThemeCatppuccin()
+	case "base16":
+		theme = huh.ThemeBase16()
+	case "base":
+		theme = huh.ThemeBase()
+	default:
+		theme = huh.ThemeCharm()
+	}
+
+	if t.Accent != "" {
+		accent := lipgloss.Color(t.Accent)
+		theme.Focused.Title = theme.Focused.Title.Foreground(accent)
+		theme.Focused.NoteTitle = theme.Focused.NoteTitle.Foreground(accent)
+		theme.Focused.Directory = theme.Focused.Directory.Foreground(accent)
+		theme.Focused.FocusedButton = theme.Focused.FocusedButton.Background(accent)
+		theme.Focused.Next = theme.Focused.FocusedButton
+		theme.Group.Title = theme.Group.Title.Foreground(accent)
+	}
+	if t.Secondary != "" {
+		secondary := lipgloss.Color(t.Secondary)
+		theme.Focused.SelectSelector = theme.Focused.SelectSelector.Foreground(secondary)
+		theme.Focused.MultiSelectSelector = theme.Focused.MultiSelectSelector.Foreground(secondary)
+		theme.Focused.NextIndicator = theme.Focused.NextIndicator.Foreground(secondary)
+		theme.Focused.PrevIndicator = theme.Focused.PrevIndicator.Foreground(secondary)
+		theme.Focused.SelectedOption = theme.Focused.SelectedOption.Foreground(secondary)
+		theme.Focused.TextInput.Cursor = theme.Focused.TextInput.Cursor.Foreground(secondary)
+		theme.Focused.TextInput.Prompt = theme.Focused.TextInput.Prompt.Foreground(secondary)
+	}
+	if t.Accent != "" || t.Secondary != "" {
+		// blurred fields keep the colors but hide the border
+		focused := theme.Focused
+		focused.Base = theme.Blurred.Base
+		focused.Card = theme.Blurred.Card
+		focused.NextIndicator = theme.Blurred.NextIndicator
+		focused.PrevIndicator = theme.Blurred.PrevIndicator
+		theme.Blurred = focused
+	}
+	return theme
+}
@@ -0,0 +1,50 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/bloodmagesoftware/climage/tiles"
+)
+
+// tileImages makes the saved images seamless and writes their tiled
+// previews. Videos are kept as they are.
+func tileImages(images []providers.Image) error {
+	for _, img := range images {
+		if img.Path == "" || img.Media == providers.MediaVideo {
+			continue
+		}
+		warnContentCredentials(img.Path)
+		if err := tiles.Process(img.Path); err != nil {
+			return fmt.Errorf("failed to make %s tileable: %w", img.Path, err)
+		}
+	}
+	return nil
+}
+
+// printTilePreviews prints the paths of the tiled previews.
+func printTilePreviews(images []providers.Image) {
+	for _, img := range images {
+		if img.Path != "" {
+			fmt.Println(tiles.PreviewPath(img.Path))
+		}
+	}
+}
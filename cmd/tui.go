@@ -0,0 +1,481 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"image"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/i18n"
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/bloodmagesoftware/climage/sizes"
+	"github.com/bloodmagesoftware/climage/styles"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// tuiInputHeight is the height of the prompt input in lines.
+	tuiInputHeight = 3
+	// tuiJobsWidth is the width of the jobs list.
+	tuiJobsWidth = 48
+	// tuiPreviewWidth is the width of the image previews in cells.
+	tuiPreviewWidth = 40
+	// tuiPollInterval is how often finished jobs are collected.
+	tuiPollInterval = 250 * time.Millisecond
+)
+
+// tuiModel is the interactive session as a full screen Bubble Tea app: the
+// results scroll above the prompt input, a status bar shows the model, the
+// session's cost and the running jobs, and the jobs list can be shown next to
+// the results. The slash commands are the same as in the line based session.
+type tuiModel struct {
+	ctx     context.Context
+	jobsCtx context.Context
+	cfg     config.Config
+	jobs    *jobList
+	keys    tuiKeyMap
+
+	model           string
+	settings        providers.ModelSettings
+	settingsByModel map[string]providers.ModelSettings
+
+	input    textarea.Model
+	results  viewport.Model
+	output   strings.Builder
+	showJobs bool
+	width    int
+	height   int
+
+	// form is a /models or /settings form shown instead of the input, done
+	// is called once it is completed.
+	form *huh.Form
+	done func()
+	// selectedModel is the value of the /models form.
+	selectedModel string
+
+	lastPrompt string
+	lastImages []providers.Image
+	refs       [][]byte
+	style      styles.Style
+	cost       float64
+	// cancelled is set after ctrl+c cancelled jobs, a second ctrl+c quits.
+	cancelled bool
+
+	statusStyle lipgloss.Style
+	accentStyle lipgloss.Style
+}
+
+type tuiTickMsg struct{}
+
+// tuiOutputMsg is output of a command that ran in the background.
+type tuiOutputMsg string
+
+// tuiRefsMsg are the reference images after a /ref command.
+type tuiRefsMsg struct {
+	refs   [][]byte
+	output string
+}
+
+func newTUIModel(ctx context.Context, jobsCtx context.Context, cfg config.Config, jobs *jobList, model string, settingsByModel map[string]providers.ModelSettings) *tuiModel {
+	input := textarea.New()
+	input.Placeholder = i18n.T("prompt.description", model)
+	input.ShowLineNumbers = false
+	input.SetHeight(tuiInputHeight)
+	keys := newTUIKeyMap(cfg.Keys)
+	input.KeyMap.InsertNewline = keys.newLine
+	input.Focus()
+
+	theme := formTheme(cfg.Theme)
+	m := &tuiModel{
+		ctx:             ctx,
+		jobsCtx:         jobsCtx,
+		cfg:             cfg,
+		jobs:            jobs,
+		keys:            keys,
+		model:           model,
+		settings:        sessionSettings(cfg, settingsByModel, model),
+		settingsByModel: settingsByModel,
+		input:           input,
+		results:         viewport.New(0, 0),
+		statusStyle:     lipgloss.NewStyle().Faint(true),
+		accentStyle:     theme.Focused.Title.Bold(true),
+	}
+	fmt.Fprintln(&m.output, i18n.T("prompt.description", model))
+	return m
+}
+
+func (m *tuiModel) Init() tea.Cmd {
+	return tea.Batch(textarea.Blink, tuiTick())
+}
+
+func tuiTick() tea.Cmd {
+	return tea.Tick(tuiPollInterval, func(time.Time) tea.Msg { return tuiTickMsg{} })
+}
+
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.layout()
+	case tuiTickMsg:
+		m.collectJobs()
+		return m, tuiTick()
+	case tuiOutputMsg:
+		m.print(string(msg))
+		return m, nil
+	case tuiRefsMsg:
+		m.refs = msg.refs
+		m.print(msg.output)
+		return m, nil
+	}
+
+	if m.form != nil {
+		form, cmd := m.form.Update(msg)
+		if f, ok := form.(*huh.Form); ok {
+			m.form = f
+		}
+		switch m.form.State {
+		case huh.StateCompleted:
+			m.form = nil
+			m.done()
+			return m, m.input.Focus()
+		case huh.StateAborted:
+			m.form = nil
+			return m, m.input.Focus()
+		}
+		return m, cmd
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.abort):
+			// the first interrupt only cancels running generations
+			if n := m.jobs.cancelAll(); n > 0 && !m.cancelled {
+				m.cancelled = true
+				m.print(i18n.T("jobs_cancelled", n))
+				return m, nil
+			}
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.refine):
+			// cancel the latest generation to refine its prompt, text that
+			// was already typed is kept
+			j, ok := m.jobs.cancelLatest()
+			if !ok {
+				return m, nil
+			}
+			m.print(i18n.T("tui.refine", j.id, m.keys.submit.Help().Key))
+			if strings.TrimSpace(m.input.Value()) == "" {
+				m.input.SetValue(j.prompt)
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.submit):
+			prompt := strings.TrimSpace(m.input.Value())
+			m.input.Reset()
+			if prompt == "" {
+				return m, nil
+			}
+			m.cancelled = false
+			return m, m.submit(prompt)
+		case msg.String() == "tab":
+			m.showJobs = !m.showJobs
+			m.layout()
+			return m, nil
+		case msg.String() == "pgup":
+			m.results.PageUp()
+			return m, nil
+		case msg.String() == "pgdown":
+			m.results.PageDown()
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// submit runs a slash command or starts a generation.
+func (m *tuiModel) submit(prompt string) tea.Cmd {
+	command, commandArg, _ := strings.Cut(prompt, " ")
+	switch command {
+	case "/models":
+		var options []huh.Option[string]
+		for modelName, model := range m.cfg.GetModels() {
+			options = append(options, huh.NewOption(model.DisplayName, modelName))
+		}
+		m.selectedModel = m.model
+		return m.openForm(newForm(m.cfg, huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(i18n.T("model.title")).
+				Description(i18n.T("model.description")).
+				Options(options...).
+				Value(&m.selectedModel),
+		)), func() {
+			m.model = m.selectedModel
+			m.settings = sessionSettings(m.cfg, m.settingsByModel, m.model)
+			m.input.Placeholder = i18n.T("prompt.description", m.model)
+		})
+
+	case "/settings":
+		return m.openForm(newForm(m.cfg, m.settings.HuhGroups()...), func() {})
+
+	case "/jobs":
+		m.showJobs = !m.showJobs
+		m.layout()
+
+	case "/preset":
+		var buf bytes.Buffer
+		if err := runPresetCommand(&buf, &m.cfg, commandArg, m.settings); err != nil {
+			fmt.Fprintln(&buf, err)
+		}
+		m.print(buf.String())
+
+	case "/quota":
+		// providers may be asked over the network
+		ctx, cfg := m.ctx, m.cfg
+		return func() tea.Msg {
+			var buf bytes.Buffer
+			printQuota(ctx, &buf, cfg)
+			return tuiOutputMsg(buf.String())
+		}
+
+	case "/style":
+		var buf bytes.Buffer
+		runStyleCommand(&buf, &m.style, commandArg)
+		m.print(buf.String())
+
+	case "/ref":
+		// URLs are downloaded in the background
+		ctx, refs := m.ctx, slices.Clone(m.refs)
+		return func() tea.Msg {
+			var buf bytes.Buffer
+			runRefCommand(ctx, &buf, &refs, commandArg)
+			return tuiRefsMsg{refs: refs, output: buf.String()}
+		}
+
+	case "/share":
+		var expires time.Duration
+		if commandArg != "" {
+			var err error
+			if expires, err = time.ParseDuration(strings.TrimSpace(commandArg)); err != nil {
+				m.print(i18n.T("invalid_expiry", err))
+				break
+			}
+		}
+		ctx, cfg, images := m.ctx, m.cfg, m.lastImages
+		return func() tea.Msg {
+			var buf bytes.Buffer
+			shareLastImages(ctx, &buf, cfg, images, expires)
+			return tuiOutputMsg(buf.String())
+		}
+
+	case "/help":
+		var buf bytes.Buffer
+		printHelp(&buf)
+		m.print(buf.String())
+
+	case "/exit":
+		return tea.Quit
+
+	case "/retry":
+		if m.lastPrompt == "" {
+			break
+		}
+		prompt = m.lastPrompt
+		fallthrough
+
+	default:
+		if strings.HasPrefix(prompt, "/") {
+			m.print(i18n.T("invalid_command", prompt))
+			break
+		}
+		m.print(m.accentStyle.Render("> " + prompt))
+		generatePrompt, generateSettings := applyStyle(m.style, prompt), m.settings.Clone()
+		generateModel := m.model
+		generateRefs := slices.Clone(m.refs)
+		cfg := m.cfg
+		m.lastPrompt = prompt
+		log.Println(prompt)
+		m.jobs.start(m.jobsCtx, generateModel, prompt, func(ctx context.Context) ([]providers.Image, error) {
+			return generateWithReferences(ctx, cfg, generateModel, generatePrompt, generateSettings, generateRefs)
+		})
+	}
+	return nil
+}
+
+func (m *tuiModel) openForm(form *huh.Form, done func()) tea.Cmd {
+	m.form = form.WithWidth(m.width).WithShowHelp(true)
+	m.done = done
+	m.input.Blur()
+	return m.form.Init()
+}
+
+// collectJobs prints the warnings and the results of the jobs that finished
+// since the last call.
+func (m *tuiModel) collectJobs() {
+	for _, w := range takeUserWarnings() {
+		m.print(w)
+	}
+	for _, j := range m.jobs.takeFinished() {
+		var buf bytes.Buffer
+		fmt.Fprintln(&buf, j.summary())
+		switch {
+		case j.status == jobCancelled:
+		case j.err != nil:
+			fmt.Fprintln(&buf, i18n.T("generate_failed", j.err))
+		default:
+			for _, img := range j.images {
+				fprintImage(&buf, img)
+				if img.Path != "" && img.Media != providers.MediaVideo && !img.Safety.Filtered {
+					if preview := halfBlockPreview(img.Path, tuiPreviewWidth); preview != "" {
+						fmt.Fprintln(&buf, preview)
+					}
+				}
+				if !img.Safety.Filtered {
+					// a fallback may have produced the image
+					m.cost += modelPrice(cmp.Or(img.Model, j.model))
+				}
+			}
+			m.lastImages = j.images
+		}
+		m.print(buf.String())
+	}
+}
+
+// print appends to the results and scrolls to the end.
+func (m *tuiModel) print(s string) {
+	m.output.WriteString(strings.TrimRight(s, "\n"))
+	m.output.WriteByte('\n')
+	m.setContent()
+	m.results.GotoBottom()
+}
+
+// setContent wraps the output to the width of the results.
+func (m *tuiModel) setContent() {
+	m.results.SetContent(lipgloss.NewStyle().Width(m.results.Width).Render(m.output.String()))
+}
+
+func (m *tuiModel) layout() {
+	resultsWidth := m.width
+	if m.showJobs {
+		resultsWidth -= tuiJobsWidth
+	}
+	m.results.Width = max(resultsWidth, 0)
+	// the input has a line above it and the status bar below
+	m.results.Height = max(m.height-tuiInputHeight-2, 0)
+	m.input.SetWidth(m.width)
+	m.setContent()
+}
+
+func (m *tuiModel) View() string {
+	if m.form != nil {
+		return m.form.View()
+	}
+	results := m.results.View()
+	if m.showJobs {
+		results = lipgloss.JoinHorizontal(lipgloss.Top, results, m.jobsView())
+	}
+	divider := m.statusStyle.Render(strings.Repeat("─", max(m.width, 0)))
+	return lipgloss.JoinVertical(lipgloss.Left, results, divider, m.input.View(), m.statusView())
+}
+
+func (m *tuiModel) jobsView() string {
+	lines := m.jobs.summaries()
+	if len(lines) == 0 {
+		lines = []string{i18n.T("no_jobs")}
+	}
+	// the newest jobs are at the bottom, next to the input
+	if len(lines) > m.results.Height {
+		lines = lines[len(lines)-m.results.Height:]
+	}
+	return lipgloss.NewStyle().
+		Width(tuiJobsWidth-2).
+		Height(m.results.Height).
+		MaxHeight(m.results.Height).
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		PaddingLeft(1).
+		Render(strings.Join(lines, "\n"))
+}
+
+func (m *tuiModel) statusView() string {
+	status := []string{m.accentStyle.Render(m.model), i18n.T("tui.cost", m.cost)}
+	if n := m.jobs.running(); n > 0 {
+		status = append(status, i18n.T("tui.running", n))
+	}
+	if m.style.Name != "" {
+		status = append(status, m.style.Name)
+	}
+	if len(m.refs) > 0 {
+		status = append(status, i18n.T("tui.refs", len(m.refs)))
+	}
+	status = append(status, i18n.T("tui.keys", m.keys.submit.Help().Key, m.keys.newLine.Help().Key, m.keys.refine.Help().Key, m.keys.abort.Help().Key))
+	return m.statusStyle.Render(strings.Join(status, " · "))
+}
+
+// halfBlockPreview renders an image with colored half blocks, two pixels per
+// cell, as the inline images of viu don't work in a full screen app.
+func halfBlockPreview(imagePath string, width int) string {
+	if noColor() {
+		return ""
+	}
+	f, err := os.Open(imagePath)
+	if err != nil {
+		return ""
+	}
+	src, _, err := image.Decode(f)
+	f.Close()
+	if err != nil {
+		return ""
+	}
+	b := src.Bounds()
+	rows := max(1, width*b.Dy()/b.Dx()/2)
+	img := sizes.Resize(src, sizes.Size{Width: width, Height: rows * 2})
+	var sb strings.Builder
+	for y := 0; y < rows*2; y += 2 {
+		for x := range width {
+			top, bottom := img.NRGBAAt(x, y), img.NRGBAAt(x, y+1)
+			sb.WriteString(lipgloss.NewStyle().
+				Foreground(lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", top.R, top.G, top.B))).
+				Background(lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", bottom.R, bottom.G, bottom.B))).
+				Render("▀"))
+		}
+		sb.WriteByte('\n')
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// isTerminal reports if stdout is a terminal, which the full screen session
+// needs.
+func isTerminal() bool {
+	fi, err := os.Stdout.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
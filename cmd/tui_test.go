@@ -0,0 +1,52 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/providers"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTUIRemappedSubmit(t *testing.T) {
+	cfg := config.Config{Keys: config.KeyBindings{Submit: []string{"ctrl+s"}, NewLine: []string{"enter"}}}
+	m := newTUIModel(context.Background(), context.Background(), cfg, &jobList{}, "mock/placeholder", map[string]providers.ModelSettings{})
+	m.input.SetValue("/jobs")
+
+	// enter inserts a line break instead of submitting
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.showJobs {
+		t.Fatal("enter submitted the prompt")
+	}
+	if got := m.input.Value(); got != "/jobs\n" {
+		t.Fatalf("input = %q, want a line break after the prompt", got)
+	}
+
+	m.input.SetValue("/jobs")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if !m.showJobs {
+		t.Fatal("ctrl+s did not submit the prompt")
+	}
+	if got := m.input.Value(); got != "" {
+		t.Fatalf("input = %q, want it to be reset", got)
+	}
+}
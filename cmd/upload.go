@@ -0,0 +1,96 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/i18n"
+	"github.com/bloodmagesoftware/climage/upload"
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+var uploadCmd = &cobra.Command{
+	Use:   "upload",
+	Short: "Manage upload targets",
+	Long:  `Manage the upload targets generated images are copied to. Targets are configured in the "upload_targets" list of the config file.`,
+}
+
+var uploadLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Authorize a Google Drive or Dropbox upload target",
+	Long:  `Authorize CLImage to upload to a Google Drive or Dropbox target. A browser URL is printed, after granting access the token is stored in the credential store and refreshed automatically.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return i18n.Errorf("error.config", err)
+		}
+
+		var options []huh.Option[int]
+		for i, t := range cfg.UploadTargets {
+			if !upload.NeedsLogin(t) {
+				continue
+			}
+			label := t.Type
+			if t.FolderID != "" {
+				label += " " + t.FolderID
+			} else if t.Path != "" {
+				label += " " + t.Path
+			}
+			options = append(options, huh.NewOption(label, i))
+		}
+		if len(options) == 0 {
+			return i18n.Errorf("error.no_oauth_targets")
+		}
+
+		index := options[0].Value
+		if len(options) > 1 {
+			if err := requireInteractive("selecting the upload target"); err != nil {
+				return err
+			}
+			if err := newForm(cfg, huh.NewGroup(
+				huh.NewSelect[int]().
+					Title(i18n.T("upload.target.title")).
+					Description(i18n.T("upload.target.description")).
+					Options(options...).
+					Value(&index),
+			)).Run(); err != nil {
+				return i18n.Errorf("error.upload_target_form", err)
+			}
+		}
+
+		t := cfg.UploadTargets[index]
+		if err := upload.Login(cmd.Context(), t, func(url string) {
+			fmt.Println(i18n.T("upload.open_url"))
+			fmt.Println(url)
+		}); err != nil {
+			return i18n.Errorf("error.upload_login", index, err)
+		}
+		fmt.Println(i18n.T("upload.authorized", t.Type))
+		return nil
+	},
+}
+
+func init() {
+	uploadCmd.AddCommand(uploadLoginCmd)
+
+	rootCmd.AddCommand(uploadCmd)
+}
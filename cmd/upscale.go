@@ -0,0 +1,112 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/spf13/cobra"
+)
+
+// upscaleModel is the model name upscales are recorded with in the history,
+// priced with UpscaleProvider.UpscalePrice.
+const upscaleModel = "upscale"
+
+var upscaleFlags struct {
+	provider string
+	factor   int
+}
+
+var upscaleCmd = &cobra.Command{
+	Use:   "upscale <image|url>",
+	Short: "Upscale an image",
+	Long:  `Upscale an existing image file or an image at an http or https URL, e.g. a 1K generation to 2K with --factor 2 or to 4K with --factor 4. The provider of the default model is used unless --provider is set.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+		providerName := upscaleFlags.provider
+		if providerName == "" {
+			model, _, err := resolveModel(cfg, cfg.DefaultModel)
+			if err != nil {
+				return err
+			}
+			providerName, _, _ = strings.Cut(model, "/")
+		}
+		image, err := providers.ReadInputImage(cmd.Context(), args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read image: %w", err)
+		}
+
+		prompt := fmt.Sprintf("upscale x%d %s", upscaleFlags.factor, args[0])
+		images, err := upscaleImage(cmd.Context(), cfg, providerName, prompt, image, upscaleFlags.factor)
+		images, err = finishImages(cmd.Context(), cfg, providerName+"/"+upscaleModel, prompt, nil, postProcessing{}, images, err)
+		if err != nil {
+			return fmt.Errorf("failed to upscale image: %w", err)
+		}
+		for _, img := range images {
+			printImage(img)
+		}
+		return nil
+	},
+}
+
+func upscaleImage(ctx context.Context, cfg config.Config, providerName string, prompt string, image []byte, factor int) ([]providers.Image, error) {
+	pp, err := providers.GetProviderByName(providerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider: %w", err)
+	}
+	up, ok := pp.(providers.UpscaleProvider)
+	if !ok {
+		return nil, providers.NewError(providers.ErrorKindInvalidSettings, providerName, fmt.Errorf("provider %s can't upscale images", providerName))
+	}
+	if !slices.Contains(up.UpscaleFactors(), factor) {
+		return nil, providers.NewError(providers.ErrorKindInvalidSettings, providerName, fmt.Errorf("provider %s supports the upscale factors %v, got %d", providerName, up.UpscaleFactors(), factor))
+	}
+	if err := checkBudget(cfg, providerName); err != nil {
+		return nil, err
+	}
+	if err := requireOutDir(); err != nil {
+		return nil, err
+	}
+	release, err := providers.Schedule(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if err := auditRequest(cfg, providerName+"/"+upscaleModel, prompt, nil); err != nil {
+		return nil, err
+	}
+	emitProgress(progressEvent{Event: progressSubmitted, Model: providerName + "/" + upscaleModel})
+	return up.UpscaleImage(ctx, image, factor)
+}
+
+func init() {
+	upscaleCmd.Flags().StringVarP(&upscaleFlags.provider, "provider", "p", "", "provider to upscale with, e.g. google")
+	upscaleCmd.Flags().IntVarP(&upscaleFlags.factor, "factor", "f", 2, "upscale factor")
+
+	rootCmd.AddCommand(upscaleCmd)
+}
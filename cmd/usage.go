@@ -0,0 +1,127 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/history"
+	"github.com/spf13/cobra"
+)
+
+var usageFlags struct {
+	since   string
+	until   string
+	groupBy string
+	csv     bool
+}
+
+// usageGroups maps the --group-by values to the group key of an entry.
+var usageGroups = map[string]func(e history.Entry) string{
+	"model": func(e history.Entry) string { return e.Model },
+	"provider": func(e history.Entry) string {
+		provider, _, _ := strings.Cut(e.Model, "/")
+		return provider
+	},
+	"day":   func(e history.Entry) string { return e.Time.Local().Format(time.DateOnly) },
+	"month": func(e history.Entry) string { return e.Time.Local().Format("2006-01") },
+}
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Summarize image counts and spend",
+	Long:  `Summarize the number of generations and images and the estimated spend from the local history, grouped by model, provider, day or month. Limit the report to a date range with --since and --until and export it with --csv, e.g. for expense reports. The spend is estimated from list prices and may differ from your bill.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		groupKey, ok := usageGroups[usageFlags.groupBy]
+		if !ok {
+			return fmt.Errorf("invalid --group-by %q, expected one of %s", usageFlags.groupBy, strings.Join(slices.Sorted(maps.Keys(usageGroups)), ", "))
+		}
+		var since, until time.Time
+		var err error
+		if usageFlags.since != "" {
+			if since, err = time.ParseInLocation(time.DateOnly, usageFlags.since, time.Local); err != nil {
+				return fmt.Errorf("invalid --since %q, expected YYYY-MM-DD", usageFlags.since)
+			}
+		}
+		if usageFlags.until != "" {
+			if until, err = time.ParseInLocation(time.DateOnly, usageFlags.until, time.Local); err != nil {
+				return fmt.Errorf("invalid --until %q, expected YYYY-MM-DD", usageFlags.until)
+			}
+			// include the whole day
+			until = until.AddDate(0, 0, 1)
+		}
+
+		entries, err := history.Read()
+		if err != nil {
+			return err
+		}
+		var total usage
+		groups := make(map[string]*usage)
+		for _, e := range entries {
+			if e.Time.Before(since) || (!until.IsZero() && !e.Time.Before(until)) {
+				continue
+			}
+			key := groupKey(e)
+			if groups[key] == nil {
+				groups[key] = &usage{}
+			}
+			groups[key].add(e)
+			total.add(e)
+		}
+		keys := slices.Sorted(maps.Keys(groups))
+
+		if usageFlags.csv {
+			w := csv.NewWriter(os.Stdout)
+			_ = w.Write([]string{usageFlags.groupBy, "generations", "images", "filtered", "cost_usd"})
+			for _, key := range keys {
+				u := groups[key]
+				_ = w.Write([]string{key, strconv.Itoa(u.generations), strconv.Itoa(u.images), strconv.Itoa(u.filtered), strconv.FormatFloat(u.cost, 'f', 2, 64)})
+			}
+			w.Flush()
+			return w.Error()
+		}
+
+		if len(keys) == 0 {
+			fmt.Println("no generations in this period")
+			return nil
+		}
+		for _, key := range keys {
+			fmt.Printf("%-40s %s\n", key, groups[key])
+		}
+		fmt.Printf("%-40s %s\n", "total", total)
+		return nil
+	},
+}
+
+func init() {
+	usageCmd.Flags().StringVar(&usageFlags.since, "since", "", "first day to include, e.g. 2025-01-01")
+	usageCmd.Flags().StringVar(&usageFlags.until, "until", "", "last day to include, e.g. 2025-01-31")
+	usageCmd.Flags().StringVar(&usageFlags.groupBy, "group-by", "model", "group by \"model\", \"provider\", \"day\" or \"month\"")
+	usageCmd.Flags().BoolVar(&usageFlags.csv, "csv", false, "print the report as CSV")
+
+	rootCmd.AddCommand(usageCmd)
+}
@@ -0,0 +1,135 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/c2pa"
+	"github.com/spf13/cobra"
+)
+
+var verifyFlags struct {
+	json bool
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <file>",
+	Short: "Show the content credentials of an image",
+	Long: `Show the C2PA content credentials of a PNG or JPEG image: who signed the manifest, which tool created the image, whether it declares AI generated content and whether the image was changed after signing. Some providers attach content credentials to generated images, climage saves them unchanged, but resizing, tiling and other post processing remove them.
+
+The signature is checked against the certificate in the manifest. Whether the certificate chains to a root of the system's trust store is shown, the C2PA trust list isn't checked. Exits with an error if there are no content credentials or they are invalid.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		report, err := c2pa.Verify(args[0])
+		if err != nil {
+			return err
+		}
+		if verifyFlags.json {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(report); err != nil {
+				return err
+			}
+		} else {
+			printReport(args[0], report)
+		}
+		if !report.Active().Signature.Valid || report.ContentHash == "mismatch" {
+			return errors.New("content credentials are invalid")
+		}
+		return nil
+	},
+}
+
+func printReport(path string, report c2pa.Report) {
+	active := report.Active()
+	fmt.Printf("file:       %s\n", path)
+	if active.Title != "" {
+		fmt.Printf("title:      %s\n", active.Title)
+	}
+	if active.ClaimGenerator != "" {
+		fmt.Printf("generator:  %s\n", active.ClaimGenerator)
+	}
+	printSignature(active.Signature)
+	switch report.ContentHash {
+	case "match":
+		fmt.Println("content:    unchanged since signing")
+	case "mismatch":
+		fmt.Println("content:    changed after signing")
+	default:
+		fmt.Println("content:    not checked")
+	}
+	fmt.Printf("AI content: %t\n", report.AIGenerated())
+	if len(active.Actions) > 0 {
+		fmt.Println("actions:")
+		for _, a := range active.Actions {
+			line := "  " + a.Action
+			if a.DigitalSourceType != "" {
+				line += ", " + a.DigitalSourceType[strings.LastIndex(a.DigitalSourceType, "/")+1:]
+			}
+			if a.SoftwareAgent != "" {
+				line += ", by " + a.SoftwareAgent
+			}
+			fmt.Println(line)
+		}
+	}
+	if len(active.Ingredients) > 0 {
+		fmt.Printf("ingredients: %s\n", strings.Join(active.Ingredients, ", "))
+	}
+	fmt.Printf("assertions: %s\n", strings.Join(active.Assertions, ", "))
+	if n := len(report.Manifests) - 1; n > 0 {
+		fmt.Printf("%d more manifests of ingredients\n", n)
+	}
+}
+
+func printSignature(sig c2pa.Signature) {
+	if sig.Signer != "" {
+		fmt.Printf("signed by:  %s\n", sig.Signer)
+		fmt.Printf("issued by:  %s\n", sig.Issuer)
+		fmt.Printf("valid:      %s to %s\n", sig.NotBefore.Local().Format(time.DateOnly), sig.NotAfter.Local().Format(time.DateOnly))
+	}
+	switch {
+	case !sig.Valid:
+		fmt.Printf("signature:  invalid, %s\n", sig.Error)
+	case sig.Trusted:
+		fmt.Println("signature:  valid, trusted by the system")
+	default:
+		fmt.Println("signature:  valid, the issuer isn't in the system's trust store")
+	}
+}
+
+func init() {
+	verifyCmd.Flags().BoolVar(&verifyFlags.json, "json", false, "print the content credentials as JSON")
+
+	rootCmd.AddCommand(verifyCmd)
+}
+
+// warnContentCredentials warns before post processing removes the content
+// credentials of an image.
+func warnContentCredentials(imagePath string) {
+	if c2pa.Has(imagePath) {
+		log.Printf("warning: post processing removes the content credentials of %s", imagePath)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/spf13/cobra"
+)
+
+var videoFlags struct {
+	prompt string
+	model  string
+	preset string
+	set    []string
+}
+
+var videoCmd = &cobra.Command{
+	Use:   "video --prompt <prompt>",
+	Short: "Generate a video from a prompt",
+	Long:  `Generate a video with a video model like Veo 3 and save it as mp4 next to the generated images. The default model is used if it is a video model, otherwise the first available video model. A preview of a few frames is shown if ffmpeg and viu are installed.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+		if videoFlags.prompt == "" {
+			return errors.New("--prompt is required")
+		}
+		model, settings, err := resolveVideoModel(cfg, videoFlags.model)
+		if err != nil {
+			return err
+		}
+		settings, err = applySettingFlags(cfg, settings, videoFlags.preset, videoFlags.set)
+		if err != nil {
+			return err
+		}
+		videos, err := generate(cmd.Context(), cfg, model, videoFlags.prompt, settings, postProcessing{})
+		if err != nil {
+			return fmt.Errorf("failed to generate video: %w", err)
+		}
+		for _, v := range videos {
+			printImage(v)
+			showMedia(v)
+		}
+		return nil
+	},
+}
+
+// resolveVideoModel returns the requested video model. Without a request the
+// default model is used if it is a video model, otherwise the first
+// available one.
+func resolveVideoModel(cfg config.Config, model string) (string, providers.ModelSettings, error) {
+	wanted := model
+	if wanted == "" {
+		wanted = cfg.DefaultModel
+	}
+	var first string
+	var firstSettings providers.ModelSettings
+	for modelName, pm := range cfg.GetModels() {
+		if pm.Media != providers.MediaVideo {
+			continue
+		}
+		if modelName == wanted {
+			return modelName, pm.Settings, nil
+		}
+		if first == "" {
+			first, firstSettings = modelName, pm.Settings
+		}
+	}
+	if model != "" {
+		return "", nil, fmt.Errorf("video model %q is not available", model)
+	}
+	if first == "" {
+		return "", nil, errors.New("no video model available, log in to a provider with video models like google")
+	}
+	return first, firstSettings, nil
+}
+
+// showVideo renders a preview of a video inline. ffmpeg extracts a frame
+// every two seconds into a grid that is rendered like an image. Nothing is
+// rendered if ffmpeg is not installed.
+func showVideo(filePath string) {
+	if noColor() {
+		return
+	}
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return
+	}
+	dir, err := os.MkdirTemp("", "climage-preview-")
+	if err != nil {
+		return
+	}
+	defer os.RemoveAll(dir)
+	preview := filepath.Join(dir, "preview.png")
+	cmd := exec.Command("ffmpeg", "-loglevel", "error", "-i", filePath,
+		"-vf", "fps=1/2,scale=480:-1,tile=2x2", "-frames:v", "1", preview)
+	if err := cmd.Run(); err != nil {
+		return
+	}
+	showImage(preview)
+}
+
+func init() {
+	videoCmd.Flags().StringVarP(&videoFlags.prompt, "prompt", "p", "", "prompt describing the video")
+	videoCmd.Flags().StringVarP(&videoFlags.model, "model", "m", "", "video model to use, e.g. google/veo-3.0-generate-001")
+
+	videoCmd.Flags().StringVar(&videoFlags.preset, "preset", "", "settings preset to apply, see /preset in the interactive session")
+	videoCmd.Flags().StringArrayVar(&videoFlags.set, "set", nil, "set a model setting, e.g. --set duration_seconds=4, can be repeated")
+
+	rootCmd.AddCommand(videoCmd)
+}
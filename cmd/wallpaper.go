@@ -0,0 +1,36 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"errors"
+
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/bloodmagesoftware/climage/wallpaper"
+)
+
+// setWallpaper sets the first generated image as the desktop background.
+func setWallpaper(images []providers.Image) error {
+	for _, img := range images {
+		if img.Path != "" && !img.Safety.Filtered {
+			return wallpaper.Set(img.Path)
+		}
+	}
+	return errors.New("no image to set as wallpaper, the images were filtered or not kept locally")
+}
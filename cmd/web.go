@@ -0,0 +1,115 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/config"
+	"github.com/bloodmagesoftware/climage/history"
+	"github.com/bloodmagesoftware/climage/sidecar"
+	"github.com/bloodmagesoftware/climage/web"
+	"github.com/spf13/cobra"
+)
+
+var webFlags struct {
+	addr    string
+	noRerun bool
+}
+
+var webCmd = &cobra.Command{
+	Use:   "web",
+	Short: "Browse the generation history in a web browser",
+	Long: `Serve a small gallery of the generation history: browse thumbnails newest first, view the prompt, model and recorded settings, download the images and re-run generations. Only files recorded in the history are served.
+
+The gallery has no authentication and listens on localhost by default. To review the generations of a remote machine, forward the port over SSH, e.g. ssh -L 8080:localhost:8080 host, and open http://localhost:8080. Requests are only answered for localhost and the host of --addr. Re-runs use the settings of the sidecar if there is one and can be disabled with --no-rerun.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+		s := &web.Server{Addr: webFlags.addr}
+		if !webFlags.noRerun {
+			s.Rerun = func(ctx context.Context, e history.Entry, newSeed bool) error {
+				return rerunEntry(ctx, cfg, e, newSeed)
+			}
+		}
+		srv := &http.Server{
+			Addr:              webFlags.addr,
+			Handler:           s.Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			<-cmd.Context().Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = srv.Shutdown(ctx)
+		}()
+		fmt.Printf("serving the gallery on http://%s, stop with Ctrl+C\n", webFlags.addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to serve gallery: %w", err)
+		}
+		return nil
+	},
+}
+
+// rerunEntry generates a history entry again with the prompt, settings and
+// size of its sidecar if there is one.
+func rerunEntry(ctx context.Context, cfg config.Config, e history.Entry, newSeed bool) error {
+	model, settings, err := resolveModel(cfg, e.Model)
+	if err != nil {
+		return err
+	}
+	if model != e.Model {
+		return fmt.Errorf("model %q is not available", e.Model)
+	}
+	prompt := e.Prompt
+	size := ""
+	tile := false
+	if len(e.Images) > 0 {
+		if meta, err := sidecar.Read(sidecar.Path(e.Images[0])); err == nil {
+			prompt = meta.Prompt
+			size = meta.Size
+			tile = meta.Tiled
+			settings = applySettingValues(settings, sidecarSettingValues(meta, newSeed))
+		}
+	}
+	post, settings, err := useOutputSize(postProcessing{}, settings, size)
+	if err != nil {
+		return err
+	}
+	post.tile = tile
+	images, err := generate(ctx, cfg, model, prompt, settings, post)
+	for _, img := range images {
+		printImage(img)
+	}
+	return err
+}
+
+func init() {
+	webCmd.Flags().StringVar(&webFlags.addr, "addr", "localhost:8080", "address to listen on, anyone who can reach it can generate with your accounts unless --no-rerun is set")
+	webCmd.Flags().BoolVar(&webFlags.noRerun, "no-rerun", false, "disable re-running generations from the gallery")
+
+	rootCmd.AddCommand(webCmd)
+}
@@ -0,0 +1,83 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package config
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+// Character is a named subject that can be referenced in prompts as @name.
+type Character struct {
+	Description string   `json:"description"`
+	Images      []string `json:"images,omitempty"`
+}
+
+var characterReference = regexp.MustCompile(`@([A-Za-z0-9_-]+)`)
+
+// ExpandSnippets replaces every @name reference of a snippet with its text.
+// Characters take precedence over snippets of the same name and snippets may
+// reference characters, but not other snippets.
+func (cfg Config) ExpandSnippets(prompt string) string {
+	return characterReference.ReplaceAllStringFunc(prompt, func(ref string) string {
+		name := ref[1:]
+		if _, ok := cfg.Characters[name]; ok {
+			return ref
+		}
+		if text, ok := cfg.Snippets[name]; ok {
+			return text
+		}
+		return ref
+	})
+}
+
+// ExpandCharacters expands the snippets and replaces every @name reference of
+// a known character with its description. If subjectIDs is set, characters
+// with reference images are additionally tagged with their subject id, e.g.
+// "a red fox [1]". Unknown references are left untouched.
+func (cfg Config) ExpandCharacters(prompt string, subjectIDs bool) (string, []providers.Subject) {
+	prompt = cfg.ExpandSnippets(prompt)
+	var subjects []providers.Subject
+	ids := make(map[string]int)
+	expanded := characterReference.ReplaceAllStringFunc(prompt, func(ref string) string {
+		name := ref[1:]
+		c, ok := cfg.Characters[name]
+		if !ok {
+			return ref
+		}
+		id, ok := ids[name]
+		if !ok {
+			id = len(subjects) + 1
+			ids[name] = id
+			subjects = append(subjects, providers.Subject{
+				ID:          id,
+				Name:        name,
+				Description: c.Description,
+				Images:      c.Images,
+			})
+		}
+		if !subjectIDs || len(c.Images) == 0 {
+			return c.Description
+		}
+		return c.Description + " [" + strconv.Itoa(id) + "]"
+	})
+	return expanded, subjects
+}
@@ -22,29 +22,102 @@ import (
 	"encoding/json"
 	"fmt"
 	"iter"
+	"log"
 	"os"
 	"path/filepath"
 	"strconv"
 
+	"github.com/bloodmagesoftware/climage/i18n"
+	"github.com/bloodmagesoftware/climage/notify"
 	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/bloodmagesoftware/climage/sign"
+	"github.com/bloodmagesoftware/climage/upload"
 )
 
-func getConfigFilePath() (string, error) {
+// Dir returns the directory of the config file, which contains the data dir
+// too.
+func Dir() (string, error) {
 	userConfigDir, err := os.UserConfigDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get user config dir: %w", err)
 	}
-	return filepath.Join(userConfigDir, "climage", "config.json"), nil
+	return filepath.Join(userConfigDir, "climage"), nil
+}
+
+func getConfigFilePath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
 }
 
 type Config struct {
-	Providers            []Provider        `json:"providers"`
-	DefaultModel         string            `json:"default_model"`
-	DefaultModelSettings map[string]string `json:"default_model_settings"`
+	Providers            []Provider           `json:"providers"`
+	DefaultModel         string               `json:"default_model"`
+	DefaultModelSettings map[string]string    `json:"default_model_settings"`
+	Characters           map[string]Character `json:"characters,omitempty"`
+	// Snippets are reusable prompt fragments expanded from @name, see
+	// ExpandSnippets.
+	Snippets map[string]string `json:"snippets,omitempty"`
+	// CredentialStore is "keyring" (default) or "file".
+	CredentialStore providers.CredentialStore `json:"credential_store,omitempty"`
+	UploadTargets   []upload.Target           `json:"upload_targets,omitempty"`
+	// KeepLocalFiles can be set to false to delete local files after they
+	// were uploaded to all upload targets.
+	KeepLocalFiles *bool `json:"keep_local_files,omitempty"`
+	// Share is where /share uploads images to, 0x0.st by default.
+	Share upload.ShareTarget `json:"share,omitzero"`
+	// Webhooks are notified with the prompt and images after every
+	// generation.
+	Webhooks []notify.Webhook `json:"webhooks,omitempty"`
+	// Locale selects the language of the interactive UI, e.g. "de". The
+	// environment (LANG) is used if empty.
+	Locale string      `json:"locale,omitempty"`
+	Theme  Theme       `json:"theme,omitzero"`
+	Keys   KeyBindings `json:"keys,omitzero"`
+	// OutputDir is where generated images are saved. The climage folder in
+	// the downloads dir is used if empty.
+	OutputDir string `json:"output_dir,omitempty"`
+	// OutputFolder is the user folder the climage folder is created in if
+	// OutputDir is empty, "downloads" (default) or "pictures".
+	OutputFolder string `json:"output_folder,omitempty"`
+	// OutputLayout is the subdirectory of the output dir images are saved
+	// to. YYYY, MM and DD are replaced with the date, "YYYY/MM/DD" is used
+	// if empty and "flat" saves directly into the output dir.
+	OutputLayout string `json:"output_layout,omitempty"`
+	// Sidecars enables writing a .json file with the prompt, model, settings
+	// and cost next to every image.
+	Sidecars bool `json:"sidecars,omitempty"`
+	// Checksums enables appending the SHA-256 hashes of every saved image and
+	// sidecar to the SHA256SUMS file in its directory, see 'climage
+	// checksums'.
+	Checksums bool `json:"checksums,omitempty"`
+	// Signing signs every saved image and sidecar, and the checksum
+	// manifest, with GPG or minisign.
+	Signing sign.Options `json:"signing,omitzero"`
+	// AuditLog enables the tamper-evident log of all generation requests,
+	// see 'climage audit'.
+	AuditLog bool `json:"audit_log,omitempty"`
+	// Budgets are spend caps per provider name, see Budget.
+	Budgets map[string]Budget `json:"budgets,omitempty"`
+	// Network configures a proxy and additional trusted certificates.
+	Network providers.NetworkOptions `json:"network,omitzero"`
+	// Presets are named bundles of model settings, see /preset.
+	Presets map[string]Preset `json:"presets,omitempty"`
+	// Fallbacks are the models tried in order when a generation with the
+	// model of the key fails with a quota or outage error, e.g.
+	// {"bfl/flux-pro": ["together/flux-schnell", "google/imagen-4-fast"]}.
+	Fallbacks map[string][]string `json:"fallbacks,omitempty"`
+	// VisionModel is the model 'climage describe' uses, e.g.
+	// "google-ai/gemini-2.5-flash". The default vision model of the default
+	// model's provider is used if empty.
+	VisionModel string `json:"vision_model,omitempty"`
 }
 
 type Provider struct {
 	Name string `json:"name"`
+	providers.Options
 }
 
 func (p Provider) Get() (providers.Provider, error) {
@@ -63,7 +136,9 @@ func GetConfig() (Config, error) {
 	}
 	_ = os.MkdirAll(filepath.Dir(userConfigPath), 0755)
 	if _, err := os.Stat(userConfigPath); os.IsNotExist(err) {
-		return Config{}, nil
+		// without a config, e.g. in a fresh container, the providers with
+		// credentials in the environment are enabled
+		return Config{Providers: envProviders()}, nil
 	}
 	f, err := os.Open(userConfigPath)
 	if err != nil {
@@ -76,17 +151,74 @@ func GetConfig() (Config, error) {
 		return Config{}, fmt.Errorf("failed to decode config: %w", err)
 	}
 
+	if err := providers.SetCredentialStore(config.CredentialStore); err != nil {
+		return Config{}, err
+	}
+	if err := providers.ConfigureNetwork(config.Network); err != nil {
+		return Config{}, err
+	}
+	if err := config.Theme.validate(); err != nil {
+		return Config{}, err
+	}
+	if err := config.Signing.Validate(); err != nil {
+		return Config{}, err
+	}
+	for provider, b := range config.Budgets {
+		if b.Daily < 0 || b.Monthly < 0 {
+			return Config{}, fmt.Errorf("budget of %q must not be negative", provider)
+		}
+	}
+	i18n.SetLocale(config.Locale)
+	if err := providers.SetOutFolder(config.OutputFolder); err != nil {
+		return Config{}, err
+	}
+	if err := providers.SetOutLayout(config.OutputLayout); err != nil {
+		return Config{}, err
+	}
+	providers.SetOutDir(config.OutputDir)
+	for _, p := range config.Providers {
+		if err := providers.Configure(p.Name, p.Options); err != nil {
+			return Config{}, err
+		}
+	}
+
 	// apply user default model settings
 	for _, m := range config.GetModels() {
 		for _, s := range m.Settings {
-			if v, ok := config.GetDefaultModelSetting(s.Name); ok && providers.IsOfType(v, s.Type) {
-				s.Value = v
+			v, ok := config.GetDefaultModelSetting(s.Name)
+			if !ok || s.Type == nil {
+				continue
+			}
+			if err := s.Type.Validate(v); err != nil {
+				log.Printf("warning: ignoring default model setting %q of %s: %v", s.Name, m.DisplayName, err)
+				continue
 			}
+			s.Value = v
 		}
 	}
 	return config, nil
 }
 
+func envProviders() []Provider {
+	var ps []Provider
+	for _, p := range providers.Providers {
+		if providers.HasEnvCredentials(p) {
+			ps = append(ps, Provider{Name: p.GetName()})
+		}
+	}
+	return ps
+}
+
+// Exists reports whether the config file exists.
+func Exists() bool {
+	userConfigPath, err := getConfigFilePath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(userConfigPath)
+	return err == nil
+}
+
 func (cfg Config) Save() error {
 	userConfigPath, err := getConfigFilePath()
 	if err != nil {
@@ -141,7 +273,7 @@ func (cfg Config) GetModels() iter.Seq2[string, providers.Model] {
 			if err != nil {
 				return
 			}
-			for _, m := range pp.GetModels() {
+			for _, m := range providers.Models(pp) {
 				if !yield(pp.GetName()+"/"+m.Name, m) {
 					return
 				}
@@ -0,0 +1,36 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package config
+
+// KeyBindings remaps the keys of the interactive session and forms. Keys are
+// named like "enter", "ctrl+s", "alt+enter" or "esc". Empty bindings keep the
+// defaults.
+type KeyBindings struct {
+	// Submit submits a field, "enter" by default.
+	Submit []string `json:"submit,omitempty"`
+	// NewLine inserts a line break into the prompt, "alt+enter" and
+	// "ctrl+j" by default.
+	NewLine []string `json:"new_line,omitempty"`
+	// Abort aborts the form, "ctrl+c" by default. In the interactive session
+	// the first abort cancels running jobs.
+	Abort []string `json:"abort,omitempty"`
+	// Refine cancels the latest job of the interactive session to edit its
+	// prompt, "esc" by default.
+	Refine []string `json:"refine,omitempty"`
+}
@@ -0,0 +1,70 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+// Preset is a named bundle of model setting values, e.g. a "thumbnail"
+// preset with "aspect_ratio": "16:9" and "number_of_images": "4".
+type Preset map[string]string
+
+// NewPreset records the values of the settings.
+func NewPreset(settings providers.ModelSettings) Preset {
+	p := make(Preset)
+	for _, s := range settings {
+		if s.Value != "" {
+			p[s.Name] = s.Value
+		}
+	}
+	return p
+}
+
+// PresetNames returns the names of the presets, sorted.
+func (cfg Config) PresetNames() []string {
+	return slices.Sorted(maps.Keys(cfg.Presets))
+}
+
+// ApplyPreset sets the values of the named preset on the settings. Values for
+// settings the model doesn't have are skipped, so a preset can be used with
+// every model.
+func (cfg Config) ApplyPreset(name string, settings providers.ModelSettings) error {
+	preset, ok := cfg.Presets[name]
+	if !ok {
+		return fmt.Errorf("preset %q not found", name)
+	}
+	for _, s := range settings {
+		v, ok := preset[s.Name]
+		if !ok {
+			continue
+		}
+		if s.Type != nil {
+			if err := s.Type.Validate(v); err != nil {
+				return fmt.Errorf("preset %q: invalid value %q for setting %q: %w", name, v, s.Name, err)
+			}
+		}
+		s.Value = v
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package config
+
+import "fmt"
+
+// Theme configures the look of the interactive forms.
+type Theme struct {
+	// Name is the base theme: "charm" (default), "dracula", "catppuccin",
+	// "base16" or "base".
+	Name string `json:"name,omitempty"`
+	// Background is "dark" or "light". It is detected from the terminal if
+	// empty.
+	Background string `json:"background,omitempty"`
+	// Accent colors the titles and buttons, Secondary the selection cursor
+	// and prompts. Colors are hex values like "#7571F9" or ANSI numbers.
+	Accent    string `json:"accent,omitempty"`
+	Secondary string `json:"secondary,omitempty"`
+}
+
+func (t Theme) validate() error {
+	switch t.Name {
+	case "", "charm", "dracula", "catppuccin", "base16", "base":
+	default:
+		return fmt.Errorf("unknown theme %q", t.Name)
+	}
+	switch t.Background {
+	case "", "dark", "light":
+	default:
+		return fmt.Errorf("invalid theme background %q: expected \"dark\" or \"light\"", t.Background)
+	}
+	return nil
+}
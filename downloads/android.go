@@ -0,0 +1,88 @@
+//go:build linux || freebsd || openbsd || netbsd || dragonfly
+
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package downloads
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// androidDownloadsDir returns the shared Download folder on Android, where
+// files are visible to other apps. Termux exposes it as ~/storage/downloads
+// after termux-setup-storage was run. Both Go's android and linux builds run
+// in Termux, so Android is detected at runtime.
+func androidDownloadsDir(home string) (string, bool) {
+	if isTermux() {
+		dir := filepath.Join(home, "storage", "downloads")
+		if isDir(dir) {
+			return dir, true
+		}
+	}
+	if os.Getenv("ANDROID_ROOT") == "" && os.Getenv("ANDROID_DATA") == "" {
+		return "", false
+	}
+	var candidates []string
+	if external := os.Getenv("EXTERNAL_STORAGE"); external != "" {
+		candidates = append(candidates, filepath.Join(external, "Download"))
+	}
+	candidates = append(candidates, "/storage/emulated/0/Download", "/sdcard/Download")
+	for _, dir := range candidates {
+		if isDir(dir) {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+// androidPicturesDir returns the shared Pictures folder on Android, see
+// androidDownloadsDir.
+func androidPicturesDir(home string) (string, bool) {
+	if isTermux() {
+		dir := filepath.Join(home, "storage", "pictures")
+		if isDir(dir) {
+			return dir, true
+		}
+	}
+	if os.Getenv("ANDROID_ROOT") == "" && os.Getenv("ANDROID_DATA") == "" {
+		return "", false
+	}
+	var candidates []string
+	if external := os.Getenv("EXTERNAL_STORAGE"); external != "" {
+		candidates = append(candidates, filepath.Join(external, "Pictures"))
+	}
+	candidates = append(candidates, "/storage/emulated/0/Pictures", "/sdcard/Pictures")
+	for _, dir := range candidates {
+		if isDir(dir) {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+func isTermux() bool {
+	return os.Getenv("TERMUX_VERSION") != "" || strings.Contains(os.Getenv("PREFIX"), "com.termux")
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
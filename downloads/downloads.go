@@ -10,6 +10,21 @@ var (
 	cacheErr  error
 )
 
+var (
+	picturesOnce sync.Once
+	picturesDir  string
+	picturesErr  error
+)
+
+// GetUserPicturesDir returns the user's Pictures directory, cached across calls.
+// It uses a platform-specific implementation in getPicturesDir().
+func GetUserPicturesDir() (string, error) {
+	picturesOnce.Do(func() {
+		picturesDir, picturesErr = getPicturesDir()
+	})
+	return picturesDir, picturesErr
+}
+
 // GetUserDownloadsDir returns the user's Downloads directory, cached across calls.
 // It uses a platform-specific implementation in getDownloadsDir().
 func GetUserDownloadsDir() (string, error) {
@@ -8,10 +8,10 @@ package downloads
 
 // Return a malloc/strdup'd UTF-8 path or NULL on failure.
 // Caller must free() the returned pointer.
-char* getDownloadsDir() {
+static char* getUserDir(NSSearchPathDirectory dir) {
     @autoreleasepool {
         NSArray *urls = [[NSFileManager defaultManager]
-            URLsForDirectory:NSDownloadsDirectory inDomains:NSUserDomainMask];
+            URLsForDirectory:dir inDomains:NSUserDomainMask];
         if ([urls count] > 0) {
             NSString *path = [[urls objectAtIndex:0] path];
             if (path == nil) return NULL;
@@ -22,6 +22,14 @@ char* getDownloadsDir() {
     }
     return NULL;
 }
+
+char* getDownloadsDir() {
+    return getUserDir(NSDownloadsDirectory);
+}
+
+char* getPicturesDir() {
+    return getUserDir(NSPicturesDirectory);
+}
 */
 import "C"
 
@@ -48,3 +56,20 @@ func getDownloadsDir() (string, error) {
 	}
 	return filepath.Join(home, "Downloads"), nil
 }
+
+func getPicturesDir() (string, error) {
+	cstr := C.getPicturesDir()
+	if cstr != nil {
+		defer C.free(unsafe.Pointer(cstr))
+		path := C.GoString(cstr)
+		if path != "" {
+			return path, nil
+		}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.New("could not determine Pictures folder")
+	}
+	return filepath.Join(home, "Pictures"), nil
+}
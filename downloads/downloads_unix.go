@@ -18,18 +18,23 @@ func getDownloadsDir() (string, error) {
 		return "", err
 	}
 
+	// Android and Termux keep shared files in their own storage
+	if path, ok := androidDownloadsDir(home); ok {
+		return path, nil
+	}
+
 	// Try XDG user dirs file
 	cfgHome := os.Getenv("XDG_CONFIG_HOME")
 	if cfgHome == "" {
 		cfgHome = filepath.Join(home, ".config")
 	}
 	f := filepath.Join(cfgHome, "user-dirs.dirs")
-	if path, err := parseXDGUserDirs(f, home); err == nil && path != "" {
+	if path, err := parseXDGUserDirs(f, home, "XDG_DOWNLOAD_DIR"); err == nil && path != "" {
 		return path, nil
 	}
 
 	// xdg-user-dir command if present (best-effort)
-	if path, err := tryXdgUserDirCommand(); err == nil && path != "" {
+	if path, err := tryXdgUserDirCommand("DOWNLOAD"); err == nil && path != "" {
 		return path, nil
 	}
 
@@ -37,7 +42,34 @@ func getDownloadsDir() (string, error) {
 	return filepath.Join(home, "Downloads"), nil
 }
 
-func parseXDGUserDirs(fpath, home string) (string, error) {
+func getPicturesDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	if path, ok := androidPicturesDir(home); ok {
+		return path, nil
+	}
+
+	cfgHome := os.Getenv("XDG_CONFIG_HOME")
+	if cfgHome == "" {
+		cfgHome = filepath.Join(home, ".config")
+	}
+	f := filepath.Join(cfgHome, "user-dirs.dirs")
+	if path, err := parseXDGUserDirs(f, home, "XDG_PICTURES_DIR"); err == nil && path != "" {
+		return path, nil
+	}
+
+	if path, err := tryXdgUserDirCommand("PICTURES"); err == nil && path != "" {
+		return path, nil
+	}
+
+	// Fallback to ~/Pictures
+	return filepath.Join(home, "Pictures"), nil
+}
+
+func parseXDGUserDirs(fpath, home, key string) (string, error) {
 	fd, err := os.Open(fpath)
 	if err != nil {
 		return "", err
@@ -51,7 +83,7 @@ func parseXDGUserDirs(fpath, home string) (string, error) {
 			continue
 		}
 		// expecting: XDG_DOWNLOAD_DIR="$HOME/Downloads"
-		if !strings.HasPrefix(line, "XDG_DOWNLOAD_DIR") {
+		if !strings.HasPrefix(line, key) {
 			continue
 		}
 		parts := strings.SplitN(line, "=", 2)
@@ -73,10 +105,10 @@ func parseXDGUserDirs(fpath, home string) (string, error) {
 	if err := scanner.Err(); err != nil {
 		return "", err
 	}
-	return "", errors.New("no XDG_DOWNLOAD_DIR found")
+	return "", errors.New("no " + key + " found")
 }
 
-func tryXdgUserDirCommand() (string, error) {
+func tryXdgUserDirCommand(dir string) (string, error) {
 	// Best-effort: try to run `xdg-user-dir DOWNLOAD` if available.
 	// Keep it optional — if command isn't present, ignore errors.
 	xdg := "/usr/bin/xdg-user-dir"
@@ -84,7 +116,7 @@ func tryXdgUserDirCommand() (string, error) {
 		// try PATH lookup
 		xdg = "xdg-user-dir"
 	}
-	out, err := runCommandCapture(xdg, "DOWNLOAD")
+	out, err := runCommandCapture(xdg, dir)
 	if err != nil {
 		return "", err
 	}
@@ -16,37 +16,62 @@ func getDownloadsDir() (string, error) {
 		0x65, 0x45, // Data3
 		0x91, 0x64, 0x39, 0xC4, 0x92, 0x5E, 0x46, 0x7B, // Data4
 	}
+	return knownFolderPath(guid, "Downloads")
+}
+
+func getPicturesDir() (string, error) {
+	// GUID for FOLDERID_Pictures: 33E28130-4E1E-4676-835A-98395C3BC3BB
+	guid := [16]byte{
+		0x30, 0x81, 0xE2, 0x33, // Data1 (little-endian)
+		0x1E, 0x4E, // Data2
+		0x76, 0x46, // Data3
+		0x83, 0x5A, 0x98, 0x39, 0x5C, 0x3B, 0xC3, 0xBB, // Data4
+	}
+	return knownFolderPath(guid, "Pictures")
+}
 
+// knownFolderPath resolves a known folder, falling back to the folder name in
+// the user's profile.
+func knownFolderPath(guid [16]byte, fallback string) (string, error) {
 	modShell32 := syscall.NewLazyDLL("shell32.dll")
 	procSHGetKnownFolderPath := modShell32.NewProc("SHGetKnownFolderPath")
 
 	modOle32 := syscall.NewLazyDLL("ole32.dll")
 	procCoTaskMemFree := modOle32.NewProc("CoTaskMemFree")
 
-	var out uintptr
+	var out *uint16
 	hr, _, _ := procSHGetKnownFolderPath.Call(
 		uintptr(unsafe.Pointer(&guid[0])),
 		uintptr(0), // dwFlags
 		uintptr(0), // hToken
 		uintptr(unsafe.Pointer(&out)),
 	)
-	if hr == 0 && out != 0 {
+	if hr == 0 && out != nil {
 		// Convert UTF-16 PWSTR to Go string
-		path := syscall.UTF16PtrToString((*uint16)(unsafe.Pointer(out)))
+		path := utf16PtrToString(out)
 		// free returned memory
-		procCoTaskMemFree.Call(out)
+		procCoTaskMemFree.Call(uintptr(unsafe.Pointer(out)))
 		if path != "" {
 			return path, nil
 		}
 	}
 
-	// On error or unsupported environment, fall back to %USERPROFILE%\Downloads or $HOME/Downloads
+	// On error or unsupported environment, fall back to %USERPROFILE%\<folder> or $HOME/<folder>
 	if up := os.Getenv("USERPROFILE"); up != "" {
-		return filepath.Join(up, "Downloads"), nil
+		return filepath.Join(up, fallback), nil
 	}
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return "", errors.New("could not determine Downloads folder")
+		return "", errors.New("could not determine " + fallback + " folder")
+	}
+	return filepath.Join(home, fallback), nil
+}
+
+// utf16PtrToString converts a NUL terminated UTF-16 string.
+func utf16PtrToString(p *uint16) string {
+	n := 0
+	for ptr := unsafe.Pointer(p); *(*uint16)(ptr) != 0; n++ {
+		ptr = unsafe.Add(ptr, 2)
 	}
-	return filepath.Join(home, "Downloads"), nil
+	return syscall.UTF16ToString(unsafe.Slice(p, n))
 }
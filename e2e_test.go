@@ -0,0 +1,188 @@
+//go:build mock
+
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// The end to end tests run the climage binary with the mock provider, so
+// they need neither credentials nor network access:
+//
+//	go test -tags mock .
+
+var binary string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "climage-e2e")
+	if err != nil {
+		panic(err)
+	}
+	binary = filepath.Join(dir, "climage")
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+	build := exec.Command("go", "build", "-tags", "mock", "-o", binary, ".")
+	build.Stderr = os.Stderr
+	if err := build.Run(); err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// sandbox is a home directory with a config that only enables the mock
+// provider.
+type sandbox struct {
+	home string
+	out  string
+}
+
+func newSandbox(t *testing.T) sandbox {
+	t.Helper()
+	s := sandbox{home: t.TempDir(), out: t.TempDir()}
+	// the config dir os.UserConfigDir returns for the environment set in run
+	configDir := filepath.Join(s.home, "config", "climage")
+	if runtime.GOOS == "darwin" {
+		configDir = filepath.Join(s.home, "Library", "Application Support", "climage")
+	}
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := `{"providers": [{"name": "mock"}], "default_model": "mock/placeholder", "credential_store": "file", "sidecars": true}`
+	if err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// run runs climage in the sandbox and returns its stdout and exit code.
+func (s sandbox) run(t *testing.T, args ...string) (string, int) {
+	t.Helper()
+	cmd := exec.Command(binary, args...)
+	cmd.Env = append(os.Environ(),
+		"HOME="+s.home,
+		"USERPROFILE="+s.home,
+		"APPDATA="+filepath.Join(s.home, "config"),
+		"LOCALAPPDATA="+filepath.Join(s.home, "cache"),
+		"XDG_CONFIG_HOME="+filepath.Join(s.home, "config"),
+		"XDG_CACHE_HOME="+filepath.Join(s.home, "cache"),
+		"XDG_STATE_HOME="+filepath.Join(s.home, "state"),
+		"CLIMAGE_HEADLESS=",
+	)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		t.Fatal(err)
+	}
+	if t.Failed() || testing.Verbose() {
+		t.Log(stderr.String())
+	}
+	return stdout.String(), cmd.ProcessState.ExitCode()
+}
+
+// batch writes the prompts to a file and generates them.
+func (s sandbox) batch(t *testing.T, prompts ...string) []string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "prompts.txt")
+	if err := os.WriteFile(file, []byte(strings.Join(prompts, "\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stdout, code := s.run(t, "--out", s.out, "batch", file)
+	if code != 0 {
+		t.Fatalf("batch exited with %d: %s", code, stdout)
+	}
+	var images []string
+	for line := range strings.Lines(stdout) {
+		line = strings.TrimSpace(line)
+		if strings.HasSuffix(line, ".png") {
+			images = append(images, line)
+		}
+	}
+	return images
+}
+
+func TestBatch(t *testing.T) {
+	s := newSandbox(t)
+	images := s.batch(t, "a red fox", "a lighthouse at night")
+	if len(images) != 2 {
+		t.Fatalf("got %d images, want 2: %v", len(images), images)
+	}
+	for _, image := range images {
+		if !strings.HasPrefix(image, s.out) {
+			t.Errorf("%s is not in the output dir %s", image, s.out)
+		}
+		f, err := os.Open(image)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = png.Decode(f)
+		f.Close()
+		if err != nil {
+			t.Errorf("%s is not a PNG: %v", image, err)
+		}
+		if _, err := os.Stat(strings.TrimSuffix(image, ".png") + ".json"); err != nil {
+			t.Errorf("sidecar of %s is missing: %v", image, err)
+		}
+	}
+}
+
+func TestMockIsDeterministic(t *testing.T) {
+	s := newSandbox(t)
+	images := s.batch(t, "a red fox", "a red fox")
+	if len(images) != 2 || images[0] == images[1] {
+		t.Fatalf("got %v, want two files", images)
+	}
+	a, err := os.ReadFile(images[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(images[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(a, b) {
+		t.Error("the same prompt generated different images")
+	}
+}
+
+func TestHeadlessRequiresOut(t *testing.T) {
+	s := newSandbox(t)
+	file := filepath.Join(t.TempDir(), "prompts.txt")
+	if err := os.WriteFile(file, []byte("a red fox\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, code := s.run(t, "--headless", "batch", file); code == 0 {
+		t.Error("headless batch without --out succeeded")
+	}
+}
@@ -0,0 +1,60 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package filelock serializes access to data files shared by several climage
+// processes, e.g. the schedule daemon and a cron job.
+package filelock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	// timeout is how long Lock waits for another process.
+	timeout = 10 * time.Second
+	// stale is the age of a lock file left behind by a crashed process.
+	stale = time.Minute
+)
+
+// Lock creates the lock file, waiting for another process that holds it. The
+// returned function releases the lock. Locks must only be held for quick file
+// updates, a lock older than a minute is considered stale and broken.
+func Lock(lockFile string) (func(), error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := os.OpenFile(lockFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(lockFile) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if fi, err := os.Stat(lockFile); err == nil && time.Since(fi.ModTime()) > stale {
+			_ = os.Remove(lockFile)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is held by another process", lockFile)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
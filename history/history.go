@@ -0,0 +1,209 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package history keeps a local log of all generations. It is never sent
+// anywhere.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/filelock"
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+// Entry is a single generation.
+type Entry struct {
+	// ID identifies the entry, e.g. in the web gallery. It is the line of
+	// the entry in the history file, so it stays the same if other lines are
+	// malformed.
+	ID     int       `json:"id,omitempty"`
+	Time   time.Time `json:"time"`
+	Model  string    `json:"model"`
+	Prompt string    `json:"prompt"`
+	// Images are the paths of the saved images.
+	Images []string `json:"images,omitempty"`
+	// Uploads are the upload locations of images whose local files were
+	// removed, see keep_local_files.
+	Uploads []string `json:"uploads,omitempty"`
+	// Filtered is the number of images removed by the safety filter.
+	Filtered int `json:"filtered,omitempty"`
+	// Cost is the estimated price in USD.
+	Cost float64 `json:"cost,omitempty"`
+}
+
+var mu sync.Mutex
+
+func getHistoryFilePath() (string, error) {
+	dataDir, err := providers.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "history.jsonl"), nil
+}
+
+// Append adds the entry to the history. The ID is set by Append.
+func Append(e Entry) error {
+	historyFile, err := getHistoryFilePath()
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(historyFile), 0700); err != nil {
+		return fmt.Errorf("failed to create data dir: %w", err)
+	}
+	// other processes must not take the same line
+	unlock, err := filelock.Lock(historyFile + ".lock")
+	if err != nil {
+		return fmt.Errorf("failed to lock history file: %w", err)
+	}
+	defer unlock()
+	f, err := os.OpenFile(historyFile, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+	lines, terminated, err := countLines(f)
+	if err != nil {
+		return fmt.Errorf("failed to read history file: %w", err)
+	}
+	e.ID = lines + 1
+	b, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+	if !terminated {
+		// finish the line of an interrupted write
+		b = append([]byte{'\n'}, b...)
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	return nil
+}
+
+// Spend returns the estimated spend in USD of the provider's generations
+// since the given time.
+func Spend(provider string, since time.Time) (float64, error) {
+	entries, err := Read()
+	if err != nil {
+		return 0, err
+	}
+	var spend float64
+	for _, e := range entries {
+		if e.Time.Before(since) || !strings.HasPrefix(e.Model, provider+"/") {
+			continue
+		}
+		spend += e.Cost
+	}
+	return spend, nil
+}
+
+// ErrNotFound is returned by Get if there is no entry with the ID.
+var ErrNotFound = errors.New("history entry not found")
+
+// Get returns the entry with the ID.
+func Get(id int) (Entry, error) {
+	entries, err := Read()
+	if err != nil {
+		return Entry{}, err
+	}
+	for _, e := range entries {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return Entry{}, fmt.Errorf("%w: %d", ErrNotFound, id)
+}
+
+// Read returns all entries, oldest first. Malformed lines are skipped.
+func Read() ([]Entry, error) {
+	historyFile, err := getHistoryFilePath()
+	if err != nil {
+		return nil, err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	f, err := os.Open(historyFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if e.ID == 0 {
+			// written before entries had IDs
+			e.ID = line
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, fmt.Errorf("failed to read history file: %w", err)
+	}
+	return entries, nil
+}
+
+// countLines returns the number of lines of the file, an unterminated last
+// line included, and whether the last line is terminated.
+func countLines(f *os.File) (int, bool, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, false, err
+	}
+	lines := 0
+	last := byte('\n')
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			lines += bytes.Count(buf[:n], []byte{'\n'})
+			last = buf[n-1]
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, false, err
+		}
+	}
+	if last != '\n' {
+		return lines + 1, false, nil
+	}
+	return lines, true, nil
+}
@@ -0,0 +1,147 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package i18n
+
+var de = map[string]string{
+	// interactive session
+	"prompt.title":         "Prompt",
+	"prompt.description":   "Gib deinen Prompt für %s ein. Tippe /help für eine Liste der Befehle.",
+	"prompt.jobs_running":  "%d Auftrag/Aufträge laufen, /jobs zeigt sie an.",
+	"model.title":          "Modell",
+	"model.description":    "Wähle ein Modell für die Bildgenerierung.",
+	"help.commands":        "Befehle:",
+	"help.models":          "Modell auswählen",
+	"help.settings":        "Einstellungen des Modells ändern",
+	"help.jobs":            "laufende und beendete Aufträge anzeigen",
+	"help.retry":           "den letzten Prompt erneut generieren",
+	"help.share":           "Links zum Teilen des letzten Ergebnisses erstellen, optional mit Ablaufdauer",
+	"help.preset":          "Einstellungen als Vorlage speichern oder eine Vorlage anwenden, /preset listet sie auf",
+	"help.quota":           "verbleibende Budgets und Kontingente der Anbieter anzeigen",
+	"help.style":           "eine Stilvorlage zu den Prompts hinzufügen, /style listet sie auf",
+	"help.ref":             "eine Bilddatei, URL oder die Zwischenablage als Referenz nutzen, Prompts bearbeiten sie bis /ref clear",
+	"help.help":            "diese Hilfe anzeigen",
+	"help.exit":            "Sitzung beenden",
+	"invalid_command":      "ungültiger Befehl: %q",
+	"invalid_expiry":       "ungültige Ablaufdauer: %v",
+	"generate_failed":      "Bild konnte nicht generiert werden: %v",
+	"jobs_cancelled":       "%d laufende(n) Auftrag/Aufträge abgebrochen",
+	"jobs_waiting":         "warte auf %d laufende(n) Auftrag/Aufträge",
+	"no_jobs":              "keine Aufträge",
+	"job.running":          "läuft",
+	"job.done":             "fertig",
+	"job.failed":           "fehlgeschlagen",
+	"job.cancelled":        "abgebrochen",
+	"image.filtered":       "Bild vom Sicherheitsfilter entfernt: %s",
+	"image.categories":     "Kategorien: %s",
+	"image.uploaded":       "hochgeladen: %s",
+	"image.watermark":      "unsichtbares Wasserzeichen: %s",
+	"tui.cost":             "%.2f $ in dieser Sitzung",
+	"tui.running":          "%d laufend",
+	"tui.refs":             "%d Referenz(en)",
+	"tui.keys":             "%s senden · %s neue Zeile · %s überarbeiten · Tab Jobs · Bild↑/Bild↓ blättern · %s abbrechen/beenden",
+	"tui.refine":           "Auftrag %d abgebrochen, Prompt bearbeiten und mit %s senden",
+	"share.no_result":      "noch kein Ergebnis zum Teilen",
+	"share.no_local_image": "kein lokales Bild zum Teilen",
+	"share.shared":         "geteilt: %s",
+	"preset.none":          "noch keine Vorlagen gespeichert, speichere eine mit /preset save <Name>",
+	"preset.saved":         "Vorlage %q gespeichert",
+	"preset.applied":       "Vorlage %q angewendet",
+	"preset.failed":        "Vorlage konnte nicht angewendet werden: %v",
+	"quota.no_providers":   "keine Anbieter konfiguriert",
+	"quota.no_budget":      "kein Budget festgelegt",
+	"quota.budget.daily":   "Tagesbudget: %.2f $ von %.2f $ übrig (%.2f $ heute ausgegeben)",
+	"quota.budget.monthly": "Monatsbudget: %.2f $ von %.2f $ übrig (%.2f $ diesen Monat ausgegeben)",
+	"quota.per_minute":     "%d Anfragen pro Minute",
+	"quota.in_flight":      "%d gleichzeitig",
+	"quota.limits":         "Ratenlimits: %s",
+	"quota.remaining":      "%s: %s %s übrig",
+	"quota.remaining_of":   "%s: %s von %s %s übrig",
+	"quota.reset":          "(wird %s zurückgesetzt)",
+	"quota.low":            "(fast aufgebraucht)",
+	"quota.used_up":        "(aufgebraucht)",
+	"quota.failed":         "Kontingent konnte nicht abgefragt werden: %v",
+	"style.none":           "kein Stil ausgewählt, wähle einen mit /style <Name>",
+	"style.selected":       "Prompts nutzen nun den Stil %s, /style none entfernt ihn",
+	"style.cleared":        "Prompts nutzen keinen Stil",
+	"style.failed":         "Stil konnte nicht ausgewählt werden: %v",
+	"ref.none":             "keine Referenzbilder, füge eines mit /ref clipboard oder /ref <Datei> hinzu",
+	"ref.count":            "%d Referenzbild(er), /ref clear entfernt sie",
+	"ref.added":            "Referenzbild hinzugefügt, Prompts bearbeiten nun die %d Referenz(en) bis /ref clear",
+	"ref.cleared":          "Referenzbilder entfernt",
+	"ref.failed":           "Referenzbild konnte nicht hinzugefügt werden: %v",
+	"preset.usage":         "Verwendung: /preset save <Name>, /preset apply <Name> oder /preset",
+	"error.config":         "Konfiguration konnte nicht geladen werden: %w",
+	"error.save_config":    "Konfiguration konnte nicht gespeichert werden: %w",
+	"error.prompt_form":    "Prompt-Formular fehlgeschlagen: %w",
+	"error.model_form":     "Modell-Formular fehlgeschlagen: %w",
+	"error.settings_form":  "Einstellungs-Formular fehlgeschlagen: %w",
+	"error.tui":            "interaktive Sitzung fehlgeschlagen: %w",
+	"error.no_model":       "kein Modell verfügbar",
+	"error.get_provider":   "Anbieter konnte nicht geladen werden: %w",
+
+	// auth
+	"auth.provider.title":      "Anbieter",
+	"auth.login.description":   "Wähle einen Anbieter für die Anmeldung.",
+	"auth.logout.description":  "Wähle einen Anbieter für die Abmeldung.",
+	"error.no_providers":       "keine Anbieter verfügbar",
+	"error.not_logged_in":      "bei keinem Anbieter angemeldet",
+	"error.provider_form":      "Anbieterauswahl fehlgeschlagen: %w",
+	"error.login_form":         "Anmeldeformular fehlgeschlagen: %w",
+	"error.logout_form":        "Abmeldeformular fehlgeschlagen: %w",
+	"error.open_file":          "Datei konnte nicht geöffnet werden: %w",
+	"error.read_file":          "Datei konnte nicht gelesen werden: %w",
+	"error.not_image":          "%s ist kein Bild",
+	"error.login":              "Anmeldung mit den angegebenen Zugangsdaten fehlgeschlagen: %w",
+	"error.save_credentials":   "Zugangsdaten konnten nicht gespeichert werden: %w",
+	"error.delete_credentials": "Zugangsdaten konnten nicht gelöscht werden: %w",
+
+	// characters
+	"character.description.title":       "Beschreibung",
+	"character.description.description": "Beschreibe @%s so, wie es in Prompts erscheinen soll.",
+	"character.images.title":            "Referenzbilder",
+	"character.images.description":      "Kommagetrennte Liste von Bilddateien oder URLs (optional).",
+	"character.list":                    "@%s (%d Bilder): %s",
+	"error.character_name":              "ungültiger Charaktername %q: nur Buchstaben, Ziffern, '-' und '_' sind erlaubt",
+	"error.character_form":              "Charakter-Formular fehlgeschlagen: %w",
+	"error.character_image_path":        "Bildpfad %q konnte nicht aufgelöst werden: %w",
+	"error.character_image":             "auf Bild %q konnte nicht zugegriffen werden: %w",
+	"error.character_not_found":         "Charakter %q nicht gefunden",
+
+	// upload
+	"upload.target.title":       "Upload-Ziel",
+	"upload.target.description": "Wähle das Upload-Ziel, das autorisiert werden soll.",
+	"upload.open_url":           "Öffne diese URL im Browser, um CLImage zu autorisieren:",
+	"upload.authorized":         "%s autorisiert",
+	"error.no_oauth_targets":    "keine Google-Drive- oder Dropbox-Upload-Ziele konfiguriert",
+	"error.upload_target_form":  "Auswahl des Upload-Ziels fehlgeschlagen: %w",
+	"error.upload_login":        "Upload-Ziel %d konnte nicht autorisiert werden: %w",
+
+	// setup
+	"setup.welcome":                "Willkommen bei CLImage! Melde dich bei einem Anbieter an und lege die Standardeinstellungen fest.",
+	"setup.model.title":            "Standardmodell",
+	"setup.model.description":      "Wähle das Modell, das beim Start von CLImage verwendet wird.",
+	"setup.output_dir.title":       "Ausgabeverzeichnis",
+	"setup.output_dir.description": "Generierte Bilder werden hier gespeichert.",
+	"setup.test.title":             "Testbild generieren?",
+	"setup.test.running":           "generiere ein Testbild mit %s",
+	"setup.test.prompt":            "Ein Aquarell eines Leuchtturms bei Sonnenuntergang",
+	"setup.done":                   "Einrichtung abgeschlossen. Mit 'climage setup' lassen sich diese Einstellungen später ändern.",
+	"error.setup_form":             "Einrichtungsformular fehlgeschlagen: %w",
+	"error.setup_test":             "Testgenerierung fehlgeschlagen: %w",
+}
@@ -0,0 +1,147 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package i18n
+
+var en = map[string]string{
+	// interactive session
+	"prompt.title":         "Prompt",
+	"prompt.description":   "Enter your prompt for %s. Type /help to list the commands.",
+	"prompt.jobs_running":  "%d job(s) running, /jobs to list them.",
+	"model.title":          "Model",
+	"model.description":    "Select a model to generate an image with.",
+	"help.commands":        "Commands:",
+	"help.models":          "select the model",
+	"help.settings":        "change the settings of the model",
+	"help.jobs":            "list running and finished jobs",
+	"help.retry":           "generate the last prompt again",
+	"help.share":           "create share links for the last result, optionally expiring after the duration",
+	"help.preset":          "save the settings as preset or apply a preset, /preset lists them",
+	"help.quota":           "show the remaining budgets and quotas of the providers",
+	"help.style":           "add a style preset to the prompts, /style lists them",
+	"help.ref":             "use an image file, URL or the clipboard as reference, prompts then edit it until /ref clear",
+	"help.help":            "show this help",
+	"help.exit":            "quit the session",
+	"invalid_command":      "invalid command: %q",
+	"invalid_expiry":       "invalid expiry: %v",
+	"generate_failed":      "failed to generate image: %v",
+	"jobs_cancelled":       "cancelled %d running job(s)",
+	"jobs_waiting":         "waiting for %d running job(s)",
+	"no_jobs":              "no jobs",
+	"job.running":          "running",
+	"job.done":             "done",
+	"job.failed":           "failed",
+	"job.cancelled":        "cancelled",
+	"image.filtered":       "image removed by safety filter: %s",
+	"image.categories":     "categories: %s",
+	"image.uploaded":       "uploaded: %s",
+	"image.watermark":      "invisible watermark: %s",
+	"tui.cost":             "$%.2f this session",
+	"tui.running":          "%d running",
+	"tui.refs":             "%d reference(s)",
+	"tui.keys":             "%s send · %s new line · %s refine · tab jobs · pgup/pgdn scroll · %s cancel/quit",
+	"tui.refine":           "cancelled job %d, edit the prompt and press %s",
+	"share.no_result":      "no result to share yet",
+	"share.no_local_image": "no local image to share",
+	"share.shared":         "shared: %s",
+	"preset.none":          "no presets saved yet, save one with /preset save <name>",
+	"preset.saved":         "saved preset %q",
+	"preset.applied":       "applied preset %q",
+	"preset.failed":        "failed to apply preset: %v",
+	"quota.no_providers":   "no providers configured",
+	"quota.no_budget":      "no budget set",
+	"quota.budget.daily":   "daily budget: $%.2f of $%.2f left ($%.2f spent today)",
+	"quota.budget.monthly": "monthly budget: $%.2f of $%.2f left ($%.2f spent this month)",
+	"quota.per_minute":     "%d requests per minute",
+	"quota.in_flight":      "%d at a time",
+	"quota.limits":         "rate limits: %s",
+	"quota.remaining":      "%s: %s %s left",
+	"quota.remaining_of":   "%s: %s of %s %s left",
+	"quota.reset":          "(resets %s)",
+	"quota.low":            "(nearly used up)",
+	"quota.used_up":        "(used up)",
+	"quota.failed":         "failed to get quota: %v",
+	"style.none":           "no style selected, select one with /style <name>",
+	"style.selected":       "prompts now use the %s style, /style none removes it",
+	"style.cleared":        "prompts use no style",
+	"style.failed":         "failed to select style: %v",
+	"ref.none":             "no reference images, add one with /ref clipboard or /ref <file>",
+	"ref.count":            "%d reference image(s), /ref clear removes them",
+	"ref.added":            "added reference image, prompts now edit the %d reference(s) until /ref clear",
+	"ref.cleared":          "removed the reference images",
+	"ref.failed":           "failed to add reference image: %v",
+	"preset.usage":         "usage: /preset save <name>, /preset apply <name> or /preset",
+	"error.config":         "failed to get config: %w",
+	"error.save_config":    "failed to save config: %w",
+	"error.prompt_form":    "failed to run prompt form: %w",
+	"error.model_form":     "failed to run model form: %w",
+	"error.settings_form":  "failed to run settings form: %w",
+	"error.tui":            "failed to run interactive session: %w",
+	"error.no_model":       "no model is available",
+	"error.get_provider":   "failed to get provider: %w",
+
+	// auth
+	"auth.provider.title":      "Provider",
+	"auth.login.description":   "Select a provider to login with.",
+	"auth.logout.description":  "Select a provider to logout from.",
+	"error.no_providers":       "no providers are available",
+	"error.not_logged_in":      "not logged in to any provider",
+	"error.provider_form":      "failed to run provider selection: %w",
+	"error.login_form":         "failed to run login form: %w",
+	"error.logout_form":        "failed to run logout form: %w",
+	"error.open_file":          "failed to open file: %w",
+	"error.read_file":          "failed to read file: %w",
+	"error.not_image":          "%s is not an image",
+	"error.login":              "failed to login with provided credentials: %w",
+	"error.save_credentials":   "failed to save credentials: %w",
+	"error.delete_credentials": "failed to delete credentials: %w",
+
+	// characters
+	"character.description.title":       "Description",
+	"character.description.description": "Describe @%s as it should appear in prompts.",
+	"character.images.title":            "Reference Images",
+	"character.images.description":      "Comma separated list of image files or URLs (optional).",
+	"character.list":                    "@%s (%d images): %s",
+	"error.character_name":              "invalid character name %q: only letters, digits, '-' and '_' are allowed",
+	"error.character_form":              "failed to run character form: %w",
+	"error.character_image_path":        "failed to resolve image path %q: %w",
+	"error.character_image":             "failed to access image %q: %w",
+	"error.character_not_found":         "character %q not found",
+
+	// upload
+	"upload.target.title":       "Upload target",
+	"upload.target.description": "Select the upload target to authorize.",
+	"upload.open_url":           "Open this URL in your browser to authorize CLImage:",
+	"upload.authorized":         "authorized %s",
+	"error.no_oauth_targets":    "no Google Drive or Dropbox upload targets are configured",
+	"error.upload_target_form":  "failed to run upload target selection: %w",
+	"error.upload_login":        "failed to authorize upload target %d: %w",
+
+	// setup
+	"setup.welcome":                "Welcome to CLImage! Let's log in to a provider and set up the defaults.",
+	"setup.model.title":            "Default model",
+	"setup.model.description":      "Select the model used when CLImage starts.",
+	"setup.output_dir.title":       "Output directory",
+	"setup.output_dir.description": "Generated images are saved here.",
+	"setup.test.title":             "Run a test generation?",
+	"setup.test.running":           "generating a test image with %s",
+	"setup.test.prompt":            "A watercolor painting of a lighthouse at sunset",
+	"setup.done":                   "Setup complete. Run 'climage setup' to change these settings later.",
+	"error.setup_form":             "failed to run setup form: %w",
+	"error.setup_test":             "test generation failed: %w",
+}
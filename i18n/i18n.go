@@ -0,0 +1,117 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package i18n provides the translated user-facing strings of the
+// interactive UI.
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+const defaultLocale = "en"
+
+var bundles = map[string]map[string]string{
+	"en": en,
+	"de": de,
+}
+
+var (
+	mu     sync.RWMutex
+	locale = localeFromEnv()
+)
+
+// SetLocale selects the locale by name, e.g. "de" or "de_DE.UTF-8". An empty
+// name uses the locale of the environment (LC_ALL, LC_MESSAGES or LANG).
+// Unknown locales fall back to English.
+func SetLocale(name string) {
+	l := normalize(name)
+	if l == "" {
+		l = localeFromEnv()
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	locale = l
+}
+
+// Locale returns the selected locale.
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return locale
+}
+
+// Locales returns the names of all available locales.
+func Locales() []string {
+	return []string{"en", "de"}
+}
+
+// T returns the message with the given key in the selected locale. If args
+// are given, the message is used as format string.
+func T(key string, args ...any) string {
+	msg := lookup(key)
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Errorf is like fmt.Errorf with the translated message as format string.
+func Errorf(key string, args ...any) error {
+	msg := lookup(key)
+	if len(args) == 0 {
+		return errors.New(msg)
+	}
+	return fmt.Errorf(msg, args...)
+}
+
+func lookup(key string) string {
+	if msg, ok := bundles[Locale()][key]; ok {
+		return msg
+	}
+	if msg, ok := en[key]; ok {
+		return msg
+	}
+	return key
+}
+
+func localeFromEnv() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if l := normalize(os.Getenv(env)); l != "" {
+			return l
+		}
+	}
+	return defaultLocale
+}
+
+// normalize reduces a POSIX locale name like "de_DE.UTF-8" to its language
+// and returns "" if there is no bundle for it.
+func normalize(name string) string {
+	name = strings.ToLower(name)
+	if i := strings.IndexAny(name, "_.@-"); i >= 0 {
+		name = name[:i]
+	}
+	if _, ok := bundles[name]; !ok {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,166 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package icons exports an image as a favicon and app icon set.
+package icons
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/bloodmagesoftware/climage/sizes"
+)
+
+// PNGSizes are the sizes of the exported PNG icons. 180 is the Apple touch
+// icon, 192 and 512 are used by web app manifests.
+var PNGSizes = []int{16, 32, 48, 64, 128, 180, 192, 256, 512, 1024}
+
+// icoSizes are the sizes in favicon.ico. ICO stores at most 256 pixels.
+var icoSizes = []int{16, 24, 32, 48, 64, 128, 256}
+
+// icnsTypes are the icon types in icon.icns with their size in pixels.
+var icnsTypes = []struct {
+	osType string
+	size   int
+}{
+	{"icp4", 16},
+	{"ic11", 32}, // 16@2x
+	{"icp5", 32},
+	{"ic12", 64}, // 32@2x
+	{"icp6", 64},
+	{"ic07", 128},
+	{"ic13", 256}, // 128@2x
+	{"ic08", 256},
+	{"ic14", 512}, // 256@2x
+	{"ic09", 512},
+	{"ic10", 1024}, // 512@2x
+}
+
+// Export writes the icon set of the image to dir: icon-<size>.png for every
+// PNG size, favicon.ico and icon.icns. Non-square images are cropped to the
+// center. It returns the written files.
+func Export(imagePath string, dir string) ([]string, error) {
+	f, err := os.Open(imagePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image: %w", err)
+	}
+	src, _, err := image.Decode(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create icon dir: %w", err)
+	}
+
+	// every size is scaled from the source, not from the next larger icon
+	encoded := make(map[int][]byte)
+	encode := func(size int) ([]byte, error) {
+		if b, ok := encoded[size]; ok {
+			return b, nil
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, sizes.Resize(src, sizes.Size{Width: size, Height: size})); err != nil {
+			return nil, fmt.Errorf("failed to encode %dpx icon: %w", size, err)
+		}
+		encoded[size] = buf.Bytes()
+		return buf.Bytes(), nil
+	}
+
+	var files []string
+	write := func(name string, b []byte) error {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, b, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		files = append(files, path)
+		return nil
+	}
+	for _, size := range PNGSizes {
+		b, err := encode(size)
+		if err != nil {
+			return files, err
+		}
+		if err := write(fmt.Sprintf("icon-%d.png", size), b); err != nil {
+			return files, err
+		}
+	}
+
+	ico, err := buildICO(encode)
+	if err != nil {
+		return files, err
+	}
+	if err := write("favicon.ico", ico); err != nil {
+		return files, err
+	}
+	icns, err := buildICNS(encode)
+	if err != nil {
+		return files, err
+	}
+	if err := write("icon.icns", icns); err != nil {
+		return files, err
+	}
+	return files, nil
+}
+
+// buildICO builds an ICO file with embedded PNG images.
+func buildICO(encode func(int) ([]byte, error)) ([]byte, error) {
+	var header, data bytes.Buffer
+	// reserved, type 1 (icon), image count
+	_ = binary.Write(&header, binary.LittleEndian, [3]uint16{0, 1, uint16(len(icoSizes))})
+	offset := 6 + 16*len(icoSizes)
+	for _, size := range icoSizes {
+		b, err := encode(size)
+		if err != nil {
+			return nil, err
+		}
+		// 0 means 256 in the one byte width and height
+		dim := byte(size % 256)
+		header.Write([]byte{dim, dim, 0, 0})
+		// color planes, bits per pixel, data size, data offset
+		_ = binary.Write(&header, binary.LittleEndian, [2]uint16{1, 32})
+		_ = binary.Write(&header, binary.LittleEndian, [2]uint32{uint32(len(b)), uint32(offset + data.Len())})
+		data.Write(b)
+	}
+	header.Write(data.Bytes())
+	return header.Bytes(), nil
+}
+
+// buildICNS builds an Apple icon image file with embedded PNG images.
+func buildICNS(encode func(int) ([]byte, error)) ([]byte, error) {
+	var data bytes.Buffer
+	for _, t := range icnsTypes {
+		b, err := encode(t.size)
+		if err != nil {
+			return nil, err
+		}
+		data.WriteString(t.osType)
+		_ = binary.Write(&data, binary.BigEndian, uint32(8+len(b)))
+		data.Write(b)
+	}
+	var icns bytes.Buffer
+	icns.WriteString("icns")
+	_ = binary.Write(&icns, binary.BigEndian, uint32(8+data.Len()))
+	icns.Write(data.Bytes())
+	return icns.Bytes(), nil
+}
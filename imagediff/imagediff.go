@@ -0,0 +1,138 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package imagediff compares images by their perceptual color difference.
+package imagediff
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// JustNoticeable is the color difference (CIE76 ΔE) people start to notice.
+const JustNoticeable = 2.3
+
+// heatmapMax is the difference shown with the hottest color.
+const heatmapMax = 50
+
+// Result is the difference of two images of the same size.
+type Result struct {
+	// MeanDelta and MaxDelta are the mean and largest ΔE of all pixels.
+	MeanDelta float64 `json:"mean_delta"`
+	MaxDelta  float64 `json:"max_delta"`
+	// Changed is the fraction of pixels that differ noticeably.
+	Changed float64 `json:"changed"`
+	// Heatmap shows the difference of every pixel in color over the first
+	// image in gray.
+	Heatmap *image.NRGBA `json:"-"`
+}
+
+// Compare compares two images of the same size.
+func Compare(a, b image.Image) Result {
+	ab, bb := a.Bounds(), b.Bounds()
+	w, h := min(ab.Dx(), bb.Dx()), min(ab.Dy(), bb.Dy())
+	res := Result{Heatmap: image.NewNRGBA(image.Rect(0, 0, w, h))}
+	var sum float64
+	var changed int
+	for y := range h {
+		for x := range w {
+			ca := a.At(ab.Min.X+x, ab.Min.Y+y)
+			d := deltaE(lab(ca), lab(b.At(bb.Min.X+x, bb.Min.Y+y)))
+			sum += d
+			res.MaxDelta = max(res.MaxDelta, d)
+			if d > JustNoticeable {
+				changed++
+			}
+			res.Heatmap.SetNRGBA(x, y, heat(ca, d))
+		}
+	}
+	if n := float64(w * h); n > 0 {
+		res.MeanDelta = sum / n
+		res.Changed = float64(changed) / n
+	}
+	return res
+}
+
+// SideBySide puts the images next to each other with a gap.
+func SideBySide(images ...image.Image) *image.NRGBA {
+	const gap = 8
+	width, height := 0, 0
+	for i, img := range images {
+		if i > 0 {
+			width += gap
+		}
+		width += img.Bounds().Dx()
+		height = max(height, img.Bounds().Dy())
+	}
+	out := image.NewNRGBA(image.Rect(0, 0, width, height))
+	x := 0
+	for _, img := range images {
+		b := img.Bounds()
+		draw.Draw(out, image.Rect(x, 0, x+b.Dx(), b.Dy()), img, b.Min, draw.Src)
+		x += b.Dx() + gap
+	}
+	return out
+}
+
+// heat colors a difference from transparent over the dimmed gray of the
+// original through blue and red to yellow.
+func heat(original color.Color, d float64) color.NRGBA {
+	g := color.GrayModel.Convert(original).(color.Gray).Y / 3
+	if d <= JustNoticeable {
+		return color.NRGBA{g, g, g, 255}
+	}
+	t := min((d-JustNoticeable)/(heatmapMax-JustNoticeable), 1)
+	stops := []color.NRGBA{{0, 0, 255, 255}, {255, 0, 0, 255}, {255, 255, 0, 255}}
+	pos := t * float64(len(stops)-1)
+	i := min(int(pos), len(stops)-2)
+	f := pos - float64(i)
+	mix := func(a, b uint8) uint8 { return uint8(float64(a)*(1-f) + float64(b)*f + 0.5) }
+	c0, c1 := stops[i], stops[i+1]
+	return color.NRGBA{mix(c0.R, c1.R), mix(c0.G, c1.G), mix(c0.B, c1.B), 255}
+}
+
+// lab converts a color to CIELAB with the D65 white point, ignoring alpha.
+func lab(c color.Color) [3]float64 {
+	r, g, b, _ := color.NRGBAModel.Convert(c).RGBA()
+	lr, lg, lb := linear(float64(r)/0xffff), linear(float64(g)/0xffff), linear(float64(b)/0xffff)
+	x := (0.4124*lr + 0.3576*lg + 0.1805*lb) / 0.95047
+	y := 0.2126*lr + 0.7152*lg + 0.0722*lb
+	z := (0.0193*lr + 0.1192*lg + 0.9505*lb) / 1.08883
+	fx, fy, fz := labF(x), labF(y), labF(z)
+	return [3]float64{116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)}
+}
+
+func linear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func labF(t float64) float64 {
+	if t > 216.0/24389 {
+		return math.Cbrt(t)
+	}
+	return (24389.0/27*t + 16) / 116
+}
+
+func deltaE(a, b [3]float64) float64 {
+	return math.Sqrt((a[0]-b[0])*(a[0]-b[0]) + (a[1]-b[1])*(a[1]-b[1]) + (a[2]-b[2])*(a[2]-b[2]))
+}
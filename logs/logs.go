@@ -0,0 +1,155 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package logs writes the operational log of climage to a file with size
+// based rotation.
+package logs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+const (
+	// FileName is the name of the current log file. Rotated files get a
+	// number suffix, e.g. climage.log.1.
+	FileName = "climage.log"
+	// maxSize is the size at which the log file is rotated.
+	maxSize = 1 << 20
+	// maxBackups is the number of rotated files that are kept.
+	maxBackups = 3
+)
+
+// Dir returns the log directory, $XDG_STATE_HOME/climage/logs or the
+// platform's equivalent.
+func Dir() (string, error) {
+	if state := os.Getenv("XDG_STATE_HOME"); state != "" {
+		return filepath.Join(state, "climage", "logs"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home dir: %w", err)
+	}
+	switch runtime.GOOS {
+	case "windows":
+		if local := os.Getenv("LOCALAPPDATA"); local != "" {
+			return filepath.Join(local, "climage", "logs"), nil
+		}
+		return filepath.Join(home, "AppData", "Local", "climage", "logs"), nil
+	case "darwin":
+		return filepath.Join(home, "Library", "Logs", "climage"), nil
+	default:
+		return filepath.Join(home, ".local", "state", "climage", "logs"), nil
+	}
+}
+
+// Path returns the path of the current log file.
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// File is a log file that is rotated once it grows beyond 1 MiB.
+type File struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	size int64
+}
+
+// Open opens the log file for appending.
+func Open() (*File, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create log dir: %w", err)
+	}
+	lf := &File{path: path}
+	if err := lf.open(); err != nil {
+		return nil, err
+	}
+	return lf, nil
+}
+
+func (lf *File) open() error {
+	f, err := os.OpenFile(lf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	lf.f = f
+	lf.size = info.Size()
+	return nil
+}
+
+func (lf *File) Write(p []byte) (int, error) {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	if lf.f == nil {
+		return 0, os.ErrClosed
+	}
+	if lf.size > 0 && lf.size+int64(len(p)) > maxSize {
+		if err := lf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := lf.f.Write(p)
+	lf.size += int64(n)
+	return n, err
+}
+
+// rotate renames climage.log to climage.log.1, climage.log.1 to
+// climage.log.2 and so on, dropping the oldest file.
+func (lf *File) rotate() error {
+	if err := lf.f.Close(); err != nil {
+		return err
+	}
+	lf.f = nil
+	for i := maxBackups - 1; i >= 1; i-- {
+		_ = os.Rename(lf.path+"."+strconv.Itoa(i), lf.path+"."+strconv.Itoa(i+1))
+	}
+	if err := os.Rename(lf.path, lf.path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	return lf.open()
+}
+
+// Close closes the log file.
+func (lf *File) Close() error {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	if lf.f == nil {
+		return nil
+	}
+	err := lf.f.Close()
+	lf.f = nil
+	return err
+}
@@ -0,0 +1,183 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+// discordMaxFiles is the maximum number of attachments of a Discord message.
+const discordMaxFiles = 10
+
+// Webhook is a chat webhook generated images are posted to.
+type Webhook struct {
+	// Type is "slack" or "discord".
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// Post posts the prompt and the generated images to the webhook. Discord
+// receives the images as attachments. Slack incoming webhooks can't receive
+// files, so only images with a public http(s) upload location are shown.
+func Post(ctx context.Context, w Webhook, model string, prompt string, images []providers.Image) error {
+	if w.URL == "" {
+		return fmt.Errorf("%s webhook url is not configured", w.Type)
+	}
+	var err error
+	switch w.Type {
+	case "slack":
+		err = postSlack(ctx, w, model, prompt, images)
+	case "discord":
+		err = postDiscord(ctx, w, model, prompt, images)
+	default:
+		return fmt.Errorf("unknown webhook type %q", w.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to post to %s webhook: %w", w.Type, err)
+	}
+	return nil
+}
+
+func postSlack(ctx context.Context, w Webhook, model string, prompt string, images []providers.Image) error {
+	text := fmt.Sprintf("*%s*\n>%s", model, strings.ReplaceAll(prompt, "\n", "\n>"))
+	blocks := []map[string]any{{
+		"type": "section",
+		"text": map[string]any{"type": "mrkdwn", "text": text},
+	}}
+	for _, img := range images {
+		if img.Safety.Filtered {
+			blocks = append(blocks, map[string]any{
+				"type": "context",
+				"elements": []map[string]any{{
+					"type": "mrkdwn",
+					"text": "image removed by safety filter: " + img.Safety.Reason,
+				}},
+			})
+			continue
+		}
+		if u := publicURL(img); u != "" {
+			blocks = append(blocks, map[string]any{
+				"type":      "image",
+				"image_url": u,
+				"alt_text":  prompt,
+			})
+		}
+	}
+	b, err := json.Marshal(map[string]any{"text": model + ": " + prompt, "blocks": blocks})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return do(req)
+}
+
+func postDiscord(ctx context.Context, w Webhook, model string, prompt string, images []providers.Image) error {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	content := fmt.Sprintf("**%s**\n> %s", model, strings.ReplaceAll(prompt, "\n", "\n> "))
+	files := 0
+	for _, img := range images {
+		if img.Safety.Filtered {
+			content += "\nimage removed by safety filter: " + img.Safety.Reason
+			continue
+		}
+		f, err := os.Open(img.Path)
+		if err != nil || files >= discordMaxFiles {
+			if u := publicURL(img); u != "" {
+				content += "\n" + u
+			}
+			if f != nil {
+				_ = f.Close()
+			}
+			continue
+		}
+		part, err := mw.CreateFormFile(fmt.Sprintf("files[%d]", files), filepath.Base(img.Path))
+		if err == nil {
+			_, err = io.Copy(part, f)
+		}
+		_ = f.Close()
+		if err != nil {
+			return err
+		}
+		files++
+	}
+	// Discord limits messages to 2000 characters
+	if r := []rune(content); len(r) > 2000 {
+		content = string(r[:1997]) + "..."
+	}
+	payload, err := json.Marshal(map[string]any{
+		"content":          content,
+		"allowed_mentions": map[string]any{"parse": []string{}},
+	})
+	if err != nil {
+		return err
+	}
+	if err := mw.WriteField("payload_json", string(payload)); err != nil {
+		return err
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return do(req)
+}
+
+// publicURL returns the first http(s) location of the image.
+func publicURL(img providers.Image) string {
+	for _, location := range append([]string{img.Path}, img.Uploads...) {
+		if strings.HasPrefix(location, "https://") || strings.HasPrefix(location, "http://") {
+			return location
+		}
+	}
+	return ""
+}
+
+func do(req *http.Request) error {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package palette extracts the dominant colors of an image.
+package palette
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"slices"
+)
+
+// Color is a dominant color of an image.
+type Color struct {
+	R, G, B uint8
+	// Share is the fraction of the image's pixels closest to the color.
+	Share float64
+}
+
+// Hex returns the color like "#1a2b3c".
+func (c Color) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// HSL returns the hue in degrees and the saturation and lightness in
+// percent.
+func (c Color) HSL() (h, s, l float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	hi, lo := max(r, g, b), min(r, g, b)
+	l = (hi + lo) / 2
+	if d := hi - lo; d > 0 {
+		s = d / (1 - math.Abs(2*l-1))
+		switch hi {
+		case r:
+			h = math.Mod((g-b)/d, 6)
+		case g:
+			h = (b-r)/d + 2
+		default:
+			h = (r-g)/d + 4
+		}
+		h *= 60
+		if h < 0 {
+			h += 360
+		}
+	}
+	return math.Round(h), math.Round(s * 100), math.Round(l * 100)
+}
+
+// maxSamples limits the pixels clustered, larger images are sampled evenly.
+const maxSamples = 128 * 128
+
+// iterations of k-means, the clusters barely move after that.
+const iterations = 16
+
+// Extract returns up to n dominant colors of the image, most common first.
+// Transparent pixels are ignored.
+func Extract(img image.Image, n int) []Color {
+	pixels := sample(img)
+	if len(pixels) == 0 || n < 1 {
+		return nil
+	}
+	centers := initialCenters(pixels, n)
+	counts := make([]int, len(centers))
+	for range iterations {
+		sums := make([][3]float64, len(centers))
+		clear(counts)
+		for _, p := range pixels {
+			i := nearest(centers, p)
+			counts[i]++
+			for c := range 3 {
+				sums[i][c] += p[c]
+			}
+		}
+		for i := range centers {
+			if counts[i] > 0 {
+				for c := range 3 {
+					centers[i][c] = sums[i][c] / float64(counts[i])
+				}
+			}
+		}
+	}
+
+	var colors []Color
+	for i, center := range centers {
+		if counts[i] == 0 {
+			continue
+		}
+		colors = append(colors, Color{
+			R:     uint8(math.Round(center[0])),
+			G:     uint8(math.Round(center[1])),
+			B:     uint8(math.Round(center[2])),
+			Share: float64(counts[i]) / float64(len(pixels)),
+		})
+	}
+	slices.SortStableFunc(colors, func(a, b Color) int {
+		switch {
+		case a.Share > b.Share:
+			return -1
+		case a.Share < b.Share:
+			return 1
+		}
+		return 0
+	})
+	return colors
+}
+
+func sample(img image.Image) [][3]float64 {
+	b := img.Bounds()
+	step := max(1, int(math.Ceil(math.Sqrt(float64(b.Dx()*b.Dy())/maxSamples))))
+	var pixels [][3]float64
+	for y := b.Min.Y; y < b.Max.Y; y += step {
+		for x := b.Min.X; x < b.Max.X; x += step {
+			r, g, bl, a := img.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+			// undo the alpha premultiplication
+			pixels = append(pixels, [3]float64{
+				float64(r) * 255 / float64(a),
+				float64(g) * 255 / float64(a),
+				float64(bl) * 255 / float64(a),
+			})
+		}
+	}
+	return pixels
+}
+
+// initialCenters picks the first pixel and then repeatedly the pixel farthest
+// from all picked ones, which is deterministic unlike random seeding.
+func initialCenters(pixels [][3]float64, n int) [][3]float64 {
+	centers := [][3]float64{pixels[0]}
+	dist := make([]float64, len(pixels))
+	for i, p := range pixels {
+		dist[i] = distance(p, pixels[0])
+	}
+	for len(centers) < n {
+		farthest := 0
+		for i := range pixels {
+			if dist[i] > dist[farthest] {
+				farthest = i
+			}
+		}
+		if dist[farthest] == 0 {
+			// fewer distinct colors than requested
+			break
+		}
+		centers = append(centers, pixels[farthest])
+		for i, p := range pixels {
+			dist[i] = min(dist[i], distance(p, pixels[farthest]))
+		}
+	}
+	return centers
+}
+
+func nearest(centers [][3]float64, p [3]float64) int {
+	best, bestDist := 0, math.Inf(1)
+	for i, c := range centers {
+		if d := distance(p, c); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+func distance(a, b [3]float64) float64 {
+	// weighted for the eye's sensitivity, cheaper than a perceptual color
+	// space and good enough for picking a palette
+	dr, dg, db := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return 2*dr*dr + 4*dg*dg + 3*db*db
+}
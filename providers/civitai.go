@@ -0,0 +1,380 @@
+//go:build !no_civitai
+
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const civitaiAPI = "https://orchestration.civitai.com/v1/consumer/jobs"
+
+// civitaiPollInterval is how often the jobs of a request are checked.
+const civitaiPollInterval = 5 * time.Second
+
+func civitaiSettings(checkpoint string, size string) ModelSettings {
+	return ModelSettings{
+		{DisplayName: "Number of Images", Name: "number_of_images", Type: IntSetting{Min: 1, Max: 10}, DefaultValue: "1"},
+		{DisplayName: "Checkpoint (AIR)", Name: "checkpoint", Type: StringSetting{}, DefaultValue: checkpoint},
+		{DisplayName: "LoRAs (AIR[:strength], comma separated, or none)", Name: "loras", Type: StringSetting{}, DefaultValue: "none"},
+		{DisplayName: "Width", Name: "width", Type: IntSetting{Min: 64, Max: 2048}, DefaultValue: size},
+		{DisplayName: "Height", Name: "height", Type: IntSetting{Min: 64, Max: 2048}, DefaultValue: size},
+		{DisplayName: "Steps", Name: "steps", Type: IntSetting{Min: 1, Max: 50}, DefaultValue: "25"},
+		{DisplayName: "CFG Scale", Name: "cfg_scale", Type: FloatSetting{Min: 1, Max: 30}, DefaultValue: "7"},
+		{DisplayName: "Seed (-1 for random)", Name: "seed", Type: IntSetting{Min: -1, Max: math.MaxInt32}, DefaultValue: "-1"},
+		{DisplayName: "Scheduler", Name: "scheduler", Type: EnumSetting{Options: []string{"EulerA", "Euler", "DPM2MKarras", "DPMSDEKarras", "DDIM", "LCM"}}, DefaultValue: "EulerA"},
+	}
+}
+
+// The model is the base model family, the checkpoint setting selects the
+// community checkpoint by its AIR identifier.
+var CivitaiModels = []Model{
+	{Name: "sdxl", DisplayName: "Civitai SDXL", Settings: civitaiSettings("urn:air:sdxl:checkpoint:civitai:101055@128078", "1024"), Capabilities: Capabilities{MaxImages: 10}},
+	{Name: "sd1", DisplayName: "Civitai SD 1.5", Settings: civitaiSettings("urn:air:sd1:checkpoint:civitai:4201@130072", "512"), Capabilities: Capabilities{MaxImages: 10}},
+}
+
+// CivitaiProvider generates with community checkpoints and LoRAs on
+// Civitai's generation API.
+type CivitaiProvider struct {
+	// mu guards the lazy login, jobs of the provider run concurrently.
+	mu       sync.Mutex
+	apiToken string
+	client   *http.Client
+}
+
+func init() {
+	Providers = append(Providers, &CivitaiProvider{})
+}
+
+func (p *CivitaiProvider) GetName() string {
+	return "civitai"
+}
+
+func (p *CivitaiProvider) GetLoginFields() []LoginField {
+	return []LoginField{
+		{
+			Name:        "api_token",
+			DisplayName: "Civitai API Token",
+			Type:        "string",
+			Secret:      true,
+		},
+	}
+}
+
+func (p *CivitaiProvider) SaveCredentials(credentials map[string]string) error {
+	apiToken, ok := credentials["api_token"]
+	if !ok {
+		return fmt.Errorf("api_token not provided")
+	}
+	return SetSecret(p.GetName(), apiToken)
+}
+
+func (p *CivitaiProvider) LoadCredentials() (map[string]string, error) {
+	stored, err := GetSecret(p.GetName())
+	if errors.Is(err, ErrSecretNotFound) {
+		return nil, NewError(ErrorKindAuth, p.GetName(), fmt.Errorf("not logged in to Civitai"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load credentials: %w", err)
+	}
+	return map[string]string{"api_token": stored}, nil
+}
+
+func (p *CivitaiProvider) DeleteCredentials() error {
+	return DeleteSecret(p.GetName())
+}
+
+func (p *CivitaiProvider) Login(ctx context.Context, creds map[string]string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.login(ctx, creds)
+}
+
+func (p *CivitaiProvider) login(ctx context.Context, creds map[string]string) error {
+	if p.client != nil {
+		return nil
+	}
+	apiToken, ok := creds["api_token"]
+	if !ok || apiToken == "" {
+		return fmt.Errorf("api_token not provided")
+	}
+	client := &http.Client{Transport: newTransport(p.GetName())}
+	ctx, cancel := context.WithTimeout(ctx, loginTimeout(p.GetName(), 5*time.Second))
+	defer cancel()
+	// check the token, an unknown job token is fine but a bad API token is not
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, civitaiAPI+"?token=climage", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to verify API token: %w", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("failed to verify API token: %s", resp.Status)
+	}
+	p.apiToken = apiToken
+	p.client = client
+	return nil
+}
+
+func (p *CivitaiProvider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.client = nil
+	p.apiToken = ""
+	return nil
+}
+
+func (p *CivitaiProvider) ensureClient(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.client != nil {
+		return nil
+	}
+	credentials, err := loadCredentials(p)
+	if err != nil {
+		return err
+	}
+	if err := p.login(ctx, credentials); err != nil {
+		return NewError(ErrorKindAuth, p.GetName(), fmt.Errorf("failed to login to Civitai: %w", err))
+	}
+	return nil
+}
+
+type civitaiNetwork struct {
+	Type     string  `json:"type"`
+	Strength float64 `json:"strength"`
+}
+
+type civitaiJobsRequest struct {
+	Type               string                    `json:"$type"`
+	Model              string                    `json:"model"`
+	Params             civitaiParams             `json:"params"`
+	AdditionalNetworks map[string]civitaiNetwork `json:"additionalNetworks,omitempty"`
+	Quantity           int                       `json:"quantity"`
+}
+
+type civitaiParams struct {
+	Prompt    string  `json:"prompt"`
+	Scheduler string  `json:"scheduler"`
+	Steps     int     `json:"steps"`
+	CFGScale  float64 `json:"cfgScale"`
+	Width     int     `json:"width"`
+	Height    int     `json:"height"`
+	Seed      int64   `json:"seed"`
+}
+
+type civitaiJobsResponse struct {
+	Token string       `json:"token"`
+	Jobs  []civitaiJob `json:"jobs"`
+}
+
+type civitaiJob struct {
+	JobID     string         `json:"jobId"`
+	Scheduled bool           `json:"scheduled"`
+	Result    civitaiResults `json:"result"`
+}
+
+type civitaiResult struct {
+	Available bool   `json:"available"`
+	BlobURL   string `json:"blobUrl"`
+}
+
+// civitaiResults is the result of a job, which is either a single result or
+// a list of results.
+type civitaiResults []civitaiResult
+
+func (r *civitaiResults) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '{' {
+		var single civitaiResult
+		if err := json.Unmarshal(b, &single); err != nil {
+			return err
+		}
+		*r = civitaiResults{single}
+		return nil
+	}
+	return json.Unmarshal(b, (*[]civitaiResult)(r))
+}
+
+// done reports whether all results of the job are available. Jobs that are
+// no longer scheduled without a result have failed or were filtered.
+func (j civitaiJob) done() bool {
+	if len(j.Result) == 0 {
+		return !j.Scheduled
+	}
+	for _, r := range j.Result {
+		if !r.Available {
+			return false
+		}
+	}
+	return true
+}
+
+// parseLoras parses a comma separated list of LoRA AIR identifiers with an
+// optional strength, e.g. "urn:air:sdxl:lora:civitai:1@2:0.8".
+func parseLoras(s string) (map[string]civitaiNetwork, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "none" {
+		return nil, nil
+	}
+	loras := make(map[string]civitaiNetwork)
+	for _, lora := range strings.Split(s, ",") {
+		lora = strings.TrimSpace(lora)
+		air, strength := lora, 1.0
+		if i := strings.LastIndex(lora, ":"); i > 0 {
+			if v, err := strconv.ParseFloat(lora[i+1:], 64); err == nil && !strings.Contains(lora[i+1:], "@") {
+				air, strength = lora[:i], v
+			}
+		}
+		if !strings.HasPrefix(air, "urn:air:") {
+			return nil, fmt.Errorf("invalid LoRA %q: expected an AIR identifier like urn:air:sdxl:lora:civitai:1@2", lora)
+		}
+		loras[air] = civitaiNetwork{Type: "Lora", Strength: strength}
+	}
+	return loras, nil
+}
+
+func (p *CivitaiProvider) GenerateImage(ctx context.Context, model string, prompt string, settings ModelSettings) ([]Image, error) {
+	if err := p.ensureClient(ctx); err != nil {
+		return nil, err
+	}
+	loras, err := parseLoras(GetModelSettingString(settings, "loras", "none"))
+	if err != nil {
+		return nil, NewError(ErrorKindInvalidSettings, p.GetName(), err)
+	}
+	seed := int64(GetModelSettingInt(settings, "seed", -1))
+	if seed < 0 {
+		// pick the seed here so it can be recorded and reused
+		seed = rand.Int64N(math.MaxInt32)
+	}
+	ctx, cancel := context.WithTimeout(ctx, generateTimeout(p.GetName(), 10*time.Minute))
+	defer cancel()
+
+	var jobs civitaiJobsResponse
+	err = p.do(ctx, http.MethodPost, civitaiAPI, civitaiJobsRequest{
+		Type:  "textToImage",
+		Model: GetModelSettingString(settings, "checkpoint", ""),
+		Params: civitaiParams{
+			Prompt:    prompt,
+			Scheduler: GetModelSettingString(settings, "scheduler", "EulerA"),
+			Steps:     GetModelSettingInt(settings, "steps", 25),
+			CFGScale:  GetModelSettingFloat(settings, "cfg_scale", 7),
+			Width:     GetModelSettingInt(settings, "width", 1024),
+			Height:    GetModelSettingInt(settings, "height", 1024),
+			Seed:      seed,
+		},
+		AdditionalNetworks: loras,
+		Quantity:           GetModelSettingInt(settings, "number_of_images", 1),
+	}, &jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	for !allCivitaiJobsDone(jobs.Jobs) {
+		select {
+		case <-ctx.Done():
+			return nil, NewError(KindOf(ctx.Err()), p.GetName(), ctx.Err())
+		case <-time.After(civitaiPollInterval):
+		}
+		if err := p.do(ctx, http.MethodGet, civitaiAPI+"?token="+url.QueryEscape(jobs.Token), nil, &jobs); err != nil {
+			return nil, err
+		}
+	}
+
+	var data []imageData
+	for _, j := range jobs.Jobs {
+		if len(j.Result) == 0 {
+			return nil, NewError(ErrorKindUnknown, p.GetName(), fmt.Errorf("job %s returned no image", j.JobID))
+		}
+		for _, r := range j.Result {
+			data = append(data, imageData{URL: r.BlobURL, Seed: &seed, ResponseID: j.JobID})
+		}
+	}
+	return saveImages(ctx, p.GetName(), prompt, data)
+}
+
+func allCivitaiJobsDone(jobs []civitaiJob) bool {
+	for _, j := range jobs {
+		if !j.done() {
+			return false
+		}
+	}
+	return true
+}
+
+// do sends a request to the API and decodes the JSON response into v.
+func (p *CivitaiProvider) do(ctx context.Context, method string, u string, body any, v any) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return NewError(KindOf(err), p.GetName(), err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return NewError(kindOfHTTPStatus(resp.StatusCode), p.GetName(), fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg))))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return NewError(ErrorKindUnknown, p.GetName(), fmt.Errorf("failed to decode response: %w", err))
+	}
+	return nil
+}
+
+func (p *CivitaiProvider) GetModels() []Model {
+	return CivitaiModels
+}
+
+func (p *CivitaiProvider) GetModelSettings(model string) []ModelSetting { return nil }
+
+func (p *CivitaiProvider) GetSettings() any {
+	return nil
+}
@@ -0,0 +1,104 @@
+//go:build !no_civitai
+
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/bloodmagesoftware/climage/providers/providertest"
+)
+
+func TestCivitaiConformance(t *testing.T) {
+	providertest.Run(t, &providers.CivitaiProvider{}, providertest.Options{
+		Credentials: map[string]string{"api_token": "test"},
+	})
+}
+
+// civitaiFake answers the requests of the Civitai provider with a finished
+// job and a placeholder image.
+type civitaiFake struct{}
+
+func (civitaiFake) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	switch {
+	case req.URL.Host == "blob.test":
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 1, 1))); err != nil {
+			return nil, err
+		}
+		body = buf.Bytes()
+	case req.Method == http.MethodPost:
+		body = []byte(`{"token": "t", "jobs": [{"jobId": "1", "result": {"available": true, "blobUrl": "https://blob.test/1.png"}}]}`)
+	default:
+		body = []byte(`{"token": "t", "jobs": []}`)
+	}
+	return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Header: http.Header{}, Body: io.NopCloser(bytes.NewReader(body)), Request: req}, nil
+}
+
+// TestCivitaiConcurrentJobs runs two jobs against a provider that isn't
+// logged in yet, run it with -race.
+func TestCivitaiConcurrentJobs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("APPDATA", home)
+	if err := providers.SetCredentialStore(providers.CredentialStoreFile); err != nil {
+		t.Fatal(err)
+	}
+	providers.SetOutDir(t.TempDir())
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = civitaiFake{}
+	t.Cleanup(func() {
+		http.DefaultTransport = defaultTransport
+		_ = providers.SetCredentialStore(providers.CredentialStoreKeyring)
+		providers.SetOutDir("")
+	})
+
+	p := &providers.CivitaiProvider{}
+	if err := p.SaveCredentials(map[string]string{"api_token": "test"}); err != nil {
+		t.Fatal(err)
+	}
+	m := p.GetModels()[0]
+	settings := m.Settings.Clone()
+	for _, s := range settings {
+		s.Value = s.DefaultValue
+	}
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Go(func() {
+			images, err := p.GenerateImage(t.Context(), m.Name, "a lighthouse at dusk", settings.Clone())
+			if err != nil {
+				t.Error(err)
+			} else if len(images) != 1 {
+				t.Errorf("got %d images, want 1", len(images))
+			}
+		})
+	}
+	wg.Wait()
+	_ = p.Close()
+}
@@ -0,0 +1,177 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxDebugBody is how much of a request or response body is logged.
+const maxDebugBody = 2048
+
+// debugHeaders are logged with their values, all other headers are redacted.
+var debugHeaders = []string{"Content-Type", "Content-Length", "Retry-After", "User-Agent", "X-Request-Id"}
+
+var (
+	debugMu   sync.Mutex
+	debugFile *os.File
+)
+
+// EnableHTTPDebug logs the HTTP requests of all providers to a file: method,
+// URL, status, latency and the beginning of text bodies. Secrets are
+// redacted.
+func EnableHTTPDebug(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open HTTP debug log: %w", err)
+	}
+	debugMu.Lock()
+	defer debugMu.Unlock()
+	if debugFile != nil {
+		_ = debugFile.Close()
+	}
+	debugFile = f
+	return nil
+}
+
+// CloseHTTPDebug closes the HTTP debug log.
+func CloseHTTPDebug() error {
+	debugMu.Lock()
+	defer debugMu.Unlock()
+	if debugFile == nil {
+		return nil
+	}
+	err := debugFile.Close()
+	debugFile = nil
+	return err
+}
+
+// debugTransport wraps base with the HTTP debug log if it is enabled.
+func debugTransport(providerName string, base http.RoundTripper) http.RoundTripper {
+	debugMu.Lock()
+	defer debugMu.Unlock()
+	if debugFile == nil {
+		return base
+	}
+	return &httpDebugTransport{base: base, provider: providerName}
+}
+
+type httpDebugTransport struct {
+	base     http.RoundTripper
+	provider string
+}
+
+func (t *httpDebugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s %s %s\n", time.Now().Format(time.RFC3339Nano), t.provider, req.Method, scrub(req.URL.String()))
+	writeDebugHeaders(&b, "> ", req.Header)
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			prefix, _ := io.ReadAll(io.LimitReader(body, maxDebugBody+1))
+			_ = body.Close()
+			writeDebugBody(&b, "> ", req.Header.Get("Content-Type"), prefix)
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	latency := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(&b, "< error after %s: %s\n", latency, scrub(err.Error()))
+		writeDebugLog(b.String())
+		return nil, err
+	}
+	fmt.Fprintf(&b, "< %s in %s\n", resp.Status, latency)
+	writeDebugHeaders(&b, "< ", resp.Header)
+	// only the logged prefix is read ahead, the rest is streamed as usual
+	prefix, readErr := io.ReadAll(io.LimitReader(resp.Body, maxDebugBody+1))
+	writeDebugBody(&b, "< ", resp.Header.Get("Content-Type"), prefix)
+	writeDebugLog(b.String())
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(prefix), errReader{readErr}, resp.Body), resp.Body}
+	return resp, nil
+}
+
+// errReader returns err once it is reached, or EOF if err is nil.
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
+
+func writeDebugHeaders(b *strings.Builder, prefix string, header http.Header) {
+	for _, name := range debugHeaders {
+		for _, v := range header.Values(name) {
+			fmt.Fprintf(b, "%s%s: %s\n", prefix, name, v)
+		}
+	}
+	for name := range header {
+		if !containsFold(debugHeaders, name) {
+			fmt.Fprintf(b, "%s%s: REDACTED\n", prefix, name)
+		}
+	}
+}
+
+func writeDebugBody(b *strings.Builder, prefix string, contentType string, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	if !strings.Contains(contentType, "json") && !strings.HasPrefix(contentType, "text/") && !strings.Contains(contentType, "form") {
+		fmt.Fprintf(b, "%s[%s body]\n", prefix, contentType)
+		return
+	}
+	// scrub before truncating, so secrets at the end are still found
+	text := scrub(string(body))
+	if len(text) > maxDebugBody {
+		text = text[:maxDebugBody] + "..."
+	}
+	fmt.Fprintf(b, "%s%s\n", prefix, text)
+}
+
+func writeDebugLog(s string) {
+	debugMu.Lock()
+	defer debugMu.Unlock()
+	if debugFile != nil {
+		_, _ = debugFile.WriteString(s + "\n")
+	}
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ModelLister is implemented by providers that can list their models at
+// runtime. Only models the provider knows how to call are returned.
+type ModelLister interface {
+	ListModels(ctx context.Context) ([]Model, error)
+}
+
+// modelCache is the cache file of the discovered models of a provider.
+type modelCache struct {
+	Updated time.Time `json:"updated"`
+	Models  []Model   `json:"models"`
+}
+
+var (
+	modelCacheMu sync.Mutex
+	// discovered are the cached models per provider, loaded on first use so
+	// the settings of a model are shared like those of the built-in models.
+	discovered = make(map[string]*modelCache)
+)
+
+// Models returns the built-in models of the provider followed by the models
+// found by the last RefreshModels.
+func Models(p Provider) []Model {
+	models := p.GetModels()
+	cache := loadModelCache(p.GetName())
+	if cache == nil {
+		return models
+	}
+	models = slices.Clone(models)
+	for _, m := range cache.Models {
+		if !slices.ContainsFunc(models, func(b Model) bool { return b.Name == m.Name }) {
+			models = append(models, m)
+		}
+	}
+	return models
+}
+
+// ModelsUpdated returns when the models of the provider were last refreshed.
+func ModelsUpdated(p Provider) (time.Time, bool) {
+	cache := loadModelCache(p.GetName())
+	if cache == nil {
+		return time.Time{}, false
+	}
+	return cache.Updated, true
+}
+
+// RefreshModels queries the provider's model list and caches the models
+// that are not built in. It returns the newly discovered models.
+func RefreshModels(ctx context.Context, p Provider) ([]Model, error) {
+	lister, ok := p.(ModelLister)
+	if !ok {
+		return nil, fmt.Errorf("provider %s can't list its models", p.GetName())
+	}
+	listed, err := lister.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	builtIn := p.GetModels()
+	cache := &modelCache{Updated: time.Now()}
+	for _, m := range listed {
+		if !slices.ContainsFunc(builtIn, func(b Model) bool { return b.Name == m.Name }) {
+			cache.Models = append(cache.Models, m)
+		}
+	}
+
+	cachePath, err := modelCachePath(p.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create model cache dir: %w", err)
+	}
+	b, err := json.Marshal(cache)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode model cache: %w", err)
+	}
+	if err := WriteFileAtomic(cachePath, b, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write model cache: %w", err)
+	}
+	modelCacheMu.Lock()
+	discovered[p.GetName()] = cache
+	modelCacheMu.Unlock()
+	return cache.Models, nil
+}
+
+func modelCachePath(providerName string) (string, error) {
+	dataDir, err := DataDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get data dir: %w", err)
+	}
+	return filepath.Join(dataDir, "models", providerName+".json"), nil
+}
+
+func loadModelCache(providerName string) *modelCache {
+	modelCacheMu.Lock()
+	defer modelCacheMu.Unlock()
+	if cache, ok := discovered[providerName]; ok {
+		return cache
+	}
+	var cache *modelCache
+	if cachePath, err := modelCachePath(providerName); err == nil {
+		b, err := os.ReadFile(cachePath)
+		if err == nil {
+			cache = &modelCache{}
+			if err := json.Unmarshal(b, cache); err != nil {
+				log.Printf("warning: ignoring model cache of %s: %v", providerName, err)
+				cache = nil
+			}
+		} else if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("warning: failed to read model cache of %s: %v", providerName, err)
+		}
+	}
+	discovered[providerName] = cache
+	return cache
+}
@@ -0,0 +1,92 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// headless disables the OS keyring and the stored provider credentials, see
+// SetHeadless.
+var headless bool
+
+// SetHeadless enables the headless mode for containers and CI, in which the
+// provider credentials are only read from the environment, see CredentialEnv,
+// and the OS keyring is never used.
+func SetHeadless(h bool) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	headless = h
+}
+
+// CredentialEnv returns the environment variable of a login field, e.g.
+// CLIMAGE_GOOGLE_AI_API_KEY. File fields are set to the path of the file.
+func CredentialEnv(p Provider, field LoginField) string {
+	name := "CLIMAGE_" + p.GetName() + "_" + field.Name
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// HasEnvCredentials reports whether all login fields of the provider are set
+// in the environment.
+func HasEnvCredentials(p Provider) bool {
+	fields := p.GetLoginFields()
+	for _, field := range fields {
+		if os.Getenv(CredentialEnv(p, field)) == "" {
+			return false
+		}
+	}
+	return len(fields) > 0
+}
+
+// loadCredentials returns the credentials from the environment if all login
+// fields of the provider are set there, or else the stored credentials. In
+// headless mode only the environment is used.
+func loadCredentials(p Provider) (map[string]string, error) {
+	credentials := make(map[string]string)
+	var missing []string
+	for _, field := range p.GetLoginFields() {
+		env := CredentialEnv(p, field)
+		value := os.Getenv(env)
+		if value == "" {
+			missing = append(missing, env)
+			continue
+		}
+		if field.Type == "file" {
+			b, err := os.ReadFile(value)
+			if err != nil {
+				return nil, NewError(ErrorKindAuth, p.GetName(), fmt.Errorf("failed to read %s: %w", env, err))
+			}
+			value = base64.StdEncoding.EncodeToString(b)
+		}
+		credentials[field.Name] = value
+	}
+	if len(missing) == 0 {
+		return credentials, nil
+	}
+	secretsMu.Lock()
+	h := headless
+	secretsMu.Unlock()
+	if h {
+		return nil, NewError(ErrorKindAuth, p.GetName(), fmt.Errorf("no credentials in headless mode, set %s", strings.Join(missing, ", ")))
+	}
+	return p.LoadCredentials()
+}
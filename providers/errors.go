@@ -0,0 +1,145 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// ErrorKind is the category of a failed request. Every kind maps to its own
+// process exit code so scripts can branch on the failure type.
+type ErrorKind uint8
+
+const (
+	ErrorKindUnknown ErrorKind = iota
+	ErrorKindAuth
+	ErrorKindQuota
+	ErrorKindContentPolicy
+	ErrorKindInvalidSettings
+	ErrorKindNetwork
+	ErrorKindOutage
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorKindAuth:
+		return "auth"
+	case ErrorKindQuota:
+		return "quota"
+	case ErrorKindContentPolicy:
+		return "content_policy"
+	case ErrorKindInvalidSettings:
+		return "invalid_settings"
+	case ErrorKindNetwork:
+		return "network"
+	case ErrorKindOutage:
+		return "provider_outage"
+	default:
+		return "unknown"
+	}
+}
+
+// ExitCode returns the process exit code for the error kind. Exit code 2 is
+// left for command line usage errors.
+func (k ErrorKind) ExitCode() int {
+	switch k {
+	case ErrorKindAuth:
+		return 3
+	case ErrorKindQuota:
+		return 4
+	case ErrorKindContentPolicy:
+		return 5
+	case ErrorKindInvalidSettings:
+		return 6
+	case ErrorKindNetwork:
+		return 7
+	case ErrorKindOutage:
+		return 8
+	default:
+		return 1
+	}
+}
+
+// Error is a categorized provider error.
+type Error struct {
+	Kind     ErrorKind
+	Provider string
+	Err      error
+}
+
+func (e *Error) Error() string {
+	if e.Provider == "" {
+		return fmt.Sprintf("%s error: %v", e.Kind, e.Err)
+	}
+	return fmt.Sprintf("%s: %s error: %v", e.Provider, e.Kind, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NewError wraps err with the given kind. A nil err stays nil.
+func NewError(kind ErrorKind, provider string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Provider: provider, Err: err}
+}
+
+// KindOf returns the kind of the first categorized error in err's chain.
+// Network failures that were not categorized by a provider are detected too.
+func KindOf(err error) ErrorKind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		return ErrorKindNetwork
+	}
+	return ErrorKindUnknown
+}
+
+// ExitCode returns the process exit code for err.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	return KindOf(err).ExitCode()
+}
+
+// kindOfHTTPStatus categorizes an HTTP status code of a provider API.
+func kindOfHTTPStatus(code int) ErrorKind {
+	switch {
+	case code == http.StatusUnauthorized, code == http.StatusForbidden:
+		return ErrorKindAuth
+	case code == http.StatusTooManyRequests, code == http.StatusPaymentRequired:
+		return ErrorKindQuota
+	case code == http.StatusBadRequest, code == http.StatusNotFound, code == http.StatusUnprocessableEntity:
+		return ErrorKindInvalidSettings
+	case code >= 500:
+		return ErrorKindOutage
+	default:
+		return ErrorKindUnknown
+	}
+}
@@ -1,3 +1,5 @@
+//go:build !no_google
+
 /*
 CLImage is a AI image generation CLI tool.
 Copyright (C) 2025  Mayer & Ott GbR
@@ -22,43 +24,133 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"cloud.google.com/go/auth/credentials"
-	"github.com/zalando/go-keyring"
+	"cloud.google.com/go/auth/httptransport"
 	"google.golang.org/genai"
 )
 
-var googleSettings = ModelSettings{
-	{DisplayName: "Number of Images", Name: "number_of_images", Type: "int", DefaultValue: "1"},
-	{DisplayName: "Aspect Ratio", Name: "aspect_ratio", Type: "enum:1:1|16:9|4:3|9:16|3:4", DefaultValue: "1:1"},
-	{DisplayName: "Output Resolution", Name: "output_resolution", Type: "enum:1K|2K", DefaultValue: "1K"},
+var googleSettings = append(ModelSettings{
+	{DisplayName: "Number of Images", Name: "number_of_images", Type: IntSetting{Min: 1, Max: 4}, DefaultValue: "1"},
+	{DisplayName: "Aspect Ratio", Name: "aspect_ratio", Type: EnumSetting{Options: []string{"1:1", "16:9", "4:3", "9:16", "3:4"}}, DefaultValue: "1:1"},
+	{DisplayName: "Output Resolution", Name: "output_resolution", Type: EnumSetting{Options: []string{"1K", "2K"}}, DefaultValue: "1K"},
+}, imagenPolicySettings()...)
+
+// Imagen 4 Fast only supports 1K output.
+var googleFastSettings = append(ModelSettings{
+	{DisplayName: "Number of Images", Name: "number_of_images", Type: IntSetting{Min: 1, Max: 4}, DefaultValue: "1"},
+	{DisplayName: "Aspect Ratio", Name: "aspect_ratio", Type: EnumSetting{Options: []string{"1:1", "16:9", "4:3", "9:16", "3:4"}}, DefaultValue: "1:1"},
+	{DisplayName: "Output Resolution", Name: "output_resolution", Type: EnumSetting{Options: []string{"1K"}}, DefaultValue: "1K"},
+}, imagenPolicySettings()...)
+
+// imagenPolicySettings returns the content policy settings of Imagen. The
+// values are the API's in lower case.
+func imagenPolicySettings() ModelSettings {
+	return ModelSettings{
+		{DisplayName: "Safety Filter Level", Name: "safety_filter_level", Type: EnumSetting{Options: []string{"block_low_and_above", "block_medium_and_above", "block_only_high", "block_none"}}, DefaultValue: "block_medium_and_above"},
+		{DisplayName: "Person Generation", Name: "person_generation", Type: EnumSetting{Options: []string{"dont_allow", "allow_adult", "allow_all"}}, DefaultValue: "allow_adult"},
+		{DisplayName: "Add Watermark (Vertex AI only)", Name: "add_watermark", Type: BoolSetting{}, DefaultValue: "true"},
+	}
+}
+
+// imagenPolicy are the content policy settings of an Imagen request.
+type imagenPolicy struct {
+	safetyFilterLevel genai.SafetyFilterLevel
+	personGeneration  genai.PersonGeneration
+	// addWatermark is nil on the Gemini API, which doesn't support it.
+	addWatermark *bool
+}
+
+func (p *GoogleProvider) imagenPolicy(settings ModelSettings) imagenPolicy {
+	policy := imagenPolicy{
+		safetyFilterLevel: genai.SafetyFilterLevel(strings.ToUpper(GetModelSettingString(settings, "safety_filter_level", ""))),
+		personGeneration:  genai.PersonGeneration(strings.ToUpper(GetModelSettingString(settings, "person_generation", ""))),
+	}
+	if !p.aiStudio {
+		addWatermark := GetModelSettingBool(settings, "add_watermark", true)
+		policy.addWatermark = &addWatermark
+	}
+	return policy
+}
+
+// watermark returns the watermark of the images, the Gemini API always adds
+// it.
+func (policy imagenPolicy) watermark() string {
+	if policy.addWatermark == nil || *policy.addWatermark {
+		return WatermarkSynthID
+	}
+	return ""
+}
+
+// httpOptions returns the request options to disable the watermark, the
+// generate config can't send false.
+func (policy imagenPolicy) httpOptions() *genai.HTTPOptions {
+	if policy.addWatermark == nil || *policy.addWatermark {
+		return nil
+	}
+	return &genai.HTTPOptions{ExtraBody: map[string]any{
+		"parameters": map[string]any{"addWatermark": false},
+	}}
 }
 
 var GoogleModels = []Model{
-	{Name: "imagen-4.0-generate-001", DisplayName: "Imagen 4", Settings: googleSettings},
-	{Name: "imagen-4.0-ultra-generate-001", DisplayName: "Imagen 4 Ultra", Settings: googleSettings},
-	{Name: "imagen-4.0-fast-generate-001", DisplayName: "Imagen 4 Fast", Settings: googleSettings},
+	{Name: "imagen-4.0-generate-001", DisplayName: "Imagen 4", Settings: googleSettings, Capabilities: Capabilities{MaxPromptTokens: 480, MaxImages: 4, MaxInputImages: 3, EditModes: imagenEditModes}, PricePerImage: 0.04},
+	{Name: "imagen-4.0-ultra-generate-001", DisplayName: "Imagen 4 Ultra", Settings: googleSettings, Capabilities: Capabilities{MaxPromptTokens: 480, MaxImages: 1, MaxInputImages: 3, EditModes: imagenEditModes}, PricePerImage: 0.06},
+	{Name: "imagen-4.0-fast-generate-001", DisplayName: "Imagen 4 Fast", Settings: googleFastSettings, Capabilities: Capabilities{MaxPromptTokens: 480, MaxImages: 4, MaxInputImages: 3, EditModes: imagenEditModes}, PricePerImage: 0.02},
+	{Name: "gemini-2.5-flash-image", DisplayName: "Gemini 2.5 Flash Image", Settings: geminiSettings, Capabilities: Capabilities{MaxPromptTokens: 32768, MaxImages: 4, MaxInputImages: 3, EditModes: []string{EditModeDefault}}, PricePerImage: 0.039},
+	{Name: "veo-3.0-generate-001", DisplayName: "Veo 3", Settings: veoSettings, Capabilities: Capabilities{MaxPromptTokens: 1024, MaxImages: 4}, Media: MediaVideo, PricePerImage: 3.20},
+	{Name: "veo-3.0-fast-generate-001", DisplayName: "Veo 3 Fast", Settings: veoSettings, Capabilities: Capabilities{MaxPromptTokens: 1024, MaxImages: 4}, Media: MediaVideo, PricePerImage: 1.20},
 }
 
+// googleEditModel is the Imagen capability model that edits with masks.
+const googleEditModel = "imagen-3.0-capability-001"
+
+// GoogleProvider generates with Vertex AI, or with the Gemini API if aiStudio
+// is set. The Gemini API only needs an API key from Google AI Studio.
 type GoogleProvider struct {
-	client *genai.Client
+	aiStudio bool
+	// clientMu guards the lazy login, jobs of the provider run concurrently.
+	clientMu sync.Mutex
+	client   *genai.Client
+
+	// clientConfig is the config of the Vertex AI client, clients holds the
+	// client of every location requests were sent to, see inLocations.
+	clientConfig genai.ClientConfig
+	clientsMu    sync.Mutex
+	clients      map[string]*genai.Client
 }
 
 func init() {
-	Providers = append(Providers, &GoogleProvider{})
+	Providers = append(Providers, &GoogleProvider{}, &GoogleProvider{aiStudio: true})
 }
 
 func (p *GoogleProvider) GetName() string {
+	if p.aiStudio {
+		return "google-ai"
+	}
 	return "google"
 }
 
 func (p *GoogleProvider) GetLoginFields() []LoginField {
+	if p.aiStudio {
+		return []LoginField{
+			{
+				Name:        "api_key",
+				DisplayName: "Google AI Studio API Key",
+				Type:        "string",
+				Secret:      true,
+			},
+		}
+	}
 	return []LoginField{
 		{
 			Name:        "service_account_key",
@@ -87,6 +179,13 @@ type googleCredentials struct {
 }
 
 func (p *GoogleProvider) SaveCredentials(credentials map[string]string) error {
+	if p.aiStudio {
+		apiKey, ok := credentials["api_key"]
+		if !ok {
+			return fmt.Errorf("api_key not provided")
+		}
+		return SetSecret(p.GetName(), apiKey)
+	}
 	serviceAccountKeyB64, ok := credentials["service_account_key"]
 	if !ok {
 		return fmt.Errorf("service_account_key not provided")
@@ -104,7 +203,7 @@ func (p *GoogleProvider) SaveCredentials(credentials map[string]string) error {
 		return fmt.Errorf("location not provided")
 	}
 
-	dataDir, err := getDataDir()
+	dataDir, err := DataDir()
 	if err != nil {
 		return fmt.Errorf("failed to get data dir: %w", err)
 	}
@@ -125,13 +224,19 @@ func (p *GoogleProvider) SaveCredentials(credentials map[string]string) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal credentials: %w", err)
 	}
-	return keyring.Set(keyringServiceName, "google", string(encoded))
+	return SetSecret("google", string(encoded))
 }
 
 func (p *GoogleProvider) LoadCredentials() (map[string]string, error) {
-	stored, err := keyring.Get(keyringServiceName, "google")
+	stored, err := GetSecret(p.GetName())
+	if errors.Is(err, ErrSecretNotFound) {
+		return nil, NewError(ErrorKindAuth, p.GetName(), fmt.Errorf("not logged in to %s", p.displayName()))
+	}
 	if err != nil {
-		return nil, fmt.Errorf("not logged in to Google")
+		return nil, fmt.Errorf("failed to load credentials: %w", err)
+	}
+	if p.aiStudio {
+		return map[string]string{"api_key": stored}, nil
 	}
 
 	var creds googleCredentials
@@ -139,7 +244,7 @@ func (p *GoogleProvider) LoadCredentials() (map[string]string, error) {
 		return nil, fmt.Errorf("failed to unmarshal credentials: %w", err)
 	}
 
-	dataDir, err := getDataDir()
+	dataDir, err := DataDir()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get data dir: %w", err)
 	}
@@ -157,7 +262,10 @@ func (p *GoogleProvider) LoadCredentials() (map[string]string, error) {
 }
 
 func (p *GoogleProvider) DeleteCredentials() error {
-	dataDir, err := getDataDir()
+	if p.aiStudio {
+		return DeleteSecret(p.GetName())
+	}
+	dataDir, err := DataDir()
 	if err != nil {
 		return fmt.Errorf("failed to get data dir: %w", err)
 	}
@@ -165,13 +273,22 @@ func (p *GoogleProvider) DeleteCredentials() error {
 	if err := os.RemoveAll(credentialDir); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to remove credential dir: %w", err)
 	}
-	return keyring.Delete(keyringServiceName, "google")
+	return DeleteSecret("google")
 }
 
 func (p *GoogleProvider) Login(ctx context.Context, creds map[string]string) error {
+	p.clientMu.Lock()
+	defer p.clientMu.Unlock()
+	return p.login(ctx, creds)
+}
+
+func (p *GoogleProvider) login(ctx context.Context, creds map[string]string) error {
 	if p.client != nil {
 		return nil
 	}
+	if p.aiStudio {
+		return p.loginAPIKey(ctx, creds)
+	}
 	serviceAccountKeyB64, ok := creds["service_account_key"]
 	if !ok {
 		return fmt.Errorf("service_account_key not provided")
@@ -188,95 +305,204 @@ func (p *GoogleProvider) Login(ctx context.Context, creds map[string]string) err
 	if !ok {
 		return fmt.Errorf("location not provided")
 	}
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, loginTimeout(p.GetName(), 5*time.Second))
 	defer cancel()
 	authCreds, err := credentials.DetectDefault(&credentials.DetectOptions{
 		CredentialsJSON: serviceAccountKey,
 		Scopes:          []string{"https://www.googleapis.com/auth/cloud-platform"},
+		// tokens are fetched through the proxy of the provider as well
+		Client: &http.Client{Transport: baseTransport(p.GetName())},
 	})
 	if err != nil {
 		return fmt.Errorf("failed to detect credentials: %w", err)
 	}
-	client, err := genai.NewClient(ctx, &genai.ClientConfig{
+	quotaProjectID, err := authCreds.QuotaProjectID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get quota project ID: %w", err)
+	}
+	httpClient, err := httptransport.NewClient(&httptransport.Options{
+		Credentials: authCreds,
+		Headers: http.Header{
+			"X-Goog-User-Project": []string{quotaProjectID},
+		},
+		BaseRoundTripper: newTransport(p.GetName()),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+	clientConfig := genai.ClientConfig{
 		Project:     projectID,
 		Location:    location,
 		Backend:     genai.BackendVertexAI,
 		Credentials: authCreds,
+		HTTPClient:  httpClient,
+	}
+	client, err := genai.NewClient(ctx, &clientConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create GenAI client: %w", err)
+	}
+	p.client = client
+	p.clientsMu.Lock()
+	p.clientConfig = clientConfig
+	p.clients = map[string]*genai.Client{location: client}
+	p.clientsMu.Unlock()
+	return nil
+}
+
+func (p *GoogleProvider) loginAPIKey(ctx context.Context, creds map[string]string) error {
+	apiKey, ok := creds["api_key"]
+	if !ok {
+		return fmt.Errorf("api_key not provided")
+	}
+	ctx, cancel := context.WithTimeout(ctx, loginTimeout(p.GetName(), 5*time.Second))
+	defer cancel()
+	client, err := genai.NewClient(ctx, &genai.ClientConfig{
+		APIKey:     apiKey,
+		Backend:    genai.BackendGeminiAPI,
+		HTTPClient: &http.Client{Transport: newTransport(p.GetName())},
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create GenAI client: %w", err)
 	}
+	// check the key, creating the client doesn't send a request
+	if _, err := client.Models.Get(ctx, GoogleModels[0].Name, nil); err != nil {
+		return fmt.Errorf("failed to verify API key: %w", err)
+	}
 	p.client = client
 	return nil
 }
 
+func (p *GoogleProvider) displayName() string {
+	if p.aiStudio {
+		return "Google AI Studio"
+	}
+	return "Google"
+}
+
 func (p *GoogleProvider) Close() error {
+	p.clientMu.Lock()
 	p.client = nil
+	p.clientMu.Unlock()
+	p.clientsMu.Lock()
+	p.clients = nil
+	p.clientsMu.Unlock()
 	return nil
 }
 
-func (p *GoogleProvider) GenerateImage(ctx context.Context, model string, prompt string, settings ModelSettings) ([]string, error) {
-	if p.client == nil {
-		credentials, err := p.LoadCredentials()
-		if err != nil {
-			return nil, err
-		}
-		if err := p.Login(ctx, credentials); err != nil {
-			return nil, fmt.Errorf("failed to login to Google: %w", err)
-		}
+func (p *GoogleProvider) ensureClient(ctx context.Context) error {
+	p.clientMu.Lock()
+	defer p.clientMu.Unlock()
+	if p.client != nil {
+		return nil
+	}
+	credentials, err := loadCredentials(p)
+	if err != nil {
+		return err
+	}
+	if err := p.login(ctx, credentials); err != nil {
+		return NewError(ErrorKindAuth, p.GetName(), fmt.Errorf("failed to login to %s: %w", p.displayName(), err))
+	}
+	return nil
+}
+
+func (p *GoogleProvider) GenerateImage(ctx context.Context, model string, prompt string, settings ModelSettings) ([]Image, error) {
+	if err := p.ensureClient(ctx); err != nil {
+		return nil, err
 	}
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	if isGeminiModel(model) {
+		return p.generateGemini(ctx, model, prompt, nil, settings)
+	}
+	ctx, cancel := context.WithTimeout(ctx, generateTimeout(p.GetName(), 5*time.Minute))
 	defer cancel()
-	resp, err := p.client.Models.GenerateImages(ctx, model, prompt, &genai.GenerateImagesConfig{
-		NumberOfImages:   int32(GetModelSettingInt(settings, "number_of_images", 1)),
-		AspectRatio:      GetModelSettingString(settings, "aspect_ratio", "1:1"),
-		ImageSize:        GetModelSettingString(settings, "output_resolution", "1K"),
-		IncludeRAIReason: true,
+	policy := p.imagenPolicy(settings)
+	var resp *genai.GenerateImagesResponse
+	err := p.inLocations(ctx, func(client *genai.Client) (err error) {
+		resp, err = client.Models.GenerateImages(ctx, model, prompt, &genai.GenerateImagesConfig{
+			HTTPOptions:             policy.httpOptions(),
+			NumberOfImages:          int32(GetModelSettingInt(settings, "number_of_images", 1)),
+			AspectRatio:             GetModelSettingString(settings, "aspect_ratio", "1:1"),
+			ImageSize:               GetModelSettingString(settings, "output_resolution", "1K"),
+			SafetyFilterLevel:       policy.safetyFilterLevel,
+			PersonGeneration:        policy.personGeneration,
+			AddWatermark:            policy.addWatermark != nil && *policy.addWatermark,
+			IncludeRAIReason:        true,
+			IncludeSafetyAttributes: true,
+		})
+		return err
 	})
 	if err != nil {
-		return nil, fmt.Errorf("google: %w", err)
+		return nil, googleError(p.GetName(), err)
 	}
 
-	dir, err := getOutDir()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get out dir: %w", err)
+	return saveGoogleImages(ctx, p.GetName(), prompt, resp.GeneratedImages, policy.watermark())
+}
+
+// GenerateImageWithSubjects passes the reference images of the subjects to
+// Gemini models. Imagen models only generate with the descriptions, subjects
+// with reference images are rejected instead of switching to the Imagen
+// capability model behind the user's back.
+func (p *GoogleProvider) GenerateImageWithSubjects(ctx context.Context, model string, prompt string, subjects []Subject, settings ModelSettings) ([]Image, error) {
+	if err := p.ensureClient(ctx); err != nil {
+		return nil, err
 	}
-	_ = os.MkdirAll(dir, 0755)
-	nowDateTime := time.Now().Format(time.RFC3339)
-	var filePaths []string
-	for i, img := range resp.GeneratedImages {
-		if len(img.RAIFilteredReason) > 0 {
-			fmt.Printf("RAI Filtered: %s\n", img.RAIFilteredReason)
+	if isGeminiModel(model) {
+		// Gemini takes the reference images as input images
+		var images [][]byte
+		for _, s := range subjects {
+			b, err := s.ReadImages(ctx)
+			if err != nil {
+				return nil, NewError(ErrorKindInvalidSettings, p.GetName(), err)
+			}
+			images = append(images, b...)
 		}
-		if img.Image == nil || len(img.Image.ImageBytes) == 0 {
-			continue
+		return p.generateGemini(ctx, model, prompt, images, settings)
+	}
+	if slices.ContainsFunc(subjects, func(s Subject) bool { return len(s.Images) > 0 }) {
+		return nil, NewError(ErrorKindInvalidSettings, p.GetName(), fmt.Errorf("model %s can't use the reference images of characters, use a Gemini image model or characters without images", model))
+	}
+	return p.GenerateImage(ctx, model, prompt, settings)
+}
+
+// googleError categorizes an error returned by the GenAI SDK.
+func googleError(provider string, err error) error {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		kind := kindOfHTTPStatus(apiErr.Code)
+		if kind == ErrorKindInvalidSettings {
+			msg := strings.ToLower(apiErr.Message)
+			for _, word := range []string{"safety", "policy", "prohibited", "sensitive"} {
+				if strings.Contains(msg, word) {
+					kind = ErrorKindContentPolicy
+					break
+				}
+			}
 		}
-		var ext string
-		if len(img.Image.MIMEType) == 0 {
-			img.Image.MIMEType = http.DetectContentType(img.Image.ImageBytes)
+		return NewError(kind, provider, err)
+	}
+	return NewError(KindOf(err), provider, err)
+}
+
+func saveGoogleImages(ctx context.Context, providerName string, prompt string, images []*genai.GeneratedImage, watermark string) ([]Image, error) {
+	var data []imageData
+	for _, img := range images {
+		d := imageData{Watermark: watermark}
+		if img.SafetyAttributes != nil {
+			d.Safety.Categories = img.SafetyAttributes.Categories
 		}
-		switch img.Image.MIMEType {
-		case "image/png":
-			ext = ".png"
-		case "image/jpeg":
-			ext = ".jpg"
-		case "image/gif":
-			ext = ".gif"
-		default:
-			if img.Image.MIMEType == "text/plain; charset=utf-8" {
-				log.Printf("Text outout: %s", string(img.Image.ImageBytes))
-			}
-			return nil, fmt.Errorf("unsupported image type: %q", img.Image.MIMEType)
+		if len(img.RAIFilteredReason) > 0 {
+			d.Safety.Filtered = true
+			d.Safety.Reason = img.RAIFilteredReason
 		}
-		filePath := filepath.Join(dir, fmt.Sprintf("%s_%x_%s", nowDateTime, i, ext))
-		if err := os.WriteFile(filePath, img.Image.ImageBytes, 0644); err != nil {
-			return nil, fmt.Errorf("failed to write image: %w", err)
+		if img.Image != nil {
+			d.Bytes = img.Image.ImageBytes
+			d.MIMEType = img.Image.MIMEType
 		}
-
-		filePaths = append(filePaths, filePath)
+		if len(d.Bytes) == 0 && !d.Safety.Filtered {
+			continue
+		}
+		data = append(data, d)
 	}
-
-	return filePaths, nil
+	return saveImages(ctx, providerName, prompt, data)
 }
 
 func (p *GoogleProvider) GetModels() []Model {
@@ -0,0 +1,69 @@
+//go:build !no_google
+
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// googleVisionModels are the Gemini models that describe images.
+var googleVisionModels = []string{"gemini-2.5-flash", "gemini-2.5-pro"}
+
+func (p *GoogleProvider) VisionModels() []string {
+	return googleVisionModels
+}
+
+// DescribeImage sends the image with the instruction to a Gemini model and
+// returns its text answer.
+func (p *GoogleProvider) DescribeImage(ctx context.Context, model string, image []byte, instruction string) (string, error) {
+	if err := p.ensureClient(ctx); err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, generateTimeout(p.GetName(), 2*time.Minute))
+	defer cancel()
+
+	contents := []*genai.Content{genai.NewContentFromParts([]*genai.Part{
+		genai.NewPartFromBytes(image, http.DetectContentType(image)),
+		genai.NewPartFromText(instruction),
+	}, genai.RoleUser)}
+	var resp *genai.GenerateContentResponse
+	err := p.inLocations(ctx, func(client *genai.Client) (err error) {
+		resp, err = client.Models.GenerateContent(ctx, model, contents, nil)
+		return err
+	})
+	if err != nil {
+		return "", googleError(p.GetName(), err)
+	}
+	if fb := resp.PromptFeedback; fb != nil && fb.BlockReason != "" {
+		return "", NewError(ErrorKindContentPolicy, p.GetName(), fmt.Errorf("image blocked: %s", fb.BlockReason))
+	}
+	text := strings.TrimSpace(resp.Text())
+	if text == "" {
+		return "", NewError(ErrorKindUnknown, p.GetName(), fmt.Errorf("%s returned no description", model))
+	}
+	return text, nil
+}
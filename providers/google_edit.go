@@ -0,0 +1,125 @@
+//go:build !no_google
+
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// googleRecontextModel is the Imagen model for product recontextualization.
+const googleRecontextModel = "imagen-product-recontext-preview-06-30"
+
+// imagenEditModes are the edit modes of the Imagen models. The edits are done
+// by the capability and recontext models, which are only available on Vertex
+// AI.
+var imagenEditModes = []string{EditModeInpaint, EditModeRemove, EditModeOutpaint, EditModeBackgroundSwap, EditModeRecontext}
+
+// EditImage edits the input images. Gemini models edit with the prompt only,
+// Imagen models with the mask based edit modes.
+func (p *GoogleProvider) EditImage(ctx context.Context, model string, req EditRequest, settings ModelSettings) ([]Image, error) {
+	if err := p.ensureClient(ctx); err != nil {
+		return nil, err
+	}
+	if isGeminiModel(model) {
+		return p.generateGemini(ctx, model, req.Prompt, req.Images, settings)
+	}
+	if p.aiStudio {
+		return nil, NewError(ErrorKindInvalidSettings, p.GetName(), fmt.Errorf("editing with Imagen requires Vertex AI, use the google provider"))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, generateTimeout(p.GetName(), 5*time.Minute))
+	defer cancel()
+	numberOfImages := int32(GetModelSettingInt(settings, "number_of_images", 1))
+	policy := p.imagenPolicy(settings)
+
+	if req.Mode == EditModeRecontext {
+		source := &genai.RecontextImageSource{Prompt: req.Prompt}
+		for _, img := range req.Images {
+			source.ProductImages = append(source.ProductImages, &genai.ProductImage{ProductImage: googleImage(img)})
+		}
+		var resp *genai.RecontextImageResponse
+		err := p.inLocations(ctx, func(client *genai.Client) (err error) {
+			resp, err = client.Models.RecontextImage(ctx, googleRecontextModel, source, &genai.RecontextImageConfig{
+				NumberOfImages:    &numberOfImages,
+				SafetyFilterLevel: policy.safetyFilterLevel,
+				PersonGeneration:  policy.personGeneration,
+				AddWatermark:      policy.addWatermark,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, googleError(p.GetName(), err)
+		}
+		return saveGoogleImages(ctx, p.GetName(), req.Prompt, resp.GeneratedImages, policy.watermark())
+	}
+
+	var editMode genai.EditMode
+	maskConfig := &genai.MaskReferenceConfig{MaskMode: genai.MaskReferenceModeMaskModeUserProvided}
+	switch req.Mode {
+	case EditModeInpaint:
+		editMode = genai.EditModeInpaintInsertion
+	case EditModeRemove:
+		editMode = genai.EditModeInpaintRemoval
+	case EditModeOutpaint:
+		editMode = genai.EditModeOutpaint
+	case EditModeBackgroundSwap:
+		editMode = genai.EditModeBgswap
+		if len(req.Mask) == 0 {
+			maskConfig.MaskMode = genai.MaskReferenceModeMaskModeBackground
+		}
+	default:
+		return nil, NewError(ErrorKindInvalidSettings, p.GetName(), fmt.Errorf("%s doesn't support edit mode %q", model, req.Mode))
+	}
+
+	referenceImages := []genai.ReferenceImage{genai.NewRawReferenceImage(googleImage(req.Images[0]), 1)}
+	var mask *genai.Image
+	if len(req.Mask) > 0 {
+		mask = googleImage(req.Mask)
+	}
+	referenceImages = append(referenceImages, genai.NewMaskReferenceImage(mask, 2, maskConfig))
+
+	var resp *genai.EditImageResponse
+	err := p.inLocations(ctx, func(client *genai.Client) (err error) {
+		resp, err = client.Models.EditImage(ctx, googleEditModel, req.Prompt, referenceImages, &genai.EditImageConfig{
+			NumberOfImages:          numberOfImages,
+			SafetyFilterLevel:       policy.safetyFilterLevel,
+			PersonGeneration:        policy.personGeneration,
+			AddWatermark:            policy.addWatermark,
+			IncludeRAIReason:        true,
+			IncludeSafetyAttributes: true,
+			EditMode:                editMode,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, googleError(p.GetName(), err)
+	}
+	return saveGoogleImages(ctx, p.GetName(), req.Prompt, resp.GeneratedImages, policy.watermark())
+}
+
+func googleImage(b []byte) *genai.Image {
+	return &genai.Image{ImageBytes: b, MIMEType: http.DetectContentType(b)}
+}
@@ -0,0 +1,131 @@
+//go:build !no_google
+
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+var geminiSettings = ModelSettings{
+	{DisplayName: "Number of Images", Name: "number_of_images", Type: IntSetting{Min: 1, Max: 4}, DefaultValue: "1"},
+	{DisplayName: "Aspect Ratio", Name: "aspect_ratio", Type: EnumSetting{Options: []string{"1:1", "2:3", "3:2", "3:4", "4:3", "9:16", "16:9", "21:9"}}, DefaultValue: "1:1"},
+}
+
+func isGeminiModel(model string) bool {
+	return strings.HasPrefix(model, "gemini-")
+}
+
+// generateGemini generates images with GenerateContent. Gemini returns one
+// image per request, so a request is sent for every image.
+func (p *GoogleProvider) generateGemini(ctx context.Context, model string, prompt string, inputImages [][]byte, settings ModelSettings) ([]Image, error) {
+	ctx, cancel := context.WithTimeout(ctx, generateTimeout(p.GetName(), 5*time.Minute))
+	defer cancel()
+
+	parts := []*genai.Part{genai.NewPartFromText(prompt)}
+	for _, img := range inputImages {
+		parts = append(parts, genai.NewPartFromBytes(img, http.DetectContentType(img)))
+	}
+	contents := []*genai.Content{genai.NewContentFromParts(parts, genai.RoleUser)}
+	config := &genai.GenerateContentConfig{
+		ResponseModalities: []string{string(genai.ModalityImage), string(genai.ModalityText)},
+		ImageConfig: &genai.ImageConfig{
+			AspectRatio: GetModelSettingString(settings, "aspect_ratio", "1:1"),
+		},
+	}
+
+	var data []imageData
+	for range GetModelSettingInt(settings, "number_of_images", 1) {
+		var resp *genai.GenerateContentResponse
+		err := p.inLocations(ctx, func(client *genai.Client) (err error) {
+			resp, err = client.Models.GenerateContent(ctx, model, contents, config)
+			return err
+		})
+		if err != nil {
+			return nil, googleError(p.GetName(), err)
+		}
+		d, err := geminiImage(resp)
+		if err != nil {
+			return nil, NewError(ErrorKindUnknown, p.GetName(), err)
+		}
+		d.ResponseID = resp.ResponseID
+		d.Watermark = WatermarkSynthID
+		data = append(data, d)
+	}
+	return saveImages(ctx, p.GetName(), prompt, data)
+}
+
+// geminiImage extracts the image of a GenerateContent response or why it was
+// blocked.
+func geminiImage(resp *genai.GenerateContentResponse) (imageData, error) {
+	var d imageData
+	if fb := resp.PromptFeedback; fb != nil && fb.BlockReason != "" {
+		d.Safety.Filtered = true
+		d.Safety.Reason = string(fb.BlockReason)
+		if fb.BlockReasonMessage != "" {
+			d.Safety.Reason += ": " + fb.BlockReasonMessage
+		}
+		d.Safety.Categories = blockedCategories(fb.SafetyRatings)
+		return d, nil
+	}
+	if len(resp.Candidates) == 0 {
+		return d, errors.New("response contains no candidates")
+	}
+	candidate := resp.Candidates[0]
+	if candidate.Content != nil {
+		for _, part := range candidate.Content.Parts {
+			if part.InlineData != nil && len(part.InlineData.Data) > 0 {
+				d.Bytes = part.InlineData.Data
+				d.MIMEType = part.InlineData.MIMEType
+				return d, nil
+			}
+		}
+	}
+	switch candidate.FinishReason {
+	case genai.FinishReasonSafety, genai.FinishReasonImageSafety, genai.FinishReasonProhibitedContent,
+		genai.FinishReasonBlocklist, genai.FinishReasonSPII:
+		d.Safety.Filtered = true
+		d.Safety.Reason = string(candidate.FinishReason)
+		d.Safety.Categories = blockedCategories(candidate.SafetyRatings)
+		return d, nil
+	}
+	// the model answered with text only, e.g. to ask for clarification
+	if text := resp.Text(); text != "" {
+		return d, fmt.Errorf("model returned no image: %s", text)
+	}
+	return d, fmt.Errorf("model returned no image (finish reason %s)", candidate.FinishReason)
+}
+
+func blockedCategories(ratings []*genai.SafetyRating) []string {
+	var categories []string
+	for _, r := range ratings {
+		if r.Blocked {
+			categories = append(categories, string(r.Category))
+		}
+	}
+	return categories
+}
@@ -0,0 +1,74 @@
+//go:build !no_google
+
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"google.golang.org/genai"
+)
+
+// inLocations sends a Vertex AI request to each of the configured locations
+// in turn until it doesn't fail with a quota or outage error, which are
+// common in a single region for new models. Without configured locations
+// the request is sent to the location of the login.
+func (p *GoogleProvider) inLocations(ctx context.Context, request func(client *genai.Client) error) error {
+	locations := getOptions(p.GetName()).Locations
+	if p.aiStudio || len(locations) == 0 {
+		return request(p.client)
+	}
+	var err error
+	for i, location := range locations {
+		client, clientErr := p.locationClient(ctx, location)
+		if clientErr != nil {
+			return clientErr
+		}
+		err = request(client)
+		if err == nil || i == len(locations)-1 || ctx.Err() != nil {
+			return err
+		}
+		if kind := KindOf(googleError(p.GetName(), err)); kind != ErrorKindQuota && kind != ErrorKindOutage {
+			return err
+		}
+		log.Printf("warning: %s is unavailable in %s, trying %s: %v", p.displayName(), location, locations[i+1], err)
+	}
+	return err
+}
+
+// locationClient returns the client for the Vertex AI location, created with
+// the credentials of the login on first use.
+func (p *GoogleProvider) locationClient(ctx context.Context, location string) (*genai.Client, error) {
+	p.clientsMu.Lock()
+	defer p.clientsMu.Unlock()
+	if client, ok := p.clients[location]; ok {
+		return client, nil
+	}
+	config := p.clientConfig
+	config.Location = location
+	client, err := genai.NewClient(ctx, &config)
+	if err != nil {
+		return nil, NewError(ErrorKindInvalidSettings, p.GetName(), fmt.Errorf("failed to create GenAI client for %s: %w", location, err))
+	}
+	p.clients[location] = client
+	return client, nil
+}
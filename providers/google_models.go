@@ -0,0 +1,91 @@
+//go:build !no_google
+
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"context"
+	"path"
+	"strings"
+)
+
+// ListModels lists the image and video models of the GenAI API. Models are
+// recognized by their name, unknown model families are skipped.
+func (p *GoogleProvider) ListModels(ctx context.Context) ([]Model, error) {
+	if err := p.ensureClient(ctx); err != nil {
+		return nil, err
+	}
+	var models []Model
+	for listed, err := range p.client.Models.All(ctx) {
+		if err != nil {
+			return nil, googleError(p.GetName(), err)
+		}
+		name := path.Base(listed.Name)
+		m, ok := googleModelTemplate(name)
+		if !ok {
+			continue
+		}
+		m.Name = name
+		m.DisplayName = listed.DisplayName
+		if m.DisplayName == "" {
+			m.DisplayName = name
+		}
+		models = append(models, m)
+	}
+	return models, nil
+}
+
+// googleModelTemplate returns the settings, capabilities and price of the
+// model family of name. The price is the one of the built-in model of the
+// family, so budgets and usage count discovered models too.
+func googleModelTemplate(name string) (Model, bool) {
+	switch {
+	case strings.HasPrefix(name, "imagen-") && strings.Contains(name, "generate"):
+		m := Model{Settings: googleSettings.Clone(), Capabilities: Capabilities{MaxPromptTokens: 480, MaxImages: 4, MaxInputImages: 3, EditModes: imagenEditModes}, PricePerImage: googlePrice("imagen-4.0-generate-001")}
+		if strings.Contains(name, "fast") {
+			m.Settings = googleFastSettings.Clone()
+			m.PricePerImage = googlePrice("imagen-4.0-fast-generate-001")
+		} else if strings.Contains(name, "ultra") {
+			m.Capabilities.MaxImages = 1
+			m.PricePerImage = googlePrice("imagen-4.0-ultra-generate-001")
+		}
+		return m, true
+	case isGeminiModel(name) && strings.Contains(name, "image"):
+		return Model{Settings: geminiSettings.Clone(), Capabilities: Capabilities{MaxPromptTokens: 32768, MaxImages: 4, MaxInputImages: 3, EditModes: []string{EditModeDefault}}, PricePerImage: googlePrice("gemini-2.5-flash-image")}, true
+	case strings.HasPrefix(name, "veo-"):
+		m := Model{Settings: veoSettings.Clone(), Capabilities: Capabilities{MaxPromptTokens: 1024, MaxImages: 4}, Media: MediaVideo, PricePerImage: googlePrice("veo-3.0-generate-001")}
+		if strings.Contains(name, "fast") {
+			m.PricePerImage = googlePrice("veo-3.0-fast-generate-001")
+		}
+		return m, true
+	default:
+		return Model{}, false
+	}
+}
+
+// googlePrice returns the price of a built-in model.
+func googlePrice(name string) float64 {
+	for _, m := range GoogleModels {
+		if m.Name == name {
+			return m.PricePerImage
+		}
+	}
+	return 0
+}
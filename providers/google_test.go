@@ -0,0 +1,74 @@
+//go:build !no_google
+
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/bloodmagesoftware/climage/providers/providertest"
+)
+
+func TestGoogleConformance(t *testing.T) {
+	p, err := providers.GetProviderByName("google")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a service account with a throwaway key, its tokens are answered by the
+	// fake transport of the suite
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serviceAccountKey, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"client_email":   "providertest@test.iam.gserviceaccount.com",
+		"private_key_id": "providertest",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		"token_uri":      "https://oauth2.googleapis.com/token",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	providertest.Run(t, p, providertest.Options{
+		Credentials: map[string]string{
+			"service_account_key": base64.StdEncoding.EncodeToString(serviceAccountKey),
+			"project_id":          "test",
+			"location":            "us-central1",
+		},
+	})
+}
+
+func TestGoogleAIConformance(t *testing.T) {
+	p, err := providers.GetProviderByName("google-ai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	providertest.Run(t, p, providertest.Options{
+		Credentials: map[string]string{"api_key": "test"},
+	})
+}
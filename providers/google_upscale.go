@@ -0,0 +1,70 @@
+//go:build !no_google
+
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// googleUpscaleModel is the Imagen model that supports the upscale mode.
+const googleUpscaleModel = "imagen-3.0-generate-002"
+
+func (p *GoogleProvider) UpscaleFactors() []int {
+	return []int{2, 4}
+}
+
+func (p *GoogleProvider) UpscalePrice() float64 {
+	return 0.003
+}
+
+// UpscaleImage upscales a generated image, e.g. a 1K image to 2K or 4K. The
+// upscale mode is only available on Vertex AI.
+func (p *GoogleProvider) UpscaleImage(ctx context.Context, image []byte, factor int) ([]Image, error) {
+	if p.aiStudio {
+		return nil, NewError(ErrorKindInvalidSettings, p.GetName(), fmt.Errorf("upscaling requires Vertex AI, use the google provider"))
+	}
+	if !slices.Contains(p.UpscaleFactors(), factor) {
+		return nil, NewError(ErrorKindInvalidSettings, p.GetName(), fmt.Errorf("unsupported upscale factor %d, supported are 2 and 4", factor))
+	}
+	if err := p.ensureClient(ctx); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, generateTimeout(p.GetName(), 5*time.Minute))
+	defer cancel()
+	var resp *genai.UpscaleImageResponse
+	err := p.inLocations(ctx, func(client *genai.Client) (err error) {
+		resp, err = client.Models.UpscaleImage(ctx, googleUpscaleModel, googleImage(image), "x"+strconv.Itoa(factor), &genai.UpscaleImageConfig{
+			IncludeRAIReason: true,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, googleError(p.GetName(), err)
+	}
+	// whether the upscaled image carries a watermark isn't documented
+	return saveGoogleImages(ctx, p.GetName(), fmt.Sprintf("upscaled x%d", factor), resp.GeneratedImages, "")
+}
@@ -0,0 +1,116 @@
+//go:build !no_google
+
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// Veo 3 generates 1080p only in landscape.
+var veoSettings = ModelSettings{
+	{DisplayName: "Number of Videos", Name: "number_of_videos", Type: IntSetting{Min: 1, Max: 4}, DefaultValue: "1"},
+	{DisplayName: "Aspect Ratio", Name: "aspect_ratio", Type: EnumSetting{Options: []string{"16:9", "9:16"}}, DefaultValue: "16:9"},
+	{DisplayName: "Resolution", Name: "resolution", Type: EnumSetting{Options: []string{"720p", "1080p"}}, DefaultValue: "720p", When: &SettingCondition{Setting: "aspect_ratio", Values: []string{"16:9"}}},
+	{DisplayName: "Duration in Seconds", Name: "duration_seconds", Type: EnumSetting{Options: []string{"4", "6", "8"}}, DefaultValue: "8"},
+	{DisplayName: "Generate Audio", Name: "generate_audio", Type: BoolSetting{}, DefaultValue: "true"},
+}
+
+// veoPollInterval is how often a running video operation is checked.
+const veoPollInterval = 10 * time.Second
+
+// GenerateVideo generates videos with Veo. Video generation is a long running
+// operation that is polled until it is done.
+func (p *GoogleProvider) GenerateVideo(ctx context.Context, model string, prompt string, settings ModelSettings) ([]Image, error) {
+	if err := p.ensureClient(ctx); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, generateTimeout(p.GetName(), 10*time.Minute))
+	defer cancel()
+
+	duration := int32(GetModelSettingInt(settings, "duration_seconds", 8))
+	generateAudio := GetModelSettingBool(settings, "generate_audio", true)
+	// the operation is polled in the location it was started in
+	var op *genai.GenerateVideosOperation
+	var client *genai.Client
+	err := p.inLocations(ctx, func(c *genai.Client) (err error) {
+		client = c
+		op, err = client.Models.GenerateVideos(ctx, model, prompt, nil, &genai.GenerateVideosConfig{
+			NumberOfVideos:  int32(GetModelSettingInt(settings, "number_of_videos", 1)),
+			AspectRatio:     GetModelSettingString(settings, "aspect_ratio", "16:9"),
+			Resolution:      GetModelSettingString(settings, "resolution", "720p"),
+			DurationSeconds: &duration,
+			GenerateAudio:   &generateAudio,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, googleError(p.GetName(), err)
+	}
+	for !op.Done {
+		select {
+		case <-ctx.Done():
+			return nil, NewError(KindOf(ctx.Err()), p.GetName(), ctx.Err())
+		case <-time.After(veoPollInterval):
+		}
+		op, err = client.Operations.GetVideosOperation(ctx, op, nil)
+		if err != nil {
+			return nil, googleError(p.GetName(), err)
+		}
+	}
+	if op.Error != nil {
+		return nil, NewError(ErrorKindUnknown, p.GetName(), fmt.Errorf("video generation failed: %v", op.Error["message"]))
+	}
+	if op.Response == nil {
+		return nil, NewError(ErrorKindUnknown, p.GetName(), fmt.Errorf("video generation returned no response"))
+	}
+
+	var data []imageData
+	for _, v := range op.Response.GeneratedVideos {
+		if v.Video == nil {
+			continue
+		}
+		if len(v.Video.VideoBytes) == 0 && v.Video.URI != "" {
+			// the Gemini API only returns a file URI
+			b, err := client.Files.Download(ctx, genai.NewDownloadURIFromGeneratedVideo(v), nil)
+			if err != nil {
+				return nil, googleError(p.GetName(), err)
+			}
+			v.Video.VideoBytes = b
+		}
+		mimeType := v.Video.MIMEType
+		if mimeType == "" {
+			mimeType = "video/mp4"
+		}
+		data = append(data, imageData{Bytes: v.Video.VideoBytes, MIMEType: mimeType, ResponseID: op.Name, Watermark: WatermarkSynthID})
+	}
+	for i := range op.Response.RAIMediaFilteredCount {
+		d := imageData{Safety: SafetyResult{Filtered: true}, ResponseID: op.Name}
+		if int(i) < len(op.Response.RAIMediaFilteredReasons) {
+			d.Safety.Reason = op.Response.RAIMediaFilteredReasons[i]
+		}
+		data = append(data, d)
+	}
+	return saveImages(ctx, p.GetName(), prompt, data)
+}
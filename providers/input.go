@@ -0,0 +1,117 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxInputImageSize is the size limit of downloaded input images.
+	MaxInputImageSize = 20 << 20
+	// inputCacheTTL is how long downloaded input images are reused.
+	inputCacheTTL = 24 * time.Hour
+)
+
+// IsURL reports if an input image path is an http or https URL.
+func IsURL(path string) bool {
+	return strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "http://")
+}
+
+// ReadInputImage reads an input image from a file or, for http and https URLs,
+// downloads it. Downloads must be images of at most MaxInputImageSize and are
+// cached for a day, so a reference image isn't downloaded for every request.
+func ReadInputImage(ctx context.Context, path string) ([]byte, error) {
+	if !IsURL(path) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+	cachePath, err := inputCachePath(path)
+	if err != nil {
+		return nil, err
+	}
+	if fi, err := os.Stat(cachePath); err == nil && time.Since(fi.ModTime()) < inputCacheTTL {
+		if b, err := os.ReadFile(cachePath); err == nil {
+			return b, nil
+		}
+	}
+	b, err := downloadInputImage(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0700); err == nil {
+		// the cache is an optimization, the image is used anyway
+		_ = WriteFileAtomic(cachePath, b, 0600)
+	}
+	return b, nil
+}
+
+func downloadInputImage(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download request: %w", err)
+	}
+	client := &http.Client{Transport: debugTransport("download", cassetteTransport("download", http.DefaultTransport))}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	if resp.ContentLength > MaxInputImageSize {
+		return nil, fmt.Errorf("%s is larger than %d MB", url, MaxInputImageSize>>20)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, MaxInputImageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	if len(b) > MaxInputImageSize {
+		return nil, fmt.Errorf("%s is larger than %d MB", url, MaxInputImageSize>>20)
+	}
+	// the content is checked as servers often send a generic content type
+	if contentType := http.DetectContentType(b); !strings.HasPrefix(contentType, "image/") {
+		return nil, fmt.Errorf("%s is not an image but %s", url, contentType)
+	}
+	return b, nil
+}
+
+func inputCachePath(url string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user cache dir: %w", err)
+	}
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(cacheDir, "climage", "inputs", hex.EncodeToString(sum[:16])), nil
+}
@@ -0,0 +1,171 @@
+//go:build mock
+
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"time"
+)
+
+var mockSettings = ModelSettings{
+	{DisplayName: "Number of Images", Name: "number_of_images", Type: IntSetting{Min: 1, Max: 4}, DefaultValue: "1"},
+	{DisplayName: "Aspect Ratio", Name: "aspect_ratio", Type: EnumSetting{Options: []string{"1:1", "16:9", "4:3", "9:16", "3:4"}}, DefaultValue: "1:1"},
+}
+
+var MockModels = []Model{
+	{Name: "placeholder", DisplayName: "Mock Placeholder", Settings: mockSettings, Capabilities: Capabilities{MaxImages: 4}},
+}
+
+// MockProvider returns deterministic placeholder images instantly without
+// credentials or network access. It is only built with the mock build tag and
+// meant for dry runs, demos and end to end tests.
+type MockProvider struct{}
+
+func init() {
+	Providers = append(Providers, &MockProvider{})
+}
+
+func (p *MockProvider) GetName() string {
+	return "mock"
+}
+
+func (p *MockProvider) GetLoginFields() []LoginField { return nil }
+
+func (p *MockProvider) SaveCredentials(credentials map[string]string) error { return nil }
+
+func (p *MockProvider) LoadCredentials() (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+func (p *MockProvider) DeleteCredentials() error { return nil }
+
+func (p *MockProvider) Login(ctx context.Context, credentials map[string]string) error { return nil }
+
+func (p *MockProvider) Close() error { return nil }
+
+// GenerateImage returns gradients whose colors are derived from the prompt,
+// so the same prompt always produces the same images.
+func (p *MockProvider) GenerateImage(ctx context.Context, model string, prompt string, settings ModelSettings) ([]Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, NewError(KindOf(err), p.GetName(), err)
+	}
+	width, height := mockSize(GetModelSettingString(settings, "aspect_ratio", "1:1"))
+	var data []imageData
+	for i := range GetModelSettingInt(settings, "number_of_images", 1) {
+		b, err := mockImage(fmt.Sprintf("%s\x00%d", prompt, i), width, height)
+		if err != nil {
+			return nil, NewError(ErrorKindUnknown, p.GetName(), err)
+		}
+		data = append(data, imageData{Bytes: b, MIMEType: "image/png"})
+	}
+	return saveImages(ctx, p.GetName(), prompt, data)
+}
+
+func mockSize(aspectRatio string) (int, int) {
+	switch aspectRatio {
+	case "16:9":
+		return 512, 288
+	case "4:3":
+		return 512, 384
+	case "9:16":
+		return 288, 512
+	case "3:4":
+		return 384, 512
+	default:
+		return 512, 512
+	}
+}
+
+func mockImage(seed string, width int, height int) ([]byte, error) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(strings.TrimSpace(seed)))
+	sum := h.Sum64()
+	from := color.RGBA{uint8(sum), uint8(sum >> 8), uint8(sum >> 16), 0xff}
+	to := color.RGBA{uint8(sum >> 24), uint8(sum >> 32), uint8(sum >> 40), 0xff}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			t := (x + y) * 255 / (width + height - 2)
+			img.Set(x, y, color.RGBA{
+				R: mix(from.R, to.R, t),
+				G: mix(from.G, to.G, t),
+				B: mix(from.B, to.B, t),
+				A: 0xff,
+			})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode placeholder: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func mix(a uint8, b uint8, t int) uint8 {
+	return uint8((int(a)*(255-t) + int(b)*t) / 255)
+}
+
+func (p *MockProvider) GetModels() []Model {
+	return MockModels
+}
+
+func (p *MockProvider) GetModelSettings(model string) []ModelSetting { return nil }
+
+func (p *MockProvider) GetSettings() any {
+	return nil
+}
+
+func (p *MockProvider) VisionModels() []string {
+	return []string{"placeholder"}
+}
+
+// DescribeImage describes the size of the image only.
+func (p *MockProvider) DescribeImage(ctx context.Context, model string, img []byte, instruction string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", NewError(KindOf(err), p.GetName(), err)
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(img))
+	if err != nil {
+		return "", NewError(ErrorKindInvalidSettings, p.GetName(), fmt.Errorf("failed to decode image: %w", err))
+	}
+	return fmt.Sprintf("A placeholder %s image of %dx%d pixels.", format, cfg.Width, cfg.Height), nil
+}
+
+// Quota reports fixed credits, so the quota display can be tried without an
+// account.
+func (p *MockProvider) Quota(ctx context.Context) ([]Quota, error) {
+	now := time.Now()
+	return []Quota{{
+		Name:      "credits",
+		Remaining: 42,
+		Limit:     500,
+		Unit:      "credits",
+		Reset:     time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, now.Location()),
+	}}, nil
+}
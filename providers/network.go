@@ -0,0 +1,135 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+)
+
+// NetworkOptions configure the HTTP connections of all providers, uploads and
+// webhooks.
+type NetworkOptions struct {
+	// Proxy is the proxy URL used for all requests, e.g.
+	// "socks5://localhost:1080" or "http://proxy:3128". HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY are used if empty.
+	Proxy string `json:"proxy,omitempty"`
+	// CABundle is a PEM file with additional trusted root certificates, e.g.
+	// of a corporate proxy that intercepts TLS.
+	CABundle string `json:"ca_bundle,omitempty"`
+}
+
+var (
+	networkMu      sync.Mutex
+	networkApplied *NetworkOptions
+)
+
+// ConfigureNetwork applies the options to http.DefaultTransport, which all
+// HTTP clients of climage are based on. Options that are already applied are
+// not applied again, so connections in use are not affected.
+func ConfigureNetwork(opts NetworkOptions) error {
+	networkMu.Lock()
+	defer networkMu.Unlock()
+	if networkApplied != nil && *networkApplied == opts {
+		return nil
+	}
+	t, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("unsupported default HTTP transport %T", http.DefaultTransport)
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if opts.Proxy != "" {
+		u, err := parseProxy(opts.Proxy)
+		if err != nil {
+			return err
+		}
+		proxy = http.ProxyURL(u)
+	}
+
+	var tlsConfig *tls.Config
+	if opts.CABundle != "" {
+		pem, err := os.ReadFile(opts.CABundle)
+		if err != nil {
+			return fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in CA bundle %s", opts.CABundle)
+		}
+		tlsConfig = &tls.Config{RootCAs: pool}
+	}
+
+	t.Proxy = proxy
+	t.TLSClientConfig = tlsConfig
+	t.CloseIdleConnections()
+	networkApplied = &opts
+	return nil
+}
+
+// ProxyDirect as proxy of a provider sends its requests without a proxy.
+const ProxyDirect = "direct"
+
+func parseProxy(proxy string) (*url.URL, error) {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy %q: %w", proxy, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid proxy %q: expected an http, https or socks5 URL", proxy)
+	}
+	return u, nil
+}
+
+// baseTransport returns the transport the requests of a provider are sent
+// with, http.DefaultTransport or a copy of it with the provider's proxy.
+func baseTransport(providerName string) http.RoundTripper {
+	proxy := getOptions(providerName).Proxy
+	if proxy == "" {
+		return http.DefaultTransport
+	}
+	networkMu.Lock()
+	defer networkMu.Unlock()
+	t, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return http.DefaultTransport
+	}
+	// the copy keeps the trusted certificates of the network options
+	t = t.Clone()
+	if proxy == ProxyDirect {
+		t.Proxy = nil
+	} else if u, err := parseProxy(proxy); err != nil {
+		// Configure rejects invalid proxies, never fall back to a direct
+		// connection
+		t.Proxy = func(*http.Request) (*url.URL, error) { return nil, err }
+	} else {
+		t.Proxy = http.ProxyURL(u)
+	}
+	return t
+}
@@ -0,0 +1,119 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Options are the user configurable, provider independent settings of a
+// provider. They are stored in the provider section of the config file.
+type Options struct {
+	Retry    RetryOptions   `json:"retry,omitzero"`
+	Limits   LimitOptions   `json:"limits,omitzero"`
+	Timeouts TimeoutOptions `json:"timeouts,omitzero"`
+	// Proxy is the proxy URL for the requests of this provider instead of
+	// the proxy of the network options, ProxyDirect sends them without one.
+	Proxy string `json:"proxy,omitempty"`
+	// Locations are the Vertex AI regions the google provider sends
+	// generations to, the next one is tried on quota and outage errors. The
+	// location of the login is used if empty.
+	Locations []string `json:"locations,omitempty"`
+}
+
+type RetryOptions struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	MaxAttempts    int      `json:"max_attempts,omitempty"`
+	InitialBackoff Duration `json:"initial_backoff,omitzero"`
+	MaxBackoff     Duration `json:"max_backoff,omitzero"`
+}
+
+type LimitOptions struct {
+	// MaxInFlight is the maximum number of concurrent generations.
+	MaxInFlight int `json:"max_in_flight,omitempty"`
+	// RequestsPerMinute limits how many generations are started per minute.
+	// Zero means unlimited.
+	RequestsPerMinute int `json:"requests_per_minute,omitempty"`
+}
+
+type TimeoutOptions struct {
+	Login    Duration `json:"login,omitzero"`
+	Generate Duration `json:"generate,omitzero"`
+}
+
+// Duration is a time.Duration that is encoded as a string like "1m30s" in JSON.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"30s\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+var (
+	optionsMu sync.RWMutex
+	options   = make(map[string]Options)
+)
+
+// Configure sets the options of the provider with the given name.
+func Configure(name string, opts Options) error {
+	if opts.Proxy != "" && opts.Proxy != ProxyDirect {
+		if _, err := parseProxy(opts.Proxy); err != nil {
+			return fmt.Errorf("provider %s: %w", name, err)
+		}
+	}
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+	options[name] = opts
+	return nil
+}
+
+func getOptions(name string) Options {
+	optionsMu.RLock()
+	defer optionsMu.RUnlock()
+	return options[name]
+}
+
+func loginTimeout(name string, defaultValue time.Duration) time.Duration {
+	if d := getOptions(name).Timeouts.Login; d > 0 {
+		return time.Duration(d)
+	}
+	return defaultValue
+}
+
+func generateTimeout(name string, defaultValue time.Duration) time.Duration {
+	if d := getOptions(name).Timeouts.Generate; d > 0 {
+		return time.Duration(d)
+	}
+	return defaultValue
+}
@@ -0,0 +1,298 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxParallelDownloads bounds how many result images are downloaded and
+// written at the same time.
+const maxParallelDownloads = 4
+
+// imageData is a generated image or video. Providers either return the bytes
+// inline or a URL it has to be downloaded from.
+type imageData struct {
+	Bytes      []byte
+	MIMEType   string
+	URL        string
+	Safety     SafetyResult
+	Seed       *int64
+	ResponseID string
+	Watermark  string
+}
+
+// saveImages downloads (if needed) and writes all images to the output
+// directory using a bounded worker pool. Downloads use the network options of
+// the provider. The files are named after the prompt.
+// The returned images keep the order of the input. Filtered images are
+// returned without a path.
+func saveImages(ctx context.Context, providerName string, prompt string, images []imageData) ([]Image, error) {
+	dir, err := OutDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get out dir: %w", err)
+	}
+	now := time.Now()
+	dir = filepath.Join(dir, layoutDir(now))
+	_ = os.MkdirAll(dir, 0755)
+	slug := promptSlug(prompt)
+
+	filePaths := make([]string, len(images))
+	media := make([]MediaType, len(images))
+	errs := make([]error, len(images))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for range min(maxParallelDownloads, len(images)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				filePaths[i], media[i], errs[i] = saveImage(ctx, providerName, dir, slug, images[i])
+			}
+		}()
+	}
+	for i := range images {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		// don't leave a partial generation behind
+		for _, filePath := range filePaths {
+			if filePath != "" {
+				_ = os.Remove(filePath)
+			}
+		}
+		return nil, err
+	}
+	saved := make([]Image, len(images))
+	for i, img := range images {
+		saved[i] = Image{Path: filePaths[i], Media: media[i], Safety: img.Safety, Seed: img.Seed, ResponseID: img.ResponseID, Watermark: img.Watermark}
+	}
+	return saved, nil
+}
+
+func saveImage(ctx context.Context, providerName string, dir string, slug string, img imageData) (string, MediaType, error) {
+	if len(img.Bytes) == 0 && img.URL != "" {
+		b, mimeType, err := downloadImage(ctx, providerName, img.URL)
+		if err != nil {
+			return "", "", err
+		}
+		img.Bytes = b
+		if img.MIMEType == "" {
+			img.MIMEType = mimeType
+		}
+	}
+	if len(img.Bytes) == 0 {
+		return "", "", nil
+	}
+	if len(img.MIMEType) == 0 {
+		img.MIMEType = http.DetectContentType(img.Bytes)
+	}
+	var ext string
+	media := MediaImage
+	switch img.MIMEType {
+	case "image/png":
+		ext = ".png"
+	case "image/jpeg":
+		ext = ".jpg"
+	case "image/gif":
+		ext = ".gif"
+	case "video/mp4":
+		ext = ".mp4"
+		media = MediaVideo
+	case "video/webm":
+		ext = ".webm"
+		media = MediaVideo
+	default:
+		if img.MIMEType == "text/plain; charset=utf-8" {
+			log.Printf("Text outout: %s", string(img.Bytes))
+		}
+		return "", "", fmt.Errorf("unsupported image type: %q", img.MIMEType)
+	}
+	if err := ctx.Err(); err != nil {
+		return "", "", err
+	}
+	// the hash of the content tells images of the same prompt apart, a
+	// counter is added instead of overwriting an existing file
+	sum := sha256.Sum256(img.Bytes)
+	name := slug + "_" + hex.EncodeToString(sum[:3])
+	for n := 0; ; n++ {
+		filePath := filepath.Join(dir, name+ext)
+		if n > 0 {
+			filePath = filepath.Join(dir, fmt.Sprintf("%s_%d%s", name, n, ext))
+		}
+		err := createFileAtomic(filePath, img.Bytes, 0644)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("failed to write image: %w", err)
+		}
+		return filePath, media, nil
+	}
+}
+
+// maxSlugLength is the maximum length of the prompt part of file names.
+const maxSlugLength = 48
+
+// promptSlug returns a file name safe slug of the prompt, e.g. "red-fox-in-snow"
+// for "A red fox in snow". Only letters and digits are kept, a leading article
+// is dropped and it is cut at a word boundary.
+func promptSlug(prompt string) string {
+	words := strings.FieldsFunc(strings.ToLower(prompt), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > 1 && slices.Contains([]string{"a", "an", "the"}, words[0]) {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return "image"
+	}
+	slug := truncateUTF8(words[0], maxSlugLength)
+	for _, word := range words[1:] {
+		if len(slug)+1+len(word) > maxSlugLength {
+			break
+		}
+		slug += "-" + word
+	}
+	return slug
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a character.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// WriteFileAtomic writes to a temporary file in the destination directory and
+// renames it on success, so readers never see a truncated file.
+func WriteFileAtomic(filePath string, data []byte, perm os.FileMode) error {
+	tmpPath, err := writeTemp(filePath, data, perm)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// writeTemp writes data to a temporary file next to filePath and returns its
+// path.
+func writeTemp(filePath string, data []byte, perm os.FileMode) (string, error) {
+	f, err := os.CreateTemp(filepath.Dir(filePath), "."+filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	tmpPath := f.Name()
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmpPath)
+		return "", err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmpPath)
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", err
+	}
+	return tmpPath, nil
+}
+
+// createFileAtomic is like WriteFileAtomic but fails with an error matching
+// os.ErrExist instead of replacing an existing file. The complete file is
+// hard linked to its name, which fails if the name exists. File systems
+// without hard links, e.g. FAT or Android's shared storage, reserve the name
+// with an empty file instead, which is removed again if writing fails.
+func createFileAtomic(filePath string, data []byte, perm os.FileMode) error {
+	tmpPath, err := writeTemp(filePath, data, perm)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+	err = os.Link(tmpPath, filePath)
+	if err == nil || errors.Is(err, os.ErrExist) {
+		return err
+	}
+
+	f, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(filePath)
+		return err
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		_ = os.Remove(filePath)
+		return err
+	}
+	return nil
+}
+
+func downloadImage(ctx context.Context, providerName string, url string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create download request: %w", err)
+	}
+	client := &http.Client{Transport: debugTransport("download", cassetteTransport("download", baseTransport(providerName)))}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download image: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to download image: %s", resp.Status)
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read image: %w", err)
+	}
+	return b, resp.Header.Get("Content-Type"), nil
+}
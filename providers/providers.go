@@ -28,146 +28,172 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bloodmagesoftware/climage/downloads"
 	"github.com/charmbracelet/huh"
 )
 
+// MediaType is the kind of media a model generates.
+type MediaType string
+
+const (
+	MediaImage MediaType = "image"
+	MediaVideo MediaType = "video"
+)
+
 type Model struct {
-	Name        string
-	DisplayName string
-	Settings    ModelSettings
+	Name         string
+	DisplayName  string
+	Settings     ModelSettings
+	Capabilities Capabilities
+	// Media is what the model generates, images if empty. Video models are
+	// generated with VideoProvider.
+	Media MediaType
+	// PricePerImage is the estimated price of one image or video in USD,
+	// used for the usage statistics. Zero means unknown.
+	PricePerImage float64
 }
 
 type ModelSettings []*ModelSetting
 
-func IsOfType(v string, t string) bool {
-	switch t {
-	case "int":
-		_, err := strconv.Atoi(v)
-		return err == nil
-	case "float":
-		_, err := strconv.ParseFloat(v, 64)
-		return err == nil
-	case "string":
+// Clone returns a deep copy so the values can no longer be changed through
+// the settings form.
+func (ms ModelSettings) Clone() ModelSettings {
+	clone := make(ModelSettings, len(ms))
+	for i, m := range ms {
+		c := *m
+		clone[i] = &c
+	}
+	return clone
+}
+
+// Applies reports whether the condition of s is met, settings without a
+// condition always apply.
+func (ms ModelSettings) Applies(s *ModelSetting) bool {
+	if s.When == nil {
 		return true
-	case "boolean":
-		return v == "true" || v == "false"
-	default:
-		if union, ok := strings.CutPrefix(t, "enum:"); ok {
-			unionFields := strings.Split(union, "|")
-			if len(unionFields) == 0 {
-				return false
-			} else if len(unionFields) <= 8 {
-				// select
-				return slices.Contains(unionFields, v)
-			} else {
-				// text input
-				return slices.Contains(unionFields, v)
-			}
-		} else {
-			log.Printf("unknown model setting type: %q", t)
-			return false
+	}
+	for _, other := range ms {
+		if other.Name == s.When.Setting {
+			return ms.Applies(other) && slices.Contains(s.When.Values, other.value())
 		}
 	}
+	return false
 }
 
-func (ms ModelSettings) HuhGroup() *huh.Group {
+// Values returns the values of the settings that apply, using the default
+// for settings without a value.
+func (ms ModelSettings) Values() map[string]string {
+	values := make(map[string]string)
+	for _, s := range ms {
+		if v := s.value(); v != "" && ms.Applies(s) {
+			values[s.Name] = v
+		}
+	}
+	return values
+}
+
+// HuhGroups returns the form groups to edit the settings. Settings with a
+// condition get their own group that is hidden while the condition is not
+// met.
+func (ms ModelSettings) HuhGroups() []*huh.Group {
 	var fields []huh.Field
+	var conditional []*huh.Group
 	for _, m := range ms {
 		if m.Value == "" && m.DefaultValue != "" {
 			m.Value = m.DefaultValue
 		}
-		switch m.Type {
-		case "int":
-			fields = append(fields, huh.NewInput().
-				Title(m.DisplayName).
-				Validate(func(s string) error {
-					_, err := strconv.Atoi(s)
-					return err
-				}).
-				Value(&m.Value))
-		case "float":
-			fields = append(fields, huh.NewInput().
-				Title(m.DisplayName).
-				Validate(func(s string) error {
-					_, err := strconv.ParseFloat(s, 64)
-					return err
-				}).
-				Value(&m.Value))
-		case "string":
-			fields = append(fields, huh.NewInput().
+		field := m.huhField()
+		if field == nil {
+			continue
+		}
+		if m.When == nil {
+			fields = append(fields, field)
+			continue
+		}
+		conditional = append(conditional, huh.NewGroup(field).
+			WithHideFunc(func() bool { return !ms.Applies(m) }))
+	}
+	return append([]*huh.Group{huh.NewGroup(fields...)}, conditional...)
+}
+
+func (m *ModelSetting) huhField() huh.Field {
+	switch t := m.Type.(type) {
+	case BoolSetting:
+		return huh.NewSelect[string]().
+			Title(m.DisplayName).
+			Options(huh.NewOptions("true", "false")...).
+			Value(&m.Value)
+	case EnumSetting:
+		if len(t.Options) == 0 {
+			return nil
+		} else if len(t.Options) <= 8 {
+			// select
+			return huh.NewSelect[string]().
 				Title(m.DisplayName).
-				Validate(huh.ValidateNotEmpty()).
-				Value(&m.Value))
-		case "boolean":
-			fields = append(fields, huh.NewSelect[string]().
+				Options(huh.NewOptions(t.Options...)...).
+				Value(&m.Value)
+		} else {
+			// text input
+			return huh.NewInput().
 				Title(m.DisplayName).
-				Options(huh.NewOptions("true", "false")...).
-				Value(&m.Value))
-		default:
-			if union, ok := strings.CutPrefix(m.Type, "enum:"); ok {
-				unionFields := strings.Split(union, "|")
-				if len(unionFields) == 0 {
-					continue
-				} else if len(unionFields) <= 8 {
-					// select
-					options := make([]huh.Option[string], len(unionFields))
-					for i, o := range unionFields {
-						options[i] = huh.NewOption(o, o)
-					}
-					fields = append(fields, huh.NewSelect[string]().
-						Title(m.DisplayName).
-						Options(options...).
-						Value(&m.Value))
-				} else {
-					// text input
-					fields = append(fields, huh.NewInput().
-						Title(m.DisplayName).
-						Validate(func(s string) error {
-							if slices.Contains(unionFields, s) {
-								return nil
-							}
-							return fmt.Errorf("invalid value")
-						}).
-						Suggestions(unionFields).
-						Value(&m.Value))
-				}
-			} else {
-				log.Printf("unknown model setting type: %q", m.Type)
-			}
+				Validate(t.Validate).
+				Suggestions(t.Options).
+				Value(&m.Value)
 		}
+	case nil:
+		log.Printf("model setting %q has no type", m.Name)
+		return nil
+	default:
+		return huh.NewInput().
+			Title(m.DisplayName).
+			Validate(t.Validate).
+			Value(&m.Value)
 	}
-	return huh.NewGroup(fields...)
 }
 
-func GetModelSettingString(ms ModelSettings, name string, defaultValue string) string {
+// setting returns the named setting if it applies.
+func (ms ModelSettings) setting(name string) (*ModelSetting, bool) {
 	for _, m := range ms {
 		if m.Name == name {
-			log.Printf("setting %q to %q", name, m.Value)
-			return m.Value
+			return m, ms.Applies(m)
 		}
 	}
+	return nil, false
+}
+
+func GetModelSettingString(ms ModelSettings, name string, defaultValue string) string {
+	if m, ok := ms.setting(name); ok {
+		log.Printf("setting %q to %q", name, m.value())
+		return m.value()
+	}
 	log.Printf("setting %q to default %q", name, defaultValue)
 	return defaultValue
 }
 func GetModelSettingBool(ms ModelSettings, name string, defaultValue bool) bool {
-	for _, m := range ms {
-		if m.Name == name {
-			return m.Value == "true"
-		}
+	if m, ok := ms.setting(name); ok {
+		return m.value() == "true"
 	}
 	return defaultValue
 }
 func GetModelSettingInt(ms ModelSettings, name string, defaultValue int) int {
-	for _, m := range ms {
-		if m.Name == name {
-			v, err := strconv.Atoi(m.Value)
-			if err != nil {
-				return defaultValue
-			}
-			return v
+	if m, ok := ms.setting(name); ok {
+		v, err := strconv.Atoi(m.value())
+		if err != nil {
+			return defaultValue
 		}
+		return v
+	}
+	return defaultValue
+}
+func GetModelSettingFloat(ms ModelSettings, name string, defaultValue float64) float64 {
+	if m, ok := ms.setting(name); ok {
+		v, err := strconv.ParseFloat(m.value(), 64)
+		if err != nil {
+			return defaultValue
+		}
+		return v
 	}
 	return defaultValue
 }
@@ -175,18 +201,28 @@ func GetModelSettingInt(ms ModelSettings, name string, defaultValue int) int {
 type ModelSetting struct {
 	DisplayName  string
 	Name         string
-	Type         string
+	Type         SettingType
 	DefaultValue string
 	Value        string
+	// When makes the setting apply only if another setting has one of the
+	// given values. Otherwise the setting is hidden and not sent.
+	When *SettingCondition
 }
 
-type ModelSettingType uint8
+// value returns the value, or the default if the settings form was never
+// run.
+func (m *ModelSetting) value() string {
+	if m.Value == "" {
+		return m.DefaultValue
+	}
+	return m.Value
+}
 
-const (
-	ModelSettingTypeString ModelSettingType = iota
-	ModelSettingTypeNumber
-	ModelSettingTypeBoolean
-)
+// SettingCondition is met if the setting has one of the values.
+type SettingCondition struct {
+	Setting string
+	Values  []string
+}
 
 const keyringServiceName = "climage"
 
@@ -204,13 +240,161 @@ type Provider interface {
 	LoadCredentials() (map[string]string, error)
 	DeleteCredentials() error
 	Login(ctx context.Context, credentials map[string]string) error
-	GenerateImage(ctx context.Context, model string, prompt string, settings ModelSettings) ([]string, error)
+	GenerateImage(ctx context.Context, model string, prompt string, settings ModelSettings) ([]Image, error)
 	GetModels() []Model
 	GetModelSettings(model string) []ModelSetting
 	GetSettings() any
 	Close() error
 }
 
+// Image is the outcome of one requested image or video. Path is empty if it
+// was removed by the provider's safety filter.
+type Image struct {
+	Path    string       `json:"path,omitempty"`
+	Media   MediaType    `json:"media,omitempty"`
+	Safety  SafetyResult `json:"safety"`
+	Uploads []string     `json:"uploads,omitempty"`
+	// Seed is the seed the image was generated with, if the provider reports
+	// it.
+	Seed *int64 `json:"seed,omitempty"`
+	// ResponseID identifies the provider's response, e.g. the job id.
+	ResponseID string `json:"response_id,omitempty"`
+	// Watermark is the invisible watermark the provider embedded, e.g.
+	// WatermarkSynthID, empty if there is none or it isn't known.
+	Watermark string `json:"watermark,omitempty"`
+	// Model is the model that produced the image, e.g. a fallback of the
+	// requested model. It is set once the generation is finished.
+	Model string `json:"model,omitempty"`
+}
+
+// WatermarkSynthID is Google's invisible watermark, which every Gemini and Veo
+// output and Imagen output without add_watermark=false carries.
+const WatermarkSynthID = "SynthID"
+
+// SafetyResult reports whether and why a provider's content filter removed an
+// image.
+type SafetyResult struct {
+	Filtered   bool     `json:"filtered"`
+	Reason     string   `json:"reason,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+}
+
+func (s SafetyResult) String() string {
+	if !s.Filtered {
+		return "not filtered"
+	}
+	str := "filtered"
+	if s.Reason != "" {
+		str += ": " + s.Reason
+	}
+	if len(s.Categories) > 0 {
+		str += " (" + strings.Join(s.Categories, ", ") + ")"
+	}
+	return str
+}
+
+// Paths returns the file paths of all images that were not filtered.
+func Paths(images []Image) []string {
+	var paths []string
+	for _, img := range images {
+		if img.Path != "" {
+			paths = append(paths, img.Path)
+		}
+	}
+	return paths
+}
+
+// Subject is a character or object that should stay consistent across
+// generations. The ID is how the prompt refers to it, e.g. "[1]".
+type Subject struct {
+	ID          int
+	Name        string
+	Description string
+	// Images are the files or URLs of the reference images, they are only
+	// read by models that use them, see ReadImages.
+	Images []string
+}
+
+// ReadImages reads the reference images of the subject.
+func (s Subject) ReadImages(ctx context.Context) ([][]byte, error) {
+	var images [][]byte
+	for _, imagePath := range s.Images {
+		b, err := ReadInputImage(ctx, imagePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read image of character %q: %w", s.Name, err)
+		}
+		images = append(images, b)
+	}
+	return images, nil
+}
+
+// SubjectProvider is implemented by providers that can keep subjects
+// consistent using reference images.
+type SubjectProvider interface {
+	GenerateImageWithSubjects(ctx context.Context, model string, prompt string, subjects []Subject, settings ModelSettings) ([]Image, error)
+}
+
+// Edit modes. Models declare the modes they support in their capabilities.
+const (
+	// EditModeDefault edits the input images as described by the prompt.
+	EditModeDefault = "edit"
+	// EditModeInpaint inserts content described by the prompt into the
+	// masked area.
+	EditModeInpaint = "inpaint"
+	// EditModeRemove removes the content of the masked area.
+	EditModeRemove = "remove"
+	// EditModeOutpaint extends the image into the masked area.
+	EditModeOutpaint = "outpaint"
+	// EditModeBackgroundSwap replaces the background with the prompt.
+	EditModeBackgroundSwap = "background-swap"
+	// EditModeRecontext places the product of the input images into the
+	// scene described by the prompt.
+	EditModeRecontext = "recontext"
+)
+
+// EditRequest is an edit of existing images.
+type EditRequest struct {
+	Prompt string
+	// Images are the input images.
+	Images [][]byte
+	// Mask marks the area to edit in white. It is required by the inpaint,
+	// remove and outpaint modes.
+	Mask []byte
+	// Mode is one of the edit modes, EditModeDefault if empty.
+	Mode string
+}
+
+// EditProvider is implemented by providers that can edit existing images.
+type EditProvider interface {
+	EditImage(ctx context.Context, model string, req EditRequest, settings ModelSettings) ([]Image, error)
+}
+
+// UpscaleProvider is implemented by providers that can upscale images.
+type UpscaleProvider interface {
+	// UpscaleFactors returns the supported upscale factors.
+	UpscaleFactors() []int
+	// UpscalePrice is the estimated price of one upscaled image in USD, zero
+	// if unknown.
+	UpscalePrice() float64
+	UpscaleImage(ctx context.Context, image []byte, factor int) ([]Image, error)
+}
+
+// VideoProvider is implemented by providers with video models.
+type VideoProvider interface {
+	GenerateVideo(ctx context.Context, model string, prompt string, settings ModelSettings) ([]Image, error)
+}
+
+// DescribeProvider is implemented by providers with vision models that can
+// describe images.
+type DescribeProvider interface {
+	// VisionModels returns the names of the vision models, the first is the
+	// default.
+	VisionModels() []string
+	// DescribeImage returns the answer of the model to the instruction about
+	// the image.
+	DescribeImage(ctx context.Context, model string, image []byte, instruction string) (string, error)
+}
+
 var Providers []Provider
 
 func GetProviderNames() []string {
@@ -243,7 +427,110 @@ func Close() error {
 	return nil
 }
 
-func getOutDir() (string, error) {
+// outDir is the directory generated images are saved to, see SetOutDir.
+var outDir string
+
+// outDirOverride replaces outDir, see OverrideOutDir.
+var outDirOverride string
+
+// Output folders the climage folder is created in if no output dir is set.
+const (
+	OutputFolderDownloads = "downloads"
+	OutputFolderPictures  = "pictures"
+)
+
+// outFolder is the user folder used by DefaultOutDir, see SetOutFolder.
+var outFolder = OutputFolderDownloads
+
+// SetOutDir sets the directory generated images are saved to. An empty dir
+// uses the climage folder in the user's downloads or pictures dir, see
+// SetOutFolder.
+func SetOutDir(dir string) {
+	outDir = dir
+}
+
+// OverrideOutDir sets the directory generated images are saved to regardless
+// of SetOutDir, e.g. from the --out flag. An empty dir removes the override.
+func OverrideOutDir(dir string) {
+	outDirOverride = dir
+}
+
+// SetOutFolder selects the user folder the default output dir is created in,
+// one of OutputFolderDownloads (default if empty) and OutputFolderPictures.
+func SetOutFolder(folder string) error {
+	switch folder {
+	case "":
+		outFolder = OutputFolderDownloads
+	case OutputFolderDownloads, OutputFolderPictures:
+		outFolder = folder
+	default:
+		return fmt.Errorf("unknown output folder %q, expected %q or %q", folder, OutputFolderDownloads, OutputFolderPictures)
+	}
+	return nil
+}
+
+// Output layouts. A layout is a relative path in which the placeholders YYYY,
+// MM and DD are replaced with the date of the generation.
+const (
+	OutputLayoutDate = "YYYY/MM/DD"
+	// OutputLayoutFlat saves all images directly into the output dir.
+	OutputLayoutFlat = "flat"
+)
+
+// outLayout is the layout of the subdirectories of the output dir, see
+// SetOutLayout.
+var outLayout = OutputLayoutDate
+
+// SetOutLayout sets the layout of the subdirectories generated images are
+// saved to, OutputLayoutDate if empty.
+func SetOutLayout(layout string) error {
+	if layout == "" {
+		layout = OutputLayoutDate
+	}
+	if layout != OutputLayoutFlat {
+		p := filepath.Clean(filepath.FromSlash(layout))
+		if filepath.IsAbs(p) || p == ".." || strings.HasPrefix(p, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("output layout %q must be a path inside the output dir", layout)
+		}
+	}
+	outLayout = layout
+	return nil
+}
+
+// layoutDir returns the subdirectory for images generated at t.
+func layoutDir(t time.Time) string {
+	if outLayout == OutputLayoutFlat {
+		return ""
+	}
+	r := strings.NewReplacer(
+		"YYYY", t.Format("2006"),
+		"MM", t.Format("01"),
+		"DD", t.Format("02"),
+	)
+	return filepath.Clean(filepath.FromSlash(r.Replace(outLayout)))
+}
+
+// OutDir returns the directory generated images are saved to.
+func OutDir() (string, error) {
+	if outDirOverride != "" {
+		return outDirOverride, nil
+	}
+	if outDir != "" {
+		return outDir, nil
+	}
+	return DefaultOutDir()
+}
+
+// DefaultOutDir returns the climage folder in the user's downloads dir, or in
+// the pictures dir if selected with SetOutFolder.
+func DefaultOutDir() (string, error) {
+	if outFolder == OutputFolderPictures {
+		dir, err := downloads.GetUserPicturesDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user pictures dir: %w", err)
+		}
+		return filepath.Join(dir, "climage"), nil
+	}
 	dir, err := downloads.GetUserDownloadsDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get user downloads dir: %w", err)
@@ -251,7 +538,8 @@ func getOutDir() (string, error) {
 	return filepath.Join(dir, "climage"), nil
 }
 
-func getDataDir() (string, error) {
+// DataDir returns the directory climage stores its data in.
+func DataDir() (string, error) {
 	userDataDir, err := os.UserConfigDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get user config dir: %w", err)
@@ -0,0 +1,358 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package providertest is a conformance suite for provider implementations.
+// Every provider should pass it:
+//
+//	func TestConformance(t *testing.T) {
+//		providertest.Run(t, &MyProvider{}, providertest.Options{
+//			Credentials: map[string]string{"api_key": "test"},
+//		})
+//	}
+//
+// The suite doesn't send requests over the network, they are answered by a fake
+// transport, so no real credentials are needed.
+package providertest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+// Options configure the suite for a provider.
+type Options struct {
+	// Credentials are saved and loaded again to check the credential
+	// round-trip, and used to log in for the cancellation check. They don't
+	// have to be valid, but must be well-formed. The round-trip is skipped if
+	// nil.
+	Credentials map[string]string
+}
+
+var knownEditModes = []string{
+	providers.EditModeDefault,
+	providers.EditModeInpaint,
+	providers.EditModeRemove,
+	providers.EditModeOutpaint,
+	providers.EditModeBackgroundSwap,
+	providers.EditModeRecontext,
+}
+
+// Run runs the conformance suite against p. Secrets and generated files are
+// kept in temporary directories.
+func Run(t *testing.T, p providers.Provider, opts Options) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("APPDATA", home)
+	if err := providers.SetCredentialStore(providers.CredentialStoreFile); err != nil {
+		t.Fatal(err)
+	}
+	providers.SetOutDir(t.TempDir())
+	t.Cleanup(func() {
+		_ = p.Close()
+		_ = providers.SetCredentialStore(providers.CredentialStoreKeyring)
+		providers.SetOutDir("")
+	})
+
+	t.Run("Name", func(t *testing.T) { testName(t, p) })
+	t.Run("LoginFields", func(t *testing.T) { testLoginFields(t, p) })
+	t.Run("Credentials", func(t *testing.T) { testCredentials(t, p, opts) })
+	t.Run("Models", func(t *testing.T) { testModels(t, p) })
+	t.Run("Cancellation", func(t *testing.T) { testCancellation(t, p, opts) })
+}
+
+func testName(t *testing.T, p providers.Provider) {
+	name := p.GetName()
+	if name == "" {
+		t.Fatal("provider name is empty")
+	}
+	if strings.ContainsAny(name, "/ ") {
+		t.Errorf("provider name %q must not contain slashes or spaces, it is the prefix of the model names", name)
+	}
+}
+
+func testLoginFields(t *testing.T, p providers.Provider) {
+	seen := make(map[string]bool)
+	for _, f := range p.GetLoginFields() {
+		if f.Name == "" || f.DisplayName == "" {
+			t.Errorf("login field %+v needs a name and a display name", f)
+		}
+		if seen[f.Name] {
+			t.Errorf("duplicate login field %q", f.Name)
+		}
+		seen[f.Name] = true
+		if f.Type != "string" && f.Type != "file" {
+			t.Errorf("login field %q has unknown type %q, expected string or file", f.Name, f.Type)
+		}
+	}
+}
+
+func testCredentials(t *testing.T, p providers.Provider, opts Options) {
+	if opts.Credentials == nil {
+		t.Skip("no credentials configured")
+	}
+	if err := p.SaveCredentials(opts.Credentials); err != nil {
+		t.Fatalf("failed to save credentials: %v", err)
+	}
+	loaded, err := p.LoadCredentials()
+	if err != nil {
+		t.Fatalf("failed to load saved credentials: %v", err)
+	}
+	for _, f := range p.GetLoginFields() {
+		if loaded[f.Name] != opts.Credentials[f.Name] {
+			t.Errorf("login field %q: loaded %q, saved %q", f.Name, loaded[f.Name], opts.Credentials[f.Name])
+		}
+	}
+	if err := p.DeleteCredentials(); err != nil {
+		t.Fatalf("failed to delete credentials: %v", err)
+	}
+	if len(p.GetLoginFields()) == 0 {
+		return
+	}
+	_, err = p.LoadCredentials()
+	if err == nil {
+		t.Fatal("loading deleted credentials succeeded")
+	}
+	if kind := providers.KindOf(err); kind != providers.ErrorKindAuth {
+		t.Errorf("loading deleted credentials returned a %s error, expected auth: %v", kind, err)
+	}
+}
+
+func testModels(t *testing.T, p providers.Provider) {
+	models := p.GetModels()
+	if len(models) == 0 {
+		t.Fatal("provider has no models")
+	}
+	seen := make(map[string]bool)
+	for _, m := range models {
+		t.Run(m.Name, func(t *testing.T) {
+			if m.Name == "" || m.DisplayName == "" {
+				t.Fatalf("model %+v needs a name and a display name", m)
+			}
+			if seen[m.Name] {
+				t.Errorf("duplicate model %q", m.Name)
+			}
+			seen[m.Name] = true
+			testSettings(t, m)
+			testCapabilities(t, p, m)
+		})
+	}
+}
+
+func testSettings(t *testing.T, m providers.Model) {
+	settings := m.Settings.Clone()
+	names := make(map[string]bool)
+	for _, s := range settings {
+		if names[s.Name] {
+			t.Errorf("duplicate setting %q", s.Name)
+		}
+		names[s.Name] = true
+		if s.Type == nil {
+			t.Errorf("setting %q has no type", s.Name)
+			continue
+		}
+		if s.DefaultValue != "" {
+			if err := s.Type.Validate(s.DefaultValue); err != nil {
+				t.Errorf("default value %q of setting %q: %v", s.DefaultValue, s.Name, err)
+			}
+		}
+		s.Value = s.DefaultValue
+	}
+	for _, s := range settings {
+		if s.When != nil && !names[s.When.Setting] {
+			t.Errorf("setting %q depends on the unknown setting %q", s.Name, s.When.Setting)
+		}
+	}
+	if err := m.Validate("a lighthouse at dusk", settings); err != nil {
+		t.Errorf("default settings don't validate: %v", err)
+	}
+	if err := m.Validate("", settings); err == nil {
+		t.Error("empty prompt validates")
+	}
+	if limit := m.Capabilities.MaxPromptTokens; limit > 0 {
+		if err := m.Validate(strings.Repeat("word ", limit), settings); err == nil {
+			t.Errorf("prompt of more than %d tokens validates", limit)
+		}
+	}
+	if limit := m.Capabilities.MaxImages; limit > 0 {
+		count := "number_of_images"
+		if m.Media == providers.MediaVideo {
+			count = "number_of_videos"
+		}
+		for _, s := range settings {
+			if s.Name == count {
+				s.Value = strconv.Itoa(limit + 1)
+			}
+		}
+		if names[count] {
+			if err := m.Validate("a lighthouse at dusk", settings); err == nil {
+				t.Errorf("%s of %d validates, the limit is %d", count, limit+1, limit)
+			}
+		}
+	}
+	unknown := append(m.Settings.Clone(), &providers.ModelSetting{Name: "providertest_unknown", Type: providers.StringSetting{}, Value: "x"})
+	if err := m.Validate("a lighthouse at dusk", unknown); err == nil {
+		t.Error("unknown setting validates")
+	}
+}
+
+func testCapabilities(t *testing.T, p providers.Provider, m providers.Model) {
+	for _, mode := range m.Capabilities.EditModes {
+		if !slices.Contains(knownEditModes, mode) {
+			t.Errorf("unknown edit mode %q", mode)
+		}
+	}
+	if len(m.Capabilities.EditModes) > 0 {
+		if _, ok := p.(providers.EditProvider); !ok {
+			t.Error("model declares edit modes but the provider doesn't implement EditProvider")
+		}
+	}
+	switch m.Media {
+	case "", providers.MediaImage:
+	case providers.MediaVideo:
+		if _, ok := p.(providers.VideoProvider); !ok {
+			t.Error("video model but the provider doesn't implement VideoProvider")
+		}
+	default:
+		t.Errorf("unknown media type %q", m.Media)
+	}
+	if m.Capabilities.MaxPromptTokens < 0 || m.Capabilities.MaxImages < 0 || m.Capabilities.MaxInputImages < 0 {
+		t.Errorf("negative capability limits: %+v", m.Capabilities)
+	}
+	if m.PricePerImage < 0 {
+		t.Errorf("negative price %v", m.PricePerImage)
+	}
+}
+
+// blockingTransport answers token requests and GET requests, like the
+// credential checks of a login, and blocks all other requests until they are
+// cancelled. started receives every blocked request.
+type blockingTransport struct {
+	started chan *http.Request
+}
+
+func (b blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	var body string
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/token"):
+		body = `{"access_token": "providertest", "token_type": "Bearer", "expires_in": 3600}`
+	case req.Method == http.MethodGet:
+		body = `{}`
+	default:
+		select {
+		case b.started <- req:
+		default:
+		}
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// testCancellation checks that cancelling the context fails a generation
+// promptly, also while its request is in flight.
+func testCancellation(t *testing.T, p providers.Provider, opts Options) {
+	if opts.Credentials != nil {
+		if err := p.SaveCredentials(opts.Credentials); err != nil {
+			t.Fatalf("failed to save credentials: %v", err)
+		}
+		t.Cleanup(func() { _ = p.DeleteCredentials() })
+	}
+	// the provider logs in again through the blocking transport
+	_ = p.Close()
+	transport := blockingTransport{started: make(chan *http.Request, 1)}
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = transport
+	t.Cleanup(func() {
+		http.DefaultTransport = defaultTransport
+		_ = p.Close()
+	})
+
+	m := p.GetModels()[0]
+	settings := m.Settings.Clone()
+	for _, s := range settings {
+		s.Value = s.DefaultValue
+	}
+	generate := func(ctx context.Context) <-chan error {
+		errs := make(chan error, 1)
+		go func() {
+			var err error
+			if m.Media == providers.MediaVideo {
+				if vp, ok := p.(providers.VideoProvider); ok {
+					_, err = vp.GenerateVideo(ctx, m.Name, "a lighthouse at dusk", settings)
+				}
+			} else {
+				_, err = p.GenerateImage(ctx, m.Name, "a lighthouse at dusk", settings)
+			}
+			errs <- err
+		}()
+		return errs
+	}
+	wait := func(errs <-chan error) {
+		t.Helper()
+		select {
+		case err := <-errs:
+			if err == nil {
+				t.Fatal("generation with a cancelled context succeeded")
+			}
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("generation with a cancelled context returned %v, expected context.Canceled", err)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("generation with a cancelled context didn't return within 10s")
+		}
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	errs := generate(ctx)
+	select {
+	case <-transport.started:
+		cancel()
+		wait(errs)
+	case err := <-errs:
+		// providers without requests, like mock, only check the context
+		if err != nil {
+			t.Fatalf("generation failed before sending a request: %v", err)
+		}
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		wait(generate(ctx))
+	case <-time.After(10 * time.Second):
+		t.Fatal("generation didn't send a request within 10s")
+	}
+}
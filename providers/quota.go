@@ -0,0 +1,41 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"context"
+	"time"
+)
+
+// Quota is an allowance a provider reports, e.g. prepaid credits.
+type Quota struct {
+	Name      string  `json:"name"`
+	Remaining float64 `json:"remaining"`
+	// Limit is zero if the provider doesn't report one.
+	Limit float64 `json:"limit,omitempty"`
+	Unit  string  `json:"unit"`
+	// Reset is when the quota is refilled, zero if unknown or never.
+	Reset time.Time `json:"reset,omitzero"`
+}
+
+// QuotaProvider is implemented by providers that report their remaining
+// credits or quota.
+type QuotaProvider interface {
+	Quota(ctx context.Context) ([]Quota, error)
+}
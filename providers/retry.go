@@ -0,0 +1,167 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = 30 * time.Second
+)
+
+// retryTransport retries requests that failed with a transient error
+// (429, 5xx or a network timeout) using jittered exponential backoff.
+// Requests that aren't idempotent, e.g. generation POSTs, are only retried if
+// the server didn't process them (429 or 503), so a job isn't submitted and
+// billed twice. A Retry-After header sent by the server takes precedence, up to
+// the maximum backoff.
+type retryTransport struct {
+	base           http.RoundTripper
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+func newRetryTransport(base http.RoundTripper, opts RetryOptions) *retryTransport {
+	t := &retryTransport{
+		base:           base,
+		maxAttempts:    opts.MaxAttempts,
+		initialBackoff: time.Duration(opts.InitialBackoff),
+		maxBackoff:     time.Duration(opts.MaxBackoff),
+	}
+	if t.maxAttempts <= 0 {
+		t.maxAttempts = defaultMaxAttempts
+	}
+	if t.initialBackoff <= 0 {
+		t.initialBackoff = defaultInitialBackoff
+	}
+	if t.maxBackoff <= 0 {
+		t.maxBackoff = defaultMaxBackoff
+	}
+	return t
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.maxAttempts || !isTransient(req, resp, err) || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				delay = min(retryAfter, t.maxBackoff)
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+			log.Printf("retrying %s %s after %s: %s", req.Method, req.URL.Host, delay, resp.Status)
+		} else {
+			log.Printf("retrying %s %s after %s: %v", req.Method, req.URL.Host, delay, err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	d := t.initialBackoff << (attempt - 1)
+	if d <= 0 || d > t.maxBackoff {
+		d = t.maxBackoff
+	}
+	// full jitter in the upper half to avoid synchronized retries
+	return d/2 + rand.N(d/2+1)
+}
+
+func isTransient(req *http.Request, resp *http.Response, err error) bool {
+	if !isIdempotent(req.Method) {
+		// the request may have been processed unless the server says otherwise
+		return err == nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable)
+	}
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout()
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// isIdempotent reports if sending a request with the method twice has the
+// same effect as sending it once.
+func isIdempotent(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// newTransport returns the HTTP transport a provider should use for its API
+// requests, configured with the provider's options.
+func newTransport(providerName string) http.RoundTripper {
+	opts := getOptions(providerName)
+	return newRetryTransport(debugTransport(providerName, cassetteTransport(providerName, baseTransport(providerName))), opts.Retry)
+}
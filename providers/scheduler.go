@@ -0,0 +1,106 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultMaxInFlight = 2
+
+// scheduler queues requests to a single provider. It limits the number of
+// requests in flight and the number of requests started per minute.
+type scheduler struct {
+	slots chan struct{}
+
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newScheduler(opts LimitOptions) *scheduler {
+	maxInFlight := opts.MaxInFlight
+	if maxInFlight <= 0 {
+		maxInFlight = defaultMaxInFlight
+	}
+	s := &scheduler{slots: make(chan struct{}, maxInFlight)}
+	if opts.RequestsPerMinute > 0 {
+		s.interval = time.Minute / time.Duration(opts.RequestsPerMinute)
+	}
+	return s
+}
+
+// acquire blocks until the request may be sent. The returned function must be
+// called once the request is done.
+func (s *scheduler) acquire(ctx context.Context) (func(), error) {
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	release := func() { <-s.slots }
+
+	if s.interval > 0 {
+		s.mu.Lock()
+		now := time.Now()
+		start := now
+		if s.next.After(now) {
+			start = s.next
+		}
+		s.next = start.Add(s.interval)
+		s.mu.Unlock()
+
+		if wait := start.Sub(now); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				release()
+				return nil, ctx.Err()
+			}
+		}
+	}
+	return release, nil
+}
+
+var (
+	schedulersMu sync.Mutex
+	schedulers   = make(map[string]*scheduler)
+)
+
+func getScheduler(providerName string) *scheduler {
+	schedulersMu.Lock()
+	defer schedulersMu.Unlock()
+	s, ok := schedulers[providerName]
+	if !ok {
+		s = newScheduler(getOptions(providerName).Limits)
+		schedulers[providerName] = s
+	}
+	return s
+}
+
+// Schedule waits until the provider's rate limits allow another request. The
+// returned function releases the slot and must be called when the request is
+// done.
+func Schedule(ctx context.Context, providerName string) (func(), error) {
+	return getScheduler(providerName).acquire(ctx)
+}
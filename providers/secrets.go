@@ -0,0 +1,214 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/zalando/go-keyring"
+)
+
+// CredentialStore is where provider secrets are stored.
+type CredentialStore string
+
+const (
+	// CredentialStoreKeyring uses the OS keyring and falls back to the file
+	// store if the keyring is unreachable.
+	CredentialStoreKeyring CredentialStore = "keyring"
+	// CredentialStoreFile stores secrets in a file only readable by the user.
+	CredentialStoreFile CredentialStore = "file"
+)
+
+// ErrSecretNotFound is returned by GetSecret if the secret does not exist.
+var ErrSecretNotFound = errors.New("secret not found")
+
+var (
+	secretsMu          sync.Mutex
+	credentialStore    = CredentialStoreKeyring
+	keyringUnavailable bool
+)
+
+// SetCredentialStore selects the store used for provider secrets.
+func SetCredentialStore(store CredentialStore) error {
+	switch store {
+	case "":
+		store = CredentialStoreKeyring
+	case CredentialStoreKeyring, CredentialStoreFile:
+	default:
+		return fmt.Errorf("unknown credential store %q", store)
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	credentialStore = store
+	return nil
+}
+
+// useKeyring reports whether the keyring should be tried. Must be called with
+// secretsMu held.
+func useKeyring() bool {
+	return credentialStore == CredentialStoreKeyring && !keyringUnavailable && !headless
+}
+
+// keyringFailed checks if err means the keyring is unreachable. In that case
+// a warning is printed once and the file store is used from now on. Must be
+// called with secretsMu held.
+func keyringFailed(err error) bool {
+	if err == nil || errors.Is(err, keyring.ErrNotFound) || errors.Is(err, keyring.ErrSetDataTooBig) {
+		return false
+	}
+	keyringUnavailable = true
+	secretsFile, _ := getSecretsFilePath()
+	log.Printf("warning: OS keyring is unavailable (%v), falling back to %s. Set \"credential_store\": \"file\" in the config to silence this warning.", err, secretsFile)
+	return true
+}
+
+// SetSecret stores a secret in the configured credential store.
+func SetSecret(user string, secret string) error {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	if useKeyring() {
+		if err := keyring.Set(keyringServiceName, user, secret); !keyringFailed(err) {
+			return err
+		}
+	}
+	secrets, err := readSecretsFile()
+	if err != nil {
+		return err
+	}
+	secrets[user] = secret
+	return writeSecretsFile(secrets)
+}
+
+// GetSecret loads a secret. ErrSecretNotFound is returned if it does not exist.
+func GetSecret(user string) (string, error) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	if useKeyring() {
+		secret, err := keyring.Get(keyringServiceName, user)
+		if errors.Is(err, keyring.ErrNotFound) {
+			return "", ErrSecretNotFound
+		}
+		if !keyringFailed(err) {
+			return secret, err
+		}
+	}
+	secrets, err := readSecretsFile()
+	if err != nil {
+		return "", err
+	}
+	secret, ok := secrets[user]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return secret, nil
+}
+
+// DeleteSecret removes a secret. Missing secrets are not an error.
+func DeleteSecret(user string) error {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	if useKeyring() {
+		err := keyring.Delete(keyringServiceName, user)
+		if !keyringFailed(err) && !errors.Is(err, keyring.ErrNotFound) {
+			return err
+		}
+	}
+	secrets, err := readSecretsFile()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[user]; !ok {
+		return nil
+	}
+	delete(secrets, user)
+	return writeSecretsFile(secrets)
+}
+
+// PurgeSecrets removes all secrets of climage from the OS keyring and the
+// secrets file, regardless of the configured store. The keyring is left alone
+// in headless mode.
+func PurgeSecrets() error {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	var errs []error
+	if !headless {
+		if err := keyring.DeleteAll(keyringServiceName); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+			errs = append(errs, fmt.Errorf("failed to delete secrets from the OS keyring: %w", err))
+		}
+	}
+	secretsFile, err := getSecretsFilePath()
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	if err := os.Remove(secretsFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		errs = append(errs, fmt.Errorf("failed to delete secrets file: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+func getSecretsFilePath() (string, error) {
+	dataDir, err := DataDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get data dir: %w", err)
+	}
+	return filepath.Join(dataDir, "secrets.json"), nil
+}
+
+func readSecretsFile() (map[string]string, error) {
+	secretsFile, err := getSecretsFilePath()
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(secretsFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string]string), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+	secrets := make(map[string]string)
+	if err := json.Unmarshal(b, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to decode secrets file: %w", err)
+	}
+	return secrets, nil
+}
+
+func writeSecretsFile(secrets map[string]string) error {
+	secretsFile, err := getSecretsFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(secretsFile), 0700); err != nil {
+		return fmt.Errorf("failed to create data dir: %w", err)
+	}
+	b, err := json.Marshal(secrets)
+	if err != nil {
+		return fmt.Errorf("failed to encode secrets file: %w", err)
+	}
+	if err := WriteFileAtomic(secretsFile, b, 0600); err != nil {
+		return fmt.Errorf("failed to write secrets file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SettingType is the schema of a model setting. Values are kept as strings
+// so they can be edited in forms and stored in the config file, the type
+// validates them.
+type SettingType interface {
+	// Validate returns an error describing why v is not a valid value.
+	Validate(v string) error
+	// String describes the valid values, e.g. "an integer".
+	String() string
+}
+
+// IntSetting is an integer. The value has to be in [Min, Max] unless both
+// are zero.
+type IntSetting struct {
+	Min int
+	Max int
+}
+
+func (s IntSetting) Validate(v string) error {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("expected %s", s)
+	}
+	if (s.Min != 0 || s.Max != 0) && (n < s.Min || n > s.Max) {
+		return fmt.Errorf("expected %s", s)
+	}
+	return nil
+}
+
+func (s IntSetting) String() string {
+	if s.Min != 0 || s.Max != 0 {
+		return fmt.Sprintf("an integer from %d to %d", s.Min, s.Max)
+	}
+	return "an integer"
+}
+
+// FloatSetting is a number. The value has to be in [Min, Max] unless both are
+// zero.
+type FloatSetting struct {
+	Min float64
+	Max float64
+}
+
+func (s FloatSetting) Validate(v string) error {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fmt.Errorf("expected %s", s)
+	}
+	if (s.Min != 0 || s.Max != 0) && (f < s.Min || f > s.Max) {
+		return fmt.Errorf("expected %s", s)
+	}
+	return nil
+}
+
+func (s FloatSetting) String() string {
+	if s.Min != 0 || s.Max != 0 {
+		return fmt.Sprintf("a number from %g to %g", s.Min, s.Max)
+	}
+	return "a number"
+}
+
+// StringSetting is any non-empty text.
+type StringSetting struct{}
+
+func (StringSetting) Validate(v string) error {
+	if strings.TrimSpace(v) == "" {
+		return errors.New("expected a non-empty text")
+	}
+	return nil
+}
+
+func (StringSetting) String() string {
+	return "a text"
+}
+
+// BoolSetting is "true" or "false".
+type BoolSetting struct{}
+
+func (BoolSetting) Validate(v string) error {
+	if v != "true" && v != "false" {
+		return errors.New("expected true or false")
+	}
+	return nil
+}
+
+func (BoolSetting) String() string {
+	return "true or false"
+}
+
+// EnumSetting is one of the options.
+type EnumSetting struct {
+	Options []string
+}
+
+func (s EnumSetting) Validate(v string) error {
+	if !slices.Contains(s.Options, v) {
+		return fmt.Errorf("expected %s", s)
+	}
+	return nil
+}
+
+func (s EnumSetting) String() string {
+	return "one of " + strings.Join(s.Options, ", ")
+}
+
+// settingJSON is the JSON encoding of a model setting, e.g. for caching model
+// lists.
+type settingJSON struct {
+	Name         string            `json:"name"`
+	DisplayName  string            `json:"display_name"`
+	Type         string            `json:"type"`
+	Min          *float64          `json:"min,omitempty"`
+	Max          *float64          `json:"max,omitempty"`
+	Options      []string          `json:"options,omitempty"`
+	DefaultValue string            `json:"default_value,omitempty"`
+	Value        string            `json:"value,omitempty"`
+	When         *SettingCondition `json:"when,omitempty"`
+}
+
+func (m ModelSetting) MarshalJSON() ([]byte, error) {
+	j := settingJSON{Name: m.Name, DisplayName: m.DisplayName, DefaultValue: m.DefaultValue, Value: m.Value, When: m.When}
+	bounds := func(lo float64, hi float64) {
+		if lo != 0 || hi != 0 {
+			j.Min, j.Max = &lo, &hi
+		}
+	}
+	switch t := m.Type.(type) {
+	case IntSetting:
+		j.Type = "int"
+		bounds(float64(t.Min), float64(t.Max))
+	case FloatSetting:
+		j.Type = "float"
+		bounds(t.Min, t.Max)
+	case StringSetting:
+		j.Type = "string"
+	case BoolSetting:
+		j.Type = "boolean"
+	case EnumSetting:
+		j.Type = "enum"
+		j.Options = t.Options
+	default:
+		return nil, fmt.Errorf("setting %q has unknown type %T", m.Name, m.Type)
+	}
+	return json.Marshal(j)
+}
+
+func (m *ModelSetting) UnmarshalJSON(b []byte) error {
+	var j settingJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	var lo, hi float64
+	if j.Min != nil {
+		lo = *j.Min
+	}
+	if j.Max != nil {
+		hi = *j.Max
+	}
+	*m = ModelSetting{Name: j.Name, DisplayName: j.DisplayName, DefaultValue: j.DefaultValue, Value: j.Value, When: j.When}
+	switch j.Type {
+	case "int":
+		m.Type = IntSetting{Min: int(lo), Max: int(hi)}
+	case "float":
+		m.Type = FloatSetting{Min: lo, Max: hi}
+	case "string":
+		m.Type = StringSetting{}
+	case "boolean":
+		m.Type = BoolSetting{}
+	case "enum":
+		m.Type = EnumSetting{Options: j.Options}
+	default:
+		return fmt.Errorf("setting %q has unknown type %q", j.Name, j.Type)
+	}
+	return nil
+}
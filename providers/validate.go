@@ -0,0 +1,136 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Capabilities declare the limits of a model that are checked before a
+// request is sent. Zero values mean unlimited.
+type Capabilities struct {
+	// MaxPromptTokens is the maximum prompt length in tokens. The token count
+	// is estimated from the prompt length.
+	MaxPromptTokens int
+	// MaxImages is the maximum number of images or videos per request.
+	MaxImages int
+	// MaxInputImages is the maximum number of input images for editing.
+	MaxInputImages int
+	// EditModes are the supported edit modes. Models without edit modes
+	// can't edit images.
+	EditModes []string
+}
+
+// estimateTokens roughly estimates the token count of a text. Typical
+// tokenizers produce about one token per four characters of English text.
+func estimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// Validate checks the prompt and settings against the model's capabilities
+// and settings schema.
+func (m Model) Validate(prompt string, settings ModelSettings) error {
+	var errs []error
+
+	if strings.TrimSpace(prompt) == "" {
+		errs = append(errs, errors.New("prompt is empty"))
+	}
+	if limit := m.Capabilities.MaxPromptTokens; limit > 0 {
+		if n := estimateTokens(prompt); n > limit {
+			errs = append(errs, fmt.Errorf("prompt is too long for %s: about %d tokens, at most %d are supported; shorten the prompt", m.DisplayName, n, limit))
+		}
+	}
+
+	for _, s := range settings {
+		schema, ok := m.setting(s.Name)
+		if !ok {
+			errs = append(errs, fmt.Errorf("setting %q is not supported by %s", s.Name, m.DisplayName))
+			continue
+		}
+		if s.Value != "" && schema.Type != nil && settings.Applies(s) {
+			if err := schema.Type.Validate(s.Value); err != nil {
+				errs = append(errs, fmt.Errorf("invalid value %q for setting %q of %s: %w", s.Value, s.Name, m.DisplayName, err))
+			}
+		}
+	}
+
+	if limit := m.Capabilities.MaxImages; limit > 0 {
+		setting, noun := "number_of_images", "images"
+		if m.Media == MediaVideo {
+			setting, noun = "number_of_videos", "videos"
+		}
+		if n := GetModelSettingInt(settings, setting, 1); n < 1 || n > limit {
+			errs = append(errs, fmt.Errorf("%s can generate 1 to %d %s per request, got %d; change %q", m.DisplayName, limit, noun, n, setting))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateEdit checks the edit request against the model's capabilities.
+func (m Model) ValidateEdit(req EditRequest) error {
+	if len(m.Capabilities.EditModes) == 0 {
+		return fmt.Errorf("%s can't edit images", m.DisplayName)
+	}
+	mode := req.Mode
+	if mode == "" {
+		mode = EditModeDefault
+	}
+	var errs []error
+	if !slices.Contains(m.Capabilities.EditModes, mode) {
+		errs = append(errs, fmt.Errorf("%s doesn't support edit mode %q, supported are %s", m.DisplayName, mode, strings.Join(m.Capabilities.EditModes, ", ")))
+	}
+	if len(req.Images) == 0 {
+		errs = append(errs, errors.New("no input images"))
+	} else if limit := m.Capabilities.MaxInputImages; limit > 0 && len(req.Images) > limit {
+		errs = append(errs, fmt.Errorf("%s accepts at most %d input images, got %d", m.DisplayName, limit, len(req.Images)))
+	}
+	switch mode {
+	case EditModeInpaint, EditModeRemove, EditModeOutpaint:
+		if len(req.Mask) == 0 {
+			errs = append(errs, fmt.Errorf("edit mode %q requires a mask", mode))
+		}
+		if len(req.Images) > 1 {
+			errs = append(errs, fmt.Errorf("edit mode %q takes a single input image", mode))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m Model) setting(name string) (*ModelSetting, bool) {
+	for _, s := range m.Settings {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// FindModel returns the model with the given name of the provider.
+func FindModel(p Provider, name string) (Model, error) {
+	for _, m := range Models(p) {
+		if m.Name == name {
+			return m, nil
+		}
+	}
+	return Model{}, fmt.Errorf("model %q not found in provider %q", name, p.GetName())
+}
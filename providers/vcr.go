@@ -0,0 +1,214 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package providers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// CassetteMode selects whether provider HTTP interactions are recorded to or
+// replayed from a cassette file.
+type CassetteMode string
+
+const (
+	// CassetteRecord sends requests to the network and records them.
+	CassetteRecord CassetteMode = "record"
+	// CassetteReplay answers requests from the cassette without network
+	// access. Requests that were not recorded fail.
+	CassetteReplay CassetteMode = "replay"
+)
+
+// Interaction is one recorded HTTP request and its response. Secrets are
+// scrubbed before they are recorded.
+type Interaction struct {
+	Provider string      `json:"provider"`
+	Method   string      `json:"method"`
+	URL      string      `json:"url"`
+	Body     []byte      `json:"body,omitempty"`
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header,omitempty"`
+	Response []byte      `json:"response,omitempty"`
+	used     bool
+}
+
+// cassette is the active cassette. It is nil if neither recording nor
+// replaying.
+var (
+	cassetteMu   sync.Mutex
+	cassette     *cassetteState
+	secretFields = regexp.MustCompile(`("(?:access_token|refresh_token|id_token|client_secret|private_key|api_key)"\s*:\s*)"[^"]*"`)
+	secretParams = regexp.MustCompile(`\b(access_token|refresh_token|client_secret|code|code_verifier|key|assertion)=[^&\s"]*`)
+)
+
+type cassetteState struct {
+	mode         CassetteMode
+	path         string
+	interactions []*Interaction
+}
+
+// UseCassette routes the HTTP requests of all providers through a cassette.
+// In replay mode the cassette is read from path, in record mode it is written
+// to path by SaveCassette.
+func UseCassette(mode CassetteMode, path string) error {
+	state := &cassetteState{mode: mode, path: path}
+	switch mode {
+	case CassetteRecord:
+	case CassetteReplay:
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(b, &state.interactions); err != nil {
+			return fmt.Errorf("failed to decode cassette: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown cassette mode %q, expected %q or %q", mode, CassetteRecord, CassetteReplay)
+	}
+	cassetteMu.Lock()
+	defer cassetteMu.Unlock()
+	cassette = state
+	return nil
+}
+
+// SaveCassette writes the recorded interactions. It does nothing unless
+// recording.
+func SaveCassette() error {
+	cassetteMu.Lock()
+	defer cassetteMu.Unlock()
+	if cassette == nil || cassette.mode != CassetteRecord {
+		return nil
+	}
+	b, err := json.MarshalIndent(cassette.interactions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := WriteFileAtomic(cassette.path, b, 0644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// cassetteTransport wraps base with the active cassette, if any.
+func cassetteTransport(providerName string, base http.RoundTripper) http.RoundTripper {
+	cassetteMu.Lock()
+	defer cassetteMu.Unlock()
+	if cassette == nil {
+		return base
+	}
+	return &vcrTransport{base: base, provider: providerName, state: cassette}
+}
+
+type vcrTransport struct {
+	base     http.RoundTripper
+	provider string
+	state    *cassetteState
+}
+
+func (t *vcrTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	recorded := &Interaction{
+		Provider: t.provider,
+		Method:   req.Method,
+		URL:      scrub(req.URL.String()),
+		Body:     scrubBody(req.Header.Get("Content-Type"), body),
+	}
+
+	if t.state.mode == CassetteReplay {
+		cassetteMu.Lock()
+		defer cassetteMu.Unlock()
+		for _, i := range t.state.interactions {
+			if !i.used && i.Provider == recorded.Provider && i.Method == recorded.Method && i.URL == recorded.URL && bytes.Equal(i.Body, recorded.Body) {
+				i.used = true
+				return &http.Response{
+					Status:        fmt.Sprintf("%d %s", i.Status, http.StatusText(i.Status)),
+					StatusCode:    i.Status,
+					Proto:         "HTTP/1.1",
+					ProtoMajor:    1,
+					ProtoMinor:    1,
+					Header:        i.Header.Clone(),
+					Body:          io.NopCloser(bytes.NewReader(i.Response)),
+					ContentLength: int64(len(i.Response)),
+					Request:       req,
+				}, nil
+			}
+		}
+		return nil, fmt.Errorf("no recorded interaction for %s %s", recorded.Method, recorded.URL)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	recorded.Status = resp.StatusCode
+	recorded.Header = http.Header{}
+	for _, name := range []string{"Content-Type", "Retry-After"} {
+		if v := resp.Header.Values(name); len(v) > 0 {
+			recorded.Header[name] = v
+		}
+	}
+	recorded.Response = scrubBody(resp.Header.Get("Content-Type"), respBody)
+	cassetteMu.Lock()
+	t.state.interactions = append(t.state.interactions, recorded)
+	cassetteMu.Unlock()
+	return resp, nil
+}
+
+// scrub replaces tokens, keys and secrets in s.
+func scrub(s string) string {
+	s = secretFields.ReplaceAllString(s, `$1"REDACTED"`)
+	return secretParams.ReplaceAllString(s, "$1=REDACTED")
+}
+
+// scrubBody scrubs text bodies. Binary bodies like images are kept as is.
+func scrubBody(contentType string, body []byte) []byte {
+	if len(body) == 0 {
+		return nil
+	}
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	if !strings.Contains(contentType, "json") && !strings.HasPrefix(contentType, "text/") && !strings.Contains(contentType, "form") {
+		return body
+	}
+	return []byte(scrub(string(body)))
+}
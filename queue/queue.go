@@ -0,0 +1,227 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package queue keeps prompts that are generated later, e.g. when the network
+// is back.
+package queue
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/filelock"
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+// Item is a queued prompt.
+type Item struct {
+	// ID identifies the item, it is set by Add. Items queued by older
+	// versions have none.
+	ID     string    `json:"id,omitempty"`
+	Added  time.Time `json:"added"`
+	Prompt string    `json:"prompt"`
+	// Model is the model to generate with, the default model if empty.
+	Model string `json:"model,omitempty"`
+	// Settings are the setting values that differ from the model's.
+	Settings map[string]string `json:"settings,omitempty"`
+	// Size is the output size, see package sizes.
+	Size string `json:"size,omitempty"`
+	// Priority orders the queue, items with a higher priority are generated
+	// first.
+	Priority int `json:"priority,omitempty"`
+}
+
+var mu sync.Mutex
+
+// same reports whether a and b are the same queued item.
+func (a Item) same(b Item) bool {
+	if a.ID != "" || b.ID != "" {
+		return a.ID == b.ID
+	}
+	return a.Added.Equal(b.Added) && a.Prompt == b.Prompt
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func getQueueFilePath() (string, error) {
+	dataDir, err := providers.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "queue.json"), nil
+}
+
+// lock takes the queue lock shared with other processes, e.g. a 'queue add'
+// while the queue is run. mu must be held.
+func lock() (func(), error) {
+	queueFile, err := getQueueFilePath()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(queueFile), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create data dir: %w", err)
+	}
+	unlock, err := filelock.Lock(queueFile + ".lock")
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock queue: %w", err)
+	}
+	return unlock, nil
+}
+
+// Read returns the queued items by priority, oldest first.
+func Read() ([]Item, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return read()
+}
+
+func read() ([]Item, error) {
+	queueFile, err := getQueueFilePath()
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(queueFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queue: %w", err)
+	}
+	var items []Item
+	if err := json.Unmarshal(b, &items); err != nil {
+		return nil, fmt.Errorf("failed to decode queue: %w", err)
+	}
+	return items, nil
+}
+
+func write(items []Item) error {
+	queueFile, err := getQueueFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(queueFile), 0700); err != nil {
+		return fmt.Errorf("failed to create data dir: %w", err)
+	}
+	b, err := json.MarshalIndent(items, "", "\t")
+	if err != nil {
+		return fmt.Errorf("failed to encode queue: %w", err)
+	}
+	tmp := queueFile + ".tmp"
+	if err := os.WriteFile(tmp, b, 0600); err != nil {
+		return fmt.Errorf("failed to write queue: %w", err)
+	}
+	if err := os.Rename(tmp, queueFile); err != nil {
+		return fmt.Errorf("failed to write queue: %w", err)
+	}
+	return nil
+}
+
+// Add adds an item to the queue after the items with the same or a higher
+// priority.
+func Add(item Item) error {
+	if item.ID == "" {
+		item.ID = newID()
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	unlock, err := lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	items, err := read()
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(items, func(other Item) bool { return other.Priority < item.Priority })
+	if i < 0 {
+		i = len(items)
+	}
+	return write(slices.Insert(items, i, item))
+}
+
+// Remove removes the item at index i of Read from the queue and returns it.
+func Remove(i int) (Item, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	unlock, err := lock()
+	if err != nil {
+		return Item{}, err
+	}
+	defer unlock()
+	items, err := read()
+	if err != nil {
+		return Item{}, err
+	}
+	if i < 0 || i >= len(items) {
+		return Item{}, fmt.Errorf("no queued prompt %d", i+1)
+	}
+	item := items[i]
+	return item, write(slices.Delete(items, i, i+1))
+}
+
+// Done removes the generated item from the queue. Items that were added or
+// removed since the queue was read are kept as they are.
+func Done(item Item) error {
+	mu.Lock()
+	defer mu.Unlock()
+	unlock, err := lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	items, err := read()
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(items, item.same)
+	if i < 0 {
+		// cancelled while it was generated
+		return nil
+	}
+	return write(slices.Delete(items, i, i+1))
+}
+
+// Queued reports whether the item is still queued. It isn't once it was
+// cancelled or generated by another run.
+func Queued(item Item) (bool, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	unlock, err := lock()
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+	items, err := read()
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(items, item.same), nil
+}
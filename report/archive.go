@@ -0,0 +1,167 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package report
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bloodmagesoftware/climage/sidecar"
+	"github.com/bloodmagesoftware/climage/tiles"
+)
+
+// manifest is the manifest.json of an archive. The files are paths in the
+// archive.
+type manifest struct {
+	Model    string            `json:"model"`
+	Style    string            `json:"style,omitempty"`
+	Settings map[string]string `json:"settings,omitempty"`
+	Prompts  []manifestPrompt  `json:"prompts"`
+	Files    []string          `json:"files,omitempty"`
+}
+
+type manifestPrompt struct {
+	Prompt string   `json:"prompt"`
+	Files  []string `json:"files"`
+	// Filtered is the number of images removed by the safety filter.
+	Filtered int `json:"filtered,omitempty"`
+}
+
+// WriteArchive bundles the images of the run with the files that were written
+// next to them and the extra files into a zip file with a manifest.json and
+// the report.md if it isn't empty. The images are stored in the images
+// folder, the extra files at the top.
+func WriteArchive(archivePath string, run Run, extra []string, report []byte) error {
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+		return fmt.Errorf("failed to create archive dir: %w", err)
+	}
+	tmp := archivePath + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer os.Remove(tmp)
+	defer f.Close()
+	zw := zip.NewWriter(f)
+
+	m := manifest{Model: run.Model, Style: run.Style, Settings: run.Settings}
+	for _, p := range run.Prompts {
+		mp := manifestPrompt{Prompt: p.Prompt, Files: []string{}}
+		for _, img := range p.Images {
+			if img.Safety.Filtered {
+				mp.Filtered++
+			}
+			if img.Path == "" {
+				continue
+			}
+			// the files written next to the image, if they exist, the icon
+			// sets included
+			base := strings.TrimSuffix(img.Path, filepath.Ext(img.Path))
+			for _, path := range []string{img.Path, sidecar.Path(img.Path), tiles.PreviewPath(img.Path), base + "_icons"} {
+				names, err := AddToZip(zw, path, "images/"+filepath.Base(path))
+				if err != nil {
+					return err
+				}
+				mp.Files = append(mp.Files, names...)
+			}
+		}
+		m.Prompts = append(m.Prompts, mp)
+	}
+	for _, path := range extra {
+		names, err := AddToZip(zw, path, filepath.Base(path))
+		if err != nil {
+			return err
+		}
+		m.Files = append(m.Files, names...)
+	}
+	if len(report) > 0 {
+		w, err := zw.Create("report.md")
+		if err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		if _, err := w.Write(report); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		m.Files = append(m.Files, "report.md")
+	}
+
+	w, err := zw.Create("manifest.json")
+	if err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(m); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := os.Rename(tmp, archivePath); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// AddToZip adds the file or the files in the directory at path to the zip
+// file as name and returns the added names. A missing path adds nothing.
+func AddToZip(zw *zip.Writer, path string, name string) ([]string, error) {
+	var names []string
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && p == path {
+			return filepath.SkipAll
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(path, p)
+		if err != nil {
+			return err
+		}
+		n := filepath.ToSlash(filepath.Join(name, rel))
+		src, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		w, err := zw.Create(n)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(w, src); err != nil {
+			return err
+		}
+		names = append(names, n)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add %s to archive: %w", path, err)
+	}
+	return names, nil
+}
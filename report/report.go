@@ -0,0 +1,128 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package report writes the Markdown report and the zip archive of a batch
+// run, e.g. to hand the generated assets to a client.
+package report
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+// Run is a batch run.
+type Run struct {
+	// PromptsPath is the prompts file, its name is the title of the report.
+	PromptsPath string
+	Model       string
+	// Style is the name of the style preset, empty if none was used.
+	Style string
+	// Settings are the setting values of the run.
+	Settings map[string]string
+	Prompts  []Prompt
+}
+
+// Prompt is a prompt of a batch run with its images.
+type Prompt struct {
+	Prompt string
+	Images []providers.Image
+}
+
+// Write writes the Markdown report of the run. The images are linked relative
+// to the report, so the report can be moved together with them.
+func Write(reportPath string, run Run) error {
+	reportDir, err := filepath.Abs(filepath.Dir(reportPath))
+	if err != nil {
+		return fmt.Errorf("failed to get report dir: %w", err)
+	}
+	report := Markdown(run, func(path string) string {
+		if abs, err := filepath.Abs(path); err == nil {
+			if rel, err := filepath.Rel(reportDir, abs); err == nil {
+				return rel
+			}
+		}
+		return path
+	})
+	if err := os.MkdirAll(reportDir, 0755); err != nil {
+		return fmt.Errorf("failed to create report dir: %w", err)
+	}
+	if err := os.WriteFile(reportPath, report, 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// Markdown returns the Markdown report of the run, the images are linked with
+// the paths returned by link.
+func Markdown(run Run, link func(path string) string) []byte {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", filepath.Base(run.PromptsPath))
+	fmt.Fprintf(&sb, "Generated %s with `%s`.\n\n", time.Now().Format(time.DateTime), run.Model)
+	if run.Style != "" {
+		fmt.Fprintf(&sb, "Style: %s\n\n", run.Style)
+	}
+	if len(run.Settings) > 0 {
+		sb.WriteString("| Setting | Value |\n| --- | --- |\n")
+		for _, name := range slices.Sorted(maps.Keys(run.Settings)) {
+			fmt.Fprintf(&sb, "| %s | %s |\n", markdownLine(name), markdownLine(run.Settings[name]))
+		}
+		sb.WriteString("\n")
+	}
+	for i, p := range run.Prompts {
+		fmt.Fprintf(&sb, "## %d. %s\n\n", i+1, markdownLine(p.Prompt))
+		filtered := 0
+		for _, img := range p.Images {
+			if img.Safety.Filtered {
+				filtered++
+				continue
+			}
+			switch {
+			case img.Path != "":
+				fmt.Fprintf(&sb, "![%s](<%s>)\n", markdownLine(filepath.Base(img.Path)), filepath.ToSlash(link(img.Path)))
+			case len(img.Uploads) > 0:
+				// not kept locally
+				fmt.Fprintf(&sb, "![%s](<%s>)\n", markdownLine(path.Base(img.Uploads[0])), img.Uploads[0])
+			default:
+				continue
+			}
+			if img.Seed != nil {
+				fmt.Fprintf(&sb, "\nSeed %d\n", *img.Seed)
+			}
+			sb.WriteString("\n")
+		}
+		if filtered > 0 {
+			fmt.Fprintf(&sb, "%d images removed by the safety filter.\n\n", filtered)
+		}
+	}
+	return []byte(sb.String())
+}
+
+// markdownLine escapes the characters of s that Markdown would interpret.
+func markdownLine(s string) string {
+	return strings.NewReplacer(
+		"\\", "\\\\", "*", "\\*", "_", "\\_", "`", "\\`", "[", "\\[", "]", "\\]", "<", "\\<", ">", "\\>", "#", "\\#", "|", "\\|",
+	).Replace(s)
+}
@@ -0,0 +1,254 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/filelock"
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+// Job is a recurring generation.
+type Job struct {
+	Name string `json:"name"`
+	// Cron is the cron expression of the job, see Parse.
+	Cron   string `json:"cron"`
+	Prompt string `json:"prompt"`
+	// Model is the model to generate with, the default model if empty.
+	Model string `json:"model,omitempty"`
+	// Settings are the setting values that differ from the model's.
+	Settings map[string]string `json:"settings,omitempty"`
+	// Size is the output size, see package sizes.
+	Size string `json:"size,omitempty"`
+	// Wallpaper sets the generated image as the desktop background.
+	Wallpaper bool      `json:"wallpaper,omitempty"`
+	Added     time.Time `json:"added"`
+	// LastRun is when the job last ran, the zero time if it never ran.
+	LastRun time.Time `json:"last_run,omitzero"`
+}
+
+// Next returns when the job runs next. Runs that were missed, e.g. because
+// the computer was off, are caught up once.
+func (j Job) Next() (time.Time, error) {
+	c, err := Parse(j.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+	since := j.Added
+	if j.LastRun.After(since) {
+		since = j.LastRun
+	}
+	return c.Next(since), nil
+}
+
+// Due reports if the job should run at now.
+func (j Job) Due(now time.Time) bool {
+	next, err := j.Next()
+	return err == nil && !next.IsZero() && !next.After(now)
+}
+
+var mu sync.Mutex
+
+func getScheduleFilePath() (string, error) {
+	dataDir, err := providers.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "schedule.json"), nil
+}
+
+// lock takes the schedule lock shared with other processes, e.g. a daemon
+// running alongside a cron job. mu must be held.
+func lock() (func(), error) {
+	scheduleFile, err := getScheduleFilePath()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(scheduleFile), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create data dir: %w", err)
+	}
+	unlock, err := filelock.Lock(scheduleFile + ".lock")
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock schedule: %w", err)
+	}
+	return unlock, nil
+}
+
+// Read returns the scheduled jobs.
+func Read() ([]Job, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return read()
+}
+
+func read() ([]Job, error) {
+	scheduleFile, err := getScheduleFilePath()
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(scheduleFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedule: %w", err)
+	}
+	var jobs []Job
+	if err := json.Unmarshal(b, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to decode schedule: %w", err)
+	}
+	return jobs, nil
+}
+
+// Write replaces the scheduled jobs.
+func Write(jobs []Job) error {
+	mu.Lock()
+	defer mu.Unlock()
+	unlock, err := lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return write(jobs)
+}
+
+func write(jobs []Job) error {
+	scheduleFile, err := getScheduleFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(scheduleFile), 0700); err != nil {
+		return fmt.Errorf("failed to create data dir: %w", err)
+	}
+	b, err := json.MarshalIndent(jobs, "", "\t")
+	if err != nil {
+		return fmt.Errorf("failed to encode schedule: %w", err)
+	}
+	tmp := scheduleFile + ".tmp"
+	if err := os.WriteFile(tmp, b, 0600); err != nil {
+		return fmt.Errorf("failed to write schedule: %w", err)
+	}
+	if err := os.Rename(tmp, scheduleFile); err != nil {
+		return fmt.Errorf("failed to write schedule: %w", err)
+	}
+	return nil
+}
+
+// Add adds a job. The name must be unique.
+func Add(job Job) error {
+	mu.Lock()
+	defer mu.Unlock()
+	unlock, err := lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	jobs, err := read()
+	if err != nil {
+		return err
+	}
+	for _, j := range jobs {
+		if j.Name == job.Name {
+			return fmt.Errorf("job %q already exists", job.Name)
+		}
+	}
+	return write(append(jobs, job))
+}
+
+// Remove removes the job with the name.
+func Remove(name string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	unlock, err := lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	jobs, err := read()
+	if err != nil {
+		return err
+	}
+	for i, j := range jobs {
+		if j.Name == name {
+			return write(append(jobs[:i], jobs[i+1:]...))
+		}
+	}
+	return fmt.Errorf("job %q doesn't exist", name)
+}
+
+// SetLastRun records that the job with the name ran at t.
+func SetLastRun(name string, t time.Time) error {
+	mu.Lock()
+	defer mu.Unlock()
+	unlock, err := lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	jobs, err := read()
+	if err != nil {
+		return err
+	}
+	for i := range jobs {
+		if jobs[i].Name == name {
+			jobs[i].LastRun = t
+			return write(jobs)
+		}
+	}
+	return fmt.Errorf("job %q doesn't exist", name)
+}
+
+// Claim records that the job with the name runs at now if it is due. Claiming
+// before generating keeps other processes from running the job as well. It
+// returns the job as it was before the claim and false if the job isn't due.
+func Claim(name string, now time.Time) (Job, bool, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	unlock, err := lock()
+	if err != nil {
+		return Job{}, false, err
+	}
+	defer unlock()
+	jobs, err := read()
+	if err != nil {
+		return Job{}, false, err
+	}
+	for i := range jobs {
+		if jobs[i].Name == name {
+			job := jobs[i]
+			if !job.Due(now) {
+				return job, false, nil
+			}
+			jobs[i].LastRun = now
+			if err := write(jobs); err != nil {
+				return Job{}, false, err
+			}
+			return job, true, nil
+		}
+	}
+	// removed by another process
+	return Job{}, false, nil
+}
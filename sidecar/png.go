@@ -0,0 +1,129 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package sidecar
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// maxTextChunkSize bounds the size of text chunks that are read.
+const maxTextChunkSize = 16 << 20
+
+// ErrNotPNG is returned by ReadPNGText for files that are not PNG images.
+var ErrNotPNG = errors.New("not a PNG image")
+
+// ReadPNGText returns the text chunks embedded in a PNG image, e.g. the
+// "parameters" other generation tools write.
+func ReadPNGText(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image: %w", err)
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+
+	signature := make([]byte, len(pngSignature))
+	if _, err := io.ReadFull(r, signature); err != nil || !bytes.Equal(signature, pngSignature) {
+		return nil, ErrNotPNG
+	}
+	text := make(map[string]string)
+	for {
+		var header struct {
+			Length uint32
+			Type   [4]byte
+		}
+		if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+			return nil, fmt.Errorf("failed to read PNG chunk: %w", err)
+		}
+		chunkType := string(header.Type[:])
+		if chunkType == "IEND" {
+			return text, nil
+		}
+		isText := chunkType == "tEXt" || chunkType == "zTXt" || chunkType == "iTXt"
+		if !isText || header.Length > maxTextChunkSize {
+			// skip the data and the CRC
+			if _, err := r.Discard(int(header.Length) + 4); err != nil {
+				return nil, fmt.Errorf("failed to read PNG chunk: %w", err)
+			}
+			continue
+		}
+		data := make([]byte, header.Length+4)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, fmt.Errorf("failed to read PNG chunk: %w", err)
+		}
+		if key, value, ok := parseTextChunk(chunkType, data[:header.Length]); ok {
+			text[key] = value
+		}
+	}
+}
+
+// parseTextChunk decodes a tEXt, zTXt or iTXt chunk. Malformed chunks are
+// skipped.
+func parseTextChunk(chunkType string, data []byte) (string, string, bool) {
+	key, rest, ok := bytes.Cut(data, []byte{0})
+	if !ok {
+		return "", "", false
+	}
+	switch chunkType {
+	case "tEXt":
+		return string(key), string(rest), true
+	case "zTXt":
+		// compression method, then the compressed text
+		if len(rest) < 1 {
+			return "", "", false
+		}
+		value, err := inflate(rest[1:])
+		return string(key), value, err == nil
+	case "iTXt":
+		// compression flag and method, language tag and translated keyword
+		if len(rest) < 2 {
+			return "", "", false
+		}
+		compressed := rest[0] == 1
+		parts := bytes.SplitN(rest[2:], []byte{0}, 3)
+		if len(parts) != 3 {
+			return "", "", false
+		}
+		if !compressed {
+			return string(key), string(parts[2]), true
+		}
+		value, err := inflate(parts[2])
+		return string(key), value, err == nil
+	}
+	return "", "", false
+}
+
+func inflate(b []byte) (string, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return "", err
+	}
+	defer zr.Close()
+	out, err := io.ReadAll(zr)
+	return string(out), err
+}
@@ -0,0 +1,89 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package sidecar writes and reads the metadata files saved next to generated
+// images, so asset pipelines can track where an image came from.
+package sidecar
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+// Metadata describes how an image was generated.
+type Metadata struct {
+	// Image is the file name of the image.
+	Image  string    `json:"image"`
+	Time   time.Time `json:"time"`
+	Model  string    `json:"model"`
+	Prompt string    `json:"prompt"`
+	// Settings are the model settings the image was generated with.
+	Settings map[string]string `json:"settings,omitempty"`
+	// Seed is the seed reported by the provider.
+	Seed *int64 `json:"seed,omitempty"`
+	// ResponseID identifies the provider's response, e.g. the job id.
+	ResponseID string `json:"response_id,omitempty"`
+	// Cost is the estimated price in USD.
+	Cost float64 `json:"cost,omitempty"`
+	// Size is the output size the image was fitted to, see package sizes.
+	Size string `json:"size,omitempty"`
+	// Tiled is set if the image was made tileable.
+	Tiled bool `json:"tiled,omitempty"`
+	// Watermark is the invisible watermark of the image, e.g. "SynthID".
+	Watermark string `json:"watermark,omitempty"`
+}
+
+// Path returns the path of the sidecar of an image, the image path with a
+// .json extension.
+func Path(imagePath string) string {
+	return strings.TrimSuffix(imagePath, filepath.Ext(imagePath)) + ".json"
+}
+
+// Write saves the metadata next to the image and returns the sidecar path.
+func Write(imagePath string, m Metadata) (string, error) {
+	m.Image = filepath.Base(imagePath)
+	b, err := json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode sidecar: %w", err)
+	}
+	sidecarPath := Path(imagePath)
+	// readers like the web gallery must never see a half written sidecar
+	if err := providers.WriteFileAtomic(sidecarPath, append(b, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write sidecar: %w", err)
+	}
+	return sidecarPath, nil
+}
+
+// Read loads a sidecar file.
+func Read(path string) (Metadata, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("failed to read sidecar: %w", err)
+	}
+	var m Metadata
+	if err := json.Unmarshal(b, &m); err != nil {
+		return Metadata{}, fmt.Errorf("failed to decode sidecar %s: %w", path, err)
+	}
+	return m, nil
+}
@@ -0,0 +1,103 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package sign signs generated files with GPG or minisign, so teams can prove
+// which outputs came from their pipeline. The signatures are detached files
+// next to the signed file and are checked with the tools themselves, e.g.
+// "gpg --verify image.png.asc" or "minisign -V -p key.pub -m image.png".
+package sign
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Tools that sign files.
+const (
+	ToolGPG      = "gpg"
+	ToolMinisign = "minisign"
+)
+
+// Options configure signing. The zero value disables it.
+type Options struct {
+	// Tool is ToolGPG or ToolMinisign.
+	Tool string `json:"tool,omitempty"`
+	// Key is the GPG key ID or user ID to sign with, the default key is used
+	// if empty, or the path of the minisign secret key, which must not be
+	// password protected as there is nobody to enter it.
+	Key string `json:"key,omitempty"`
+}
+
+// Enabled reports whether files are signed.
+func (o Options) Enabled() bool {
+	return o.Tool != ""
+}
+
+// Validate checks the options.
+func (o Options) Validate() error {
+	switch o.Tool {
+	case "", ToolGPG:
+	case ToolMinisign:
+		if o.Key == "" {
+			return fmt.Errorf("signing with minisign needs the path of the secret key")
+		}
+	default:
+		return fmt.Errorf("unknown signing tool %q, expected %q or %q", o.Tool, ToolGPG, ToolMinisign)
+	}
+	return nil
+}
+
+// SignaturePath returns the path of the signature of a file, the file path
+// with .asc for GPG or .minisig for minisign appended.
+func (o Options) SignaturePath(path string) string {
+	if o.Tool == ToolMinisign {
+		return path + ".minisig"
+	}
+	return path + ".asc"
+}
+
+// File signs the file and returns the path of the signature. An existing
+// signature is replaced.
+func File(ctx context.Context, o Options, path string) (string, error) {
+	if err := o.Validate(); err != nil {
+		return "", err
+	}
+	sigPath := o.SignaturePath(path)
+	var c *exec.Cmd
+	switch o.Tool {
+	case ToolGPG:
+		args := []string{"--batch", "--yes", "--armor", "--detach-sign", "--output", sigPath}
+		if o.Key != "" {
+			args = append(args, "--local-user", o.Key)
+		}
+		c = exec.CommandContext(ctx, "gpg", append(args, "--", path)...)
+	case ToolMinisign:
+		c = exec.CommandContext(ctx, "minisign", "-S", "-s", o.Key, "-x", sigPath, "-m", path)
+	default:
+		return "", fmt.Errorf("signing is disabled")
+	}
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("%s failed to sign %s: %w: %s", o.Tool, path, err, strings.TrimSpace(stderr.String()))
+	}
+	return sigPath, nil
+}
@@ -0,0 +1,174 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package sizes
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"os"
+
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+// Fit crops the image file to the aspect ratio of the size, centered, and
+// scales it to the exact size. PNG and JPEG files are supported, the file
+// keeps its format.
+func Fit(imagePath string, size Size) error {
+	b, err := os.ReadFile(imagePath)
+	if err != nil {
+		return fmt.Errorf("failed to read image: %w", err)
+	}
+	src, format, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("failed to decode image: %w", err)
+	}
+	dst := Resize(src, size)
+
+	var buf bytes.Buffer
+	switch format {
+	case "png":
+		err = png.Encode(&buf, dst)
+	case "jpeg":
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 95})
+	default:
+		return fmt.Errorf("can't resize %s images", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+	if err := providers.WriteFileAtomic(imagePath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write image: %w", err)
+	}
+	return nil
+}
+
+// Resize crops the image to the aspect ratio of the size, centered, and
+// scales it to the exact size.
+func Resize(src image.Image, size Size) *image.NRGBA {
+	return resize(src, cropRect(src.Bounds(), size), size.Width, size.Height)
+}
+
+// cropRect returns the largest centered part of r with the aspect ratio of
+// the size.
+func cropRect(r image.Rectangle, size Size) image.Rectangle {
+	w, h := r.Dx(), r.Dy()
+	if w*size.Height > h*size.Width {
+		cw := h * size.Width / size.Height
+		x := r.Min.X + (w-cw)/2
+		return image.Rect(x, r.Min.Y, x+cw, r.Max.Y)
+	}
+	ch := w * size.Height / size.Width
+	y := r.Min.Y + (h-ch)/2
+	return image.Rect(r.Min.X, y, r.Max.X, y+ch)
+}
+
+// resize scales the part r of src to width x height with a triangle filter
+// that is widened when shrinking, so downscaled images don't alias.
+func resize(src image.Image, r image.Rectangle, width, height int) *image.NRGBA {
+	sw, sh := r.Dx(), r.Dy()
+	// premultiplied RGBA
+	px := make([]float64, sw*sh*4)
+	for y := range sh {
+		for x := range sw {
+			cr, cg, cb, ca := src.At(r.Min.X+x, r.Min.Y+y).RGBA()
+			i := (y*sw + x) * 4
+			px[i], px[i+1], px[i+2], px[i+3] = float64(cr), float64(cg), float64(cb), float64(ca)
+		}
+	}
+
+	// horizontal pass: sw x sh to width x sh
+	tmp := make([]float64, width*sh*4)
+	for x, ws := range weights(sw, width) {
+		for y := range sh {
+			for _, w := range ws {
+				si, di := (y*sw+w.index)*4, (y*width+x)*4
+				for c := range 4 {
+					tmp[di+c] += px[si+c] * w.weight
+				}
+			}
+		}
+	}
+
+	// vertical pass: width x sh to width x height
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y, ws := range weights(sh, height) {
+		for x := range width {
+			var sum [4]float64
+			for _, w := range ws {
+				si := (w.index*width + x) * 4
+				for c := range 4 {
+					sum[c] += tmp[si+c] * w.weight
+				}
+			}
+			di := dst.PixOffset(x, y)
+			a := clamp(sum[3])
+			if a == 0 {
+				continue
+			}
+			for c := range 3 {
+				dst.Pix[di+c] = uint8(clamp(sum[c]*0xffff/float64(a)) >> 8)
+			}
+			dst.Pix[di+3] = uint8(a >> 8)
+		}
+	}
+	return dst
+}
+
+type weight struct {
+	index  int
+	weight float64
+}
+
+// weights returns the normalized source weights of every destination pixel.
+func weights(srcLen, dstLen int) [][]weight {
+	scale := float64(srcLen) / float64(dstLen)
+	support := math.Max(1, scale)
+	all := make([][]weight, dstLen)
+	for d := range dstLen {
+		center := (float64(d)+0.5)*scale - 0.5
+		var ws []weight
+		var total float64
+		for i := int(math.Ceil(center - support)); i <= int(math.Floor(center+support)); i++ {
+			w := 1 - math.Abs(float64(i)-center)/support
+			if w <= 0 {
+				continue
+			}
+			ws = append(ws, weight{index: min(max(i, 0), srcLen-1), weight: w})
+			total += w
+		}
+		if total == 0 {
+			// the center is exactly on a source pixel
+			ws = []weight{{index: min(max(int(math.Round(center)), 0), srcLen-1), weight: 1}}
+			total = 1
+		}
+		for i := range ws {
+			ws[i].weight /= total
+		}
+		all[d] = ws
+	}
+	return all
+}
+
+func clamp(v float64) uint32 {
+	return uint32(min(max(math.Round(v), 0), 0xffff))
+}
@@ -0,0 +1,112 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package sizes has the named output sizes for social media and wallpapers
+// and fits generated images to them.
+package sizes
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Size is an exact output size in pixels.
+type Size struct {
+	// Name is the preset name, empty for custom sizes.
+	Name   string
+	Width  int
+	Height int
+}
+
+// Presets are the built-in output sizes.
+var Presets = []Size{
+	{"og-image", 1200, 630},
+	{"twitter-post", 1600, 900},
+	{"twitter-header", 1500, 500},
+	{"instagram-square", 1080, 1080},
+	{"instagram-portrait", 1080, 1350},
+	{"instagram-story", 1080, 1920},
+	{"facebook-cover", 1640, 624},
+	{"linkedin-post", 1200, 627},
+	{"linkedin-banner", 1584, 396},
+	{"pinterest-pin", 1000, 1500},
+	{"yt-thumbnail", 1280, 720},
+	{"yt-banner", 2560, 1440},
+	{"wallpaper-hd", 1920, 1080},
+	{"wallpaper-4k", 3840, 2160},
+	{"phone-wallpaper", 1170, 2532},
+}
+
+// maxDimension limits custom sizes.
+const maxDimension = 8192
+
+// Parse returns the preset with the name or the custom size "WIDTHxHEIGHT".
+func Parse(s string) (Size, error) {
+	for _, p := range Presets {
+		if p.Name == s {
+			return p, nil
+		}
+	}
+	w, h, ok := strings.Cut(strings.ToLower(s), "x")
+	if ok {
+		width, errW := strconv.Atoi(w)
+		height, errH := strconv.Atoi(h)
+		if errW == nil && errH == nil {
+			if width < 1 || height < 1 || width > maxDimension || height > maxDimension {
+				return Size{}, fmt.Errorf("invalid size %q, width and height must be between 1 and %d", s, maxDimension)
+			}
+			return Size{Width: width, Height: height}, nil
+		}
+	}
+	return Size{}, fmt.Errorf("unknown size %q, use a preset like og-image or WIDTHxHEIGHT, see 'climage sizes'", s)
+}
+
+// String returns the preset name or "WIDTHxHEIGHT".
+func (s Size) String() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return fmt.Sprintf("%dx%d", s.Width, s.Height)
+}
+
+// NearestAspectRatio returns the option like "16:9" closest to the aspect
+// ratio of the size, or "" if no option is a valid ratio.
+func (s Size) NearestAspectRatio(options []string) string {
+	want := math.Log(float64(s.Width) / float64(s.Height))
+	nearest := ""
+	best := math.Inf(1)
+	for _, option := range options {
+		w, h, ok := strings.Cut(option, ":")
+		if !ok {
+			continue
+		}
+		fw, errW := strconv.ParseFloat(w, 64)
+		fh, errH := strconv.ParseFloat(h, 64)
+		if errW != nil || errH != nil || fw <= 0 || fh <= 0 {
+			continue
+		}
+		// compared on a log scale so 2:1 and 1:2 are as far from 1:1
+		if d := math.Abs(math.Log(fw/fh) - want); d < best {
+			best = d
+			nearest = option
+		}
+	}
+	return nearest
+}
@@ -0,0 +1,153 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package sprites packs images into a sprite sheet with a JSON atlas.
+package sprites
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	// decoders for the generated images
+	_ "image/jpeg"
+)
+
+// padding is the transparent space between sprites, so sampling at the edge
+// of a sprite doesn't bleed into its neighbour.
+const padding = 2
+
+// Sprite is the position of an image in the sheet.
+type Sprite struct {
+	Name   string `json:"name"`
+	Prompt string `json:"prompt,omitempty"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Width  int    `json:"w"`
+	Height int    `json:"h"`
+}
+
+// Atlas describes a sprite sheet.
+type Atlas struct {
+	Image   string   `json:"image"`
+	Width   int      `json:"width"`
+	Height  int      `json:"height"`
+	Sprites []Sprite `json:"sprites"`
+}
+
+// Entry is an image to pack.
+type Entry struct {
+	Path   string
+	Prompt string
+}
+
+// AtlasPath returns the path of the atlas of a sprite sheet.
+func AtlasPath(sheetPath string) string {
+	return strings.TrimSuffix(sheetPath, filepath.Ext(sheetPath)) + ".json"
+}
+
+// Write packs the images into a PNG sprite sheet at sheetPath and writes the
+// atlas next to it, see AtlasPath. Sprites are named after their files.
+func Write(sheetPath string, entries []Entry) (Atlas, error) {
+	images := make([]image.Image, len(entries))
+	sprites := make([]Sprite, len(entries))
+	for i, e := range entries {
+		f, err := os.Open(e.Path)
+		if err != nil {
+			return Atlas{}, fmt.Errorf("failed to open image: %w", err)
+		}
+		img, _, err := image.Decode(f)
+		f.Close()
+		if err != nil {
+			return Atlas{}, fmt.Errorf("failed to decode %s: %w", e.Path, err)
+		}
+		images[i] = img
+		sprites[i] = Sprite{
+			Name:   strings.TrimSuffix(filepath.Base(e.Path), filepath.Ext(e.Path)),
+			Prompt: e.Prompt,
+			Width:  img.Bounds().Dx(),
+			Height: img.Bounds().Dy(),
+		}
+	}
+
+	atlas := Atlas{Image: filepath.Base(sheetPath), Sprites: sprites}
+	atlas.Width, atlas.Height = pack(sprites)
+	sheet := image.NewNRGBA(image.Rect(0, 0, atlas.Width, atlas.Height))
+	for i, s := range sprites {
+		r := image.Rect(s.X, s.Y, s.X+s.Width, s.Y+s.Height)
+		draw.Draw(sheet, r, images[i], images[i].Bounds().Min, draw.Src)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, sheet); err != nil {
+		return Atlas{}, fmt.Errorf("failed to encode sprite sheet: %w", err)
+	}
+	if err := os.WriteFile(sheetPath, buf.Bytes(), 0644); err != nil {
+		return Atlas{}, fmt.Errorf("failed to write sprite sheet: %w", err)
+	}
+	b, err := json.MarshalIndent(atlas, "", "\t")
+	if err != nil {
+		return Atlas{}, fmt.Errorf("failed to encode atlas: %w", err)
+	}
+	if err := os.WriteFile(AtlasPath(sheetPath), b, 0644); err != nil {
+		return Atlas{}, fmt.Errorf("failed to write atlas: %w", err)
+	}
+	return atlas, nil
+}
+
+// pack places the sprites in rows, tallest first, aiming for a square sheet,
+// and returns the size of the sheet. The sprites keep their order.
+func pack(sprites []Sprite) (int, int) {
+	area, widest := 0, 0
+	for _, s := range sprites {
+		area += (s.Width + padding) * (s.Height + padding)
+		widest = max(widest, s.Width)
+	}
+	maxWidth := max(widest, int(math.Ceil(math.Sqrt(float64(area)))))
+
+	order := make([]int, len(sprites))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return sprites[b].Height - sprites[a].Height
+	})
+
+	x, y, rowHeight, width := 0, 0, 0, 0
+	for _, i := range order {
+		s := &sprites[i]
+		if x > 0 && x+s.Width > maxWidth {
+			x = 0
+			y += rowHeight + padding
+			rowHeight = 0
+		}
+		s.X, s.Y = x, y
+		x += s.Width + padding
+		rowHeight = max(rowHeight, s.Height)
+		width = max(width, s.X+s.Width)
+	}
+	return width, y + rowHeight
+}
@@ -0,0 +1,71 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package styles
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Style is a curated look that is added to prompts.
+type Style struct {
+	Name string
+	// Modifier is appended to the prompt.
+	Modifier string
+}
+
+// Presets are the shipped styles.
+var Presets = []Style{
+	{
+		Name:     "photorealistic",
+		Modifier: "photorealistic, natural lighting, shot on a full frame camera with a 50mm lens, sharp focus, fine detail, realistic textures",
+	},
+	{
+		Name:     "watercolor",
+		Modifier: "watercolor painting, soft washes of translucent color, visible paper texture, loose brushwork, bleeding edges",
+	},
+	{
+		Name:     "isometric",
+		Modifier: "isometric view, orthographic projection from a 30 degree angle, clean geometry, soft even lighting, miniature diorama",
+	},
+	{
+		Name:     "pixel-art",
+		Modifier: "pixel art, 16-bit retro video game style, limited color palette, crisp hard pixel edges, no anti-aliasing",
+	},
+}
+
+// Get returns the style with the name.
+func Get(name string) (Style, error) {
+	for _, s := range Presets {
+		if strings.EqualFold(s.Name, name) {
+			return s, nil
+		}
+	}
+	names := make([]string, len(Presets))
+	for i, s := range Presets {
+		names[i] = s.Name
+	}
+	return Style{}, fmt.Errorf("unknown style %q, expected one of %s", name, strings.Join(names, ", "))
+}
+
+// Apply appends the modifier to the prompt.
+func (s Style) Apply(prompt string) string {
+	prompt = strings.TrimRight(strings.TrimSpace(prompt), ",.")
+	return prompt + ", " + s.Modifier
+}
@@ -0,0 +1,141 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package tiles makes images tileable and renders tiled previews.
+package tiles
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+// MakeSeamless blends the image with a copy shifted by half its size, so the
+// opposite edges continue into each other. The shifted copy is used at the
+// edges and faded out towards the center, one axis after the other.
+func MakeSeamless(src image.Image) *image.NRGBA {
+	img := toNRGBA(src)
+	img = blendShifted(img, true)
+	return blendShifted(img, false)
+}
+
+// blendShifted blends img with its copy shifted by half the width or height.
+func blendShifted(img *image.NRGBA, horizontal bool) *image.NRGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	length := h
+	if horizontal {
+		length = w
+	}
+	// width of the blend at each edge
+	band := float64(length) / 4
+	out := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			pos, sx, sy := y, x, (y+h/2)%h
+			if horizontal {
+				pos, sx, sy = x, (x+w/2)%w, y
+			}
+			d := float64(min(pos, length-1-pos))
+			t := min(d/band, 1)
+			// smoothstep, the weight of the original pixel
+			t = t * t * (3 - 2*t)
+			oi := img.PixOffset(b.Min.X+x, b.Min.Y+y)
+			si := img.PixOffset(b.Min.X+sx, b.Min.Y+sy)
+			di := out.PixOffset(x, y)
+			for c := range 4 {
+				out.Pix[di+c] = uint8(float64(img.Pix[oi+c])*t + float64(img.Pix[si+c])*(1-t) + 0.5)
+			}
+		}
+	}
+	return out
+}
+
+// Preview repeats the image n times in both directions.
+func Preview(src image.Image, n int) *image.NRGBA {
+	b := src.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, b.Dx()*n, b.Dy()*n))
+	for ty := range n {
+		for tx := range n {
+			r := image.Rect(tx*b.Dx(), ty*b.Dy(), (tx+1)*b.Dx(), (ty+1)*b.Dy())
+			draw.Draw(out, r, src, b.Min, draw.Src)
+		}
+	}
+	return out
+}
+
+// PreviewPath returns the path of the tiled preview of an image.
+func PreviewPath(imagePath string) string {
+	return strings.TrimSuffix(imagePath, filepath.Ext(imagePath)) + "_tiled.png"
+}
+
+// Process makes the image file seamless and writes a 2x2 tiled preview to
+// PreviewPath. PNG and JPEG files are supported, the file keeps its format.
+func Process(imagePath string) error {
+	b, err := os.ReadFile(imagePath)
+	if err != nil {
+		return fmt.Errorf("failed to read image: %w", err)
+	}
+	img, format, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("failed to decode image: %w", err)
+	}
+	img = MakeSeamless(img)
+	if err := writeImage(imagePath, img, format); err != nil {
+		return err
+	}
+	return writeImage(PreviewPath(imagePath), Preview(img, 2), "png")
+}
+
+func writeImage(path string, img image.Image, format string) error {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95})
+	default:
+		return fmt.Errorf("can't process %s images", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+	if err := providers.WriteFileAtomic(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write image: %w", err)
+	}
+	return nil
+}
+
+func toNRGBA(src image.Image) *image.NRGBA {
+	if img, ok := src.(*image.NRGBA); ok {
+		return img
+	}
+	b := src.Bounds()
+	img := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(img, img.Bounds(), src, b.Min, draw.Src)
+	return img
+}
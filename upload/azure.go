@@ -0,0 +1,59 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package upload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// uploadAzure uploads a block blob to a container URL that carries a SAS
+// token with write permission.
+func uploadAzure(ctx context.Context, t Target, key string, contentType string, b []byte) (string, error) {
+	if t.ContainerURL == "" {
+		return "", errors.New("container_url is not configured")
+	}
+	containerURL, err := url.Parse(t.ContainerURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid container_url: %w", err)
+	}
+	blobURL := *containerURL
+	blobURL.Path = strings.TrimSuffix(containerURL.Path, "/") + "/" + key
+	blobURL.RawPath = ""
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, blobURL.String(), bytes.NewReader(b))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+	req.Header.Set("X-Ms-Version", "2023-11-03")
+	if err := do(http.DefaultClient, req); err != nil {
+		return "", err
+	}
+
+	// don't print the SAS token
+	blobURL.RawQuery = ""
+	return blobURL.String(), nil
+}
@@ -0,0 +1,143 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package upload
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path"
+	"strings"
+)
+
+// uploadGDrive uploads into the configured Google Drive folder.
+func uploadGDrive(ctx context.Context, t Target, key string, contentType string, b []byte) (string, error) {
+	client, save, err := oauthClient(ctx, t)
+	if err != nil {
+		return "", err
+	}
+	defer save()
+
+	metadata := map[string]any{"name": path.Base(key)}
+	if t.FolderID != "" {
+		metadata["parents"] = []string{t.FolderID}
+	}
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return "", err
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/json; charset=UTF-8"}})
+	if err != nil {
+		return "", err
+	}
+	_, _ = part.Write(metadataJSON)
+	part, err = mw.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+	if err != nil {
+		return "", err
+	}
+	_, _ = part.Write(b)
+	if err := mw.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://www.googleapis.com/upload/drive/v3/files?uploadType=multipart&fields=id", &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "multipart/related; boundary="+mw.Boundary())
+	var file struct {
+		ID string `json:"id"`
+	}
+	if err := doJSON(client, req, &file); err != nil {
+		return "", err
+	}
+	return "https://drive.google.com/file/d/" + file.ID + "/view", nil
+}
+
+// uploadDropbox uploads into the configured Dropbox folder. Existing files
+// are not overwritten.
+func uploadDropbox(ctx context.Context, t Target, key string, _ string, b []byte) (string, error) {
+	client, save, err := oauthClient(ctx, t)
+	if err != nil {
+		return "", err
+	}
+	defer save()
+
+	arg, err := json.Marshal(map[string]any{
+		"path":       "/" + strings.Trim(path.Join(t.Path, key), "/"),
+		"mode":       "add",
+		"autorename": true,
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://content.dropboxapi.com/2/files/upload", bytes.NewReader(b))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Dropbox-API-Arg", asciiJSON(arg))
+	var file struct {
+		PathDisplay string `json:"path_display"`
+	}
+	if err := doJSON(client, req, &file); err != nil {
+		return "", err
+	}
+	return "dropbox:" + file.PathDisplay, nil
+}
+
+// asciiJSON escapes all non ASCII characters, HTTP headers must be ASCII.
+func asciiJSON(b []byte) string {
+	var sb strings.Builder
+	for _, r := range string(b) {
+		if r < 0x80 {
+			sb.WriteRune(r)
+		} else if r > 0xFFFF {
+			r -= 0x10000
+			fmt.Fprintf(&sb, `\u%04x\u%04x`, 0xD800+(r>>10), 0xDC00+(r&0x3FF))
+		} else {
+			fmt.Fprintf(&sb, `\u%04x`, r)
+		}
+	}
+	return sb.String()
+}
+
+func doJSON(client *http.Client, req *http.Request, v any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
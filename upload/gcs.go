@@ -0,0 +1,61 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package upload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"cloud.google.com/go/auth/credentials"
+	"cloud.google.com/go/auth/httptransport"
+)
+
+// uploadGCS uploads with the Cloud Storage JSON API using Application Default
+// Credentials.
+func uploadGCS(ctx context.Context, t Target, key string, contentType string, b []byte) (string, error) {
+	if t.Bucket == "" {
+		return "", errors.New("bucket is not configured")
+	}
+	creds, err := credentials.DetectDefault(&credentials.DetectOptions{
+		Scopes: []string{"https://www.googleapis.com/auth/devstorage.read_write"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to detect Google credentials: %w", err)
+	}
+	client, err := httptransport.NewClient(&httptransport.Options{Credentials: creds})
+	if err != nil {
+		return "", fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	u := "https://storage.googleapis.com/upload/storage/v1/b/" + url.PathEscape(t.Bucket) +
+		"/o?uploadType=media&name=" + url.QueryEscape(key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if err := do(client, req); err != nil {
+		return "", err
+	}
+	return "gs://" + t.Bucket + "/" + key, nil
+}
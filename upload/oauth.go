@@ -0,0 +1,168 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package upload
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/bloodmagesoftware/climage/providers"
+	"golang.org/x/oauth2"
+)
+
+// oauthRedirectAddr is the loopback address the OAuth redirect is received
+// on. It has to be registered as redirect URI in the OAuth app.
+const oauthRedirectAddr = "127.0.0.1:53682"
+
+// NeedsLogin reports whether the target uses OAuth and has to be authorized
+// with Login first.
+func NeedsLogin(t Target) bool {
+	return t.Type == "gdrive" || t.Type == "dropbox"
+}
+
+func oauthConfig(t Target) (*oauth2.Config, error) {
+	if t.ClientID == "" {
+		return nil, errors.New("client_id is not configured")
+	}
+	c := &oauth2.Config{
+		ClientID:     t.ClientID,
+		ClientSecret: t.ClientSecret,
+		RedirectURL:  "http://" + oauthRedirectAddr + "/",
+	}
+	switch t.Type {
+	case "gdrive":
+		c.Endpoint = oauth2.Endpoint{
+			AuthURL:  "https://accounts.google.com/o/oauth2/auth",
+			TokenURL: "https://oauth2.googleapis.com/token",
+		}
+		c.Scopes = []string{"https://www.googleapis.com/auth/drive.file"}
+	case "dropbox":
+		c.Endpoint = oauth2.Endpoint{
+			AuthURL:  "https://www.dropbox.com/oauth2/authorize",
+			TokenURL: "https://api.dropboxapi.com/oauth2/token",
+		}
+	default:
+		return nil, fmt.Errorf("upload target type %q does not use OAuth", t.Type)
+	}
+	return c, nil
+}
+
+func tokenSecretName(t Target) string {
+	return "upload:" + t.Type + ":" + t.ClientID
+}
+
+// Login runs the OAuth authorization code flow with PKCE. The user has to
+// open the URL passed to printURL in a browser. The resulting refresh token is
+// stored in the credential store.
+func Login(ctx context.Context, t Target, printURL func(string)) error {
+	c, err := oauthConfig(t)
+	if err != nil {
+		return err
+	}
+
+	stateBytes := make([]byte, 16)
+	_, _ = rand.Read(stateBytes)
+	state := hex.EncodeToString(stateBytes)
+	verifier := oauth2.GenerateVerifier()
+
+	listener, err := net.Listen("tcp", oauthRedirectAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for the OAuth redirect: %w", err)
+	}
+	codes := make(chan string, 1)
+	errs := make(chan error, 1)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("state") != state {
+			http.Error(w, "invalid state", http.StatusBadRequest)
+			return
+		}
+		if e := q.Get("error"); e != "" {
+			http.Error(w, "authorization failed: "+e, http.StatusBadRequest)
+			errs <- fmt.Errorf("authorization failed: %s", e)
+			return
+		}
+		_, _ = fmt.Fprintln(w, "CLImage is authorized, you can close this window.")
+		codes <- q.Get("code")
+	})}
+	go func() { _ = server.Serve(listener) }()
+	defer server.Close()
+
+	opts := []oauth2.AuthCodeOption{oauth2.AccessTypeOffline, oauth2.S256ChallengeOption(verifier)}
+	if t.Type == "dropbox" {
+		opts = append(opts, oauth2.SetAuthURLParam("token_access_type", "offline"))
+	}
+	printURL(c.AuthCodeURL(state, opts...))
+
+	var code string
+	select {
+	case code = <-codes:
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	token, err := c.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	return saveToken(t, token)
+}
+
+func saveToken(t Target, token *oauth2.Token) error {
+	b, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to encode token: %w", err)
+	}
+	return providers.SetSecret(tokenSecretName(t), string(b))
+}
+
+// oauthClient returns an HTTP client authorized for the target. Refreshed
+// tokens are stored again.
+func oauthClient(ctx context.Context, t Target) (*http.Client, func(), error) {
+	c, err := oauthConfig(t)
+	if err != nil {
+		return nil, nil, err
+	}
+	stored, err := providers.GetSecret(tokenSecretName(t))
+	if errors.Is(err, providers.ErrSecretNotFound) {
+		return nil, nil, fmt.Errorf("not logged in to %s, run 'climage upload login' first", t.Type)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load token: %w", err)
+	}
+	var token oauth2.Token
+	if err := json.Unmarshal([]byte(stored), &token); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode token: %w", err)
+	}
+	ts := c.TokenSource(ctx, &token)
+	save := func() {
+		if current, err := ts.Token(); err == nil && current.AccessToken != token.AccessToken {
+			_ = saveToken(t, current)
+		}
+	}
+	return oauth2.NewClient(ctx, ts), save, nil
+}
@@ -0,0 +1,189 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package upload
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// uploadS3 uploads with a single PUT request signed with AWS Signature
+// Version 4. Credentials are read from the standard AWS environment variables.
+func uploadS3(ctx context.Context, t Target, key string, contentType string, b []byte) (string, error) {
+	creds, err := getS3Credentials(t)
+	if err != nil {
+		return "", err
+	}
+	scheme, host, uri := s3Location(t, key, creds.region)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, scheme+"://"+host+uri, bytes.NewReader(b))
+	if err != nil {
+		return "", err
+	}
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	payloadHash := sha256Hex(b)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	req.Header.Set("X-Amz-Date", amzDate)
+	signedHeaders := "content-type;host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "content-type:" + contentType + "\n" +
+		"host:" + host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	if creds.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.sessionToken)
+		signedHeaders += ";x-amz-security-token"
+		canonicalHeaders += "x-amz-security-token:" + creds.sessionToken + "\n"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodPut,
+		uri,
+		"",
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope, signature := creds.sign(now, canonicalRequest)
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", creds.accessKey, scope, signedHeaders, signature))
+
+	if err := do(http.DefaultClient, req); err != nil {
+		return "", err
+	}
+	return "s3://" + t.Bucket + "/" + key, nil
+}
+
+// presignS3 returns a presigned GET URL for the object that is valid for the
+// given duration. S3 allows at most 7 days.
+func presignS3(t Target, key string, expires time.Duration) (string, error) {
+	creds, err := getS3Credentials(t)
+	if err != nil {
+		return "", err
+	}
+	if expires <= 0 || expires > 7*24*time.Hour {
+		expires = 7 * 24 * time.Hour
+	}
+	scheme, host, uri := s3Location(t, key, creds.region)
+	now := time.Now().UTC()
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", creds.accessKey+"/"+creds.scope(now))
+	query.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if creds.sessionToken != "" {
+		query.Set("X-Amz-Security-Token", creds.sessionToken)
+	}
+	// url.Values.Encode escapes spaces as "+", SigV4 requires "%20"
+	canonicalQuery := strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		uri,
+		canonicalQuery,
+		"host:" + host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	_, signature := creds.sign(now, canonicalRequest)
+	return scheme + "://" + host + uri + "?" + canonicalQuery + "&X-Amz-Signature=" + signature, nil
+}
+
+type s3Credentials struct {
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	region       string
+}
+
+func getS3Credentials(t Target) (s3Credentials, error) {
+	creds := s3Credentials{
+		accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		region:       t.Region,
+	}
+	if creds.accessKey == "" || creds.secretKey == "" {
+		return creds, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set")
+	}
+	if t.Bucket == "" {
+		return creds, errors.New("bucket is not configured")
+	}
+	if creds.region == "" {
+		creds.region = os.Getenv("AWS_REGION")
+	}
+	if creds.region == "" {
+		creds.region = "us-east-1"
+	}
+	return creds, nil
+}
+
+func (c s3Credentials) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + c.region + "/s3/aws4_request"
+}
+
+// sign returns the credential scope and the signature of the canonical
+// request.
+func (c s3Credentials) sign(now time.Time, canonicalRequest string) (string, string) {
+	scope := c.scope(now)
+	stringToSign := "AWS4-HMAC-SHA256\n" + now.Format("20060102T150405Z") + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	signingKey := hmacSHA256([]byte("AWS4"+c.secretKey), now.Format("20060102"))
+	signingKey = hmacSHA256(signingKey, c.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	return scope, hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+}
+
+// s3Location returns the scheme, host and escaped path of the object.
+func s3Location(t Target, key string, region string) (string, string, string) {
+	scheme := "https"
+	if strings.HasPrefix(t.Endpoint, "http://") {
+		scheme = "http"
+	}
+	if t.Endpoint != "" {
+		// path style for S3 compatible services
+		host := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSuffix(t.Endpoint, "/"), "https://"), "http://")
+		return scheme, host, "/" + uriEscape(t.Bucket) + "/" + escapePath(key)
+	}
+	return scheme, t.Bucket + ".s3." + region + ".amazonaws.com", "/" + escapePath(key)
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,172 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package upload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/providers"
+)
+
+const defaultShareURL = "https://0x0.st"
+
+// ShareTarget is where temporary share links are created.
+type ShareTarget struct {
+	// Type is "0x0" (default), "http" or "s3".
+	//
+	// "0x0" posts the file as multipart form to a 0x0.st compatible host.
+	// "http" PUTs the file to a self-hosted, transfer.sh compatible endpoint.
+	// Both expect the share URL as response body.
+	// "s3" uploads to the bucket and creates a presigned URL.
+	Type string `json:"type,omitempty"`
+	// URL is the host or endpoint for "0x0" and "http".
+	URL string `json:"url,omitempty"`
+	// Bucket, Prefix, Region and Endpoint are used by "s3", see Target.
+	Bucket   string `json:"bucket,omitempty"`
+	Prefix   string `json:"prefix,omitempty"`
+	Region   string `json:"region,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+	// Expires is the default lifetime of a share link. Zero uses the
+	// default of the host.
+	Expires providers.Duration `json:"expires,omitzero"`
+}
+
+// Share uploads the file to the share target and returns a shareable URL. An
+// expiry of zero uses the target's default.
+func Share(ctx context.Context, t ShareTarget, filePath string, expires time.Duration) (string, error) {
+	if expires <= 0 {
+		expires = time.Duration(t.Expires)
+	}
+	var (
+		shareURL string
+		err      error
+	)
+	switch t.Type {
+	case "", "0x0":
+		shareURL, err = share0x0(ctx, t, filePath, expires)
+	case "http":
+		shareURL, err = shareHTTP(ctx, t, filePath, expires)
+	case "s3":
+		shareURL, err = shareS3(ctx, t, filePath, expires)
+	default:
+		return "", fmt.Errorf("unknown share target type %q", t.Type)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to share %q: %w", filePath, err)
+	}
+	return shareURL, nil
+}
+
+func share0x0(ctx context.Context, t ShareTarget, filePath string, expires time.Duration) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", filepath.Base(filePath))
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return "", err
+	}
+	if expires > 0 {
+		// 0x0.st expects the lifetime in hours
+		_ = mw.WriteField("expires", strconv.Itoa(int(math.Ceil(expires.Hours()))))
+	}
+	if err := mw.Close(); err != nil {
+		return "", err
+	}
+
+	host := t.URL
+	if host == "" {
+		host = defaultShareURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, host, &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	// 0x0.st blocks the default Go user agent
+	req.Header.Set("User-Agent", "climage")
+	return doText(req)
+}
+
+func shareHTTP(ctx context.Context, t ShareTarget, filePath string, expires time.Duration) (string, error) {
+	if t.URL == "" {
+		return "", fmt.Errorf("url is not configured")
+	}
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, strings.TrimSuffix(t.URL, "/")+"/"+uriEscape(filepath.Base(filePath)), bytes.NewReader(b))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", http.DetectContentType(b))
+	if expires > 0 {
+		req.Header.Set("Max-Days", strconv.Itoa(int(math.Ceil(expires.Hours()/24))))
+	}
+	return doText(req)
+}
+
+func shareS3(ctx context.Context, t ShareTarget, filePath string, expires time.Duration) (string, error) {
+	target := Target{Type: "s3", Bucket: t.Bucket, Prefix: t.Prefix, Region: t.Region, Endpoint: t.Endpoint}
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	key := objectKey(t.Prefix, filepath.Base(filePath))
+	if _, err := uploadS3(ctx, target, key, http.DetectContentType(b), b); err != nil {
+		return "", err
+	}
+	return presignS3(target, key, expires)
+}
+
+// doText sends the request and returns the trimmed response body.
+func doText(req *http.Request) (string, error) {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return strings.TrimSpace(string(body)), nil
+}
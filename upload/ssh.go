@@ -0,0 +1,128 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package upload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// The SSH based targets use the system's sftp and rsync binaries, so the
+// user's SSH config, agent and known hosts apply.
+
+func remote(t Target) (string, error) {
+	if t.Host == "" {
+		return "", errors.New("host is not configured")
+	}
+	if t.User != "" {
+		return t.User + "@" + t.Host, nil
+	}
+	return t.Host, nil
+}
+
+func remotePath(t Target, key string) string {
+	if t.Path == "" {
+		return key
+	}
+	return path.Join(t.Path, key)
+}
+
+func uploadSFTP(ctx context.Context, t Target, key string, filePath string) (string, error) {
+	host, err := remote(t)
+	if err != nil {
+		return "", err
+	}
+	args := []string{"-b", "-"}
+	if t.Port != 0 {
+		args = append(args, "-P", strconv.Itoa(t.Port))
+	}
+	if t.Key != "" {
+		args = append(args, "-i", t.Key)
+	}
+	args = append(args, host)
+
+	dst := remotePath(t, key)
+	var batch strings.Builder
+	// create the missing directories, "-" ignores errors for existing ones
+	dir := path.Dir(dst)
+	var parts []string
+	for _, p := range strings.Split(dir, "/") {
+		parts = append(parts, p)
+		if p == "" || p == "." {
+			continue
+		}
+		fmt.Fprintf(&batch, "-mkdir %q\n", strings.Join(parts, "/"))
+	}
+	fmt.Fprintf(&batch, "put %q %q\n", filePath, dst)
+
+	c := exec.CommandContext(ctx, "sftp", args...)
+	c.Stdin = strings.NewReader(batch.String())
+	if err := runSSHCommand(c); err != nil {
+		return "", err
+	}
+	return "sftp://" + host + "/" + strings.TrimPrefix(dst, "/"), nil
+}
+
+func uploadRsync(ctx context.Context, t Target, key string, filePath string) (string, error) {
+	host, err := remote(t)
+	if err != nil {
+		return "", err
+	}
+	ssh := []string{"ssh"}
+	if t.Port != 0 {
+		ssh = append(ssh, "-p", strconv.Itoa(t.Port))
+	}
+	if t.Key != "" {
+		ssh = append(ssh, "-i", t.Key)
+	}
+	dst := remotePath(t, key)
+	c := exec.CommandContext(ctx, "rsync",
+		"--mkpath", "--partial",
+		"-e", shellJoin(ssh),
+		filePath, host+":"+dst)
+	if err := runSSHCommand(c); err != nil {
+		return "", err
+	}
+	return host + ":" + dst, nil
+}
+
+// shellJoin quotes each argument so a key path with spaces or quotes
+// survives the word splitting rsync applies to its -e command.
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = "'" + strings.ReplaceAll(a, "'", `'"'"'`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+func runSSHCommand(c *exec.Cmd) error {
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", c.Args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
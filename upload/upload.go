@@ -0,0 +1,143 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package upload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Target is a remote location generated images are uploaded to.
+type Target struct {
+	// Type is one of "s3", "gcs", "azure", "sftp", "rsync", "gdrive" or
+	// "dropbox".
+	Type   string `json:"type"`
+	Bucket string `json:"bucket,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	// Region and Endpoint are used by S3. Endpoint allows S3 compatible
+	// services like MinIO or R2.
+	Region   string `json:"region,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+	// ContainerURL is the Azure Blob container URL including a SAS token.
+	ContainerURL string `json:"container_url,omitempty"`
+	// Host, Port, User, Path and Key are used by SFTP and rsync. Key is the
+	// path to the SSH private key, the SSH agent and config are used if empty.
+	// Path is the destination folder for Dropbox too.
+	Host string `json:"host,omitempty"`
+	Port int    `json:"port,omitempty"`
+	User string `json:"user,omitempty"`
+	Path string `json:"path,omitempty"`
+	Key  string `json:"key,omitempty"`
+	// ClientID and ClientSecret identify the OAuth app used for Google Drive
+	// and Dropbox. FolderID is the ID of the Google Drive folder.
+	ClientID     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	FolderID     string `json:"folder_id,omitempty"`
+	// PublicURL is the base URL under which uploaded objects are publicly
+	// reachable. If set, the public URL of every upload is printed.
+	PublicURL string `json:"public_url,omitempty"`
+}
+
+// Upload uploads the file to the target and returns the public URL of the
+// object if the target has one, or its location otherwise.
+func Upload(ctx context.Context, t Target, filePath string) (string, error) {
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %q: %w", filePath, err)
+	}
+	key := objectKey(t.Prefix, filepath.Base(filePath))
+	contentType := http.DetectContentType(b)
+
+	var location string
+	switch t.Type {
+	case "s3":
+		location, err = uploadS3(ctx, t, key, contentType, b)
+	case "gcs":
+		location, err = uploadGCS(ctx, t, key, contentType, b)
+	case "azure":
+		location, err = uploadAzure(ctx, t, key, contentType, b)
+	case "sftp":
+		location, err = uploadSFTP(ctx, t, key, filePath)
+	case "rsync":
+		location, err = uploadRsync(ctx, t, key, filePath)
+	case "gdrive":
+		location, err = uploadGDrive(ctx, t, key, contentType, b)
+	case "dropbox":
+		location, err = uploadDropbox(ctx, t, key, contentType, b)
+	default:
+		return "", fmt.Errorf("unknown upload target type %q", t.Type)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to upload to %s: %w", t.Type, err)
+	}
+
+	if t.PublicURL != "" {
+		return strings.TrimSuffix(t.PublicURL, "/") + "/" + escapePath(key), nil
+	}
+	return location, nil
+}
+
+func objectKey(prefix string, name string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return name
+	}
+	return path.Join(prefix, name)
+}
+
+func escapePath(key string) string {
+	parts := strings.Split(key, "/")
+	for i, p := range parts {
+		parts[i] = uriEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
+
+// uriEscape escapes everything except the unreserved characters of RFC 3986.
+func uriEscape(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+func do(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package wallpaper sets images as the desktop background.
+package wallpaper
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// ErrUnsupported is returned on desktops the wallpaper can't be set on.
+var ErrUnsupported = errors.New("setting the wallpaper is not supported on this desktop")
+
+// Set sets the image as the desktop background.
+func Set(imagePath string) error {
+	abs, err := filepath.Abs(imagePath)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	if err := set(abs); err != nil {
+		return fmt.Errorf("failed to set wallpaper: %w", err)
+	}
+	return nil
+}
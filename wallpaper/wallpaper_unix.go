@@ -0,0 +1,59 @@
+//go:build linux || freebsd || openbsd || netbsd || dragonfly
+
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package wallpaper
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+func set(imagePath string) error {
+	if os.Getenv("SWAYSOCK") != "" {
+		return run("swaymsg", "output", "*", "bg", imagePath, "fill")
+	}
+	desktops := strings.Split(strings.ToLower(os.Getenv("XDG_CURRENT_DESKTOP")), ":")
+	for _, desktop := range desktops {
+		switch desktop {
+		case "gnome", "unity", "budgie", "pantheon":
+			uri := (&url.URL{Scheme: "file", Path: imagePath}).String()
+			if err := run("gsettings", "set", "org.gnome.desktop.background", "picture-uri", uri); err != nil {
+				return err
+			}
+			// used with the dark style since GNOME 42, older versions don't
+			// have the key
+			_ = run("gsettings", "set", "org.gnome.desktop.background", "picture-uri-dark", uri)
+			return nil
+		case "kde":
+			return run("plasma-apply-wallpaperimage", imagePath)
+		}
+	}
+	return ErrUnsupported
+}
+
+func run(name string, args ...string) error {
+	if out, err := exec.Command(name, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package wallpaper
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const (
+	spiSetDeskWallpaper = 0x0014
+	spifUpdateIniFile   = 0x01
+	spifSendChange      = 0x02
+)
+
+func set(imagePath string) error {
+	p, err := syscall.UTF16PtrFromString(imagePath)
+	if err != nil {
+		return err
+	}
+	systemParametersInfo := syscall.NewLazyDLL("user32.dll").NewProc("SystemParametersInfoW")
+	r, _, err := systemParametersInfo.Call(spiSetDeskWallpaper, 0, uintptr(unsafe.Pointer(p)), spifUpdateIniFile|spifSendChange)
+	if r == 0 {
+		return err
+	}
+	return nil
+}
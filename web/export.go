@@ -0,0 +1,140 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package web
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/bloodmagesoftware/climage/history"
+)
+
+// staticLinks are the relative links of an exported gallery.
+type staticLinks struct{}
+
+func (staticLinks) entry(id int) string {
+	return fmt.Sprintf("entry-%d.html", id)
+}
+
+func (staticLinks) file(id, n int, path string) (string, string, string) {
+	url := fmt.Sprintf("images/%d-%s", id, filepath.Base(path))
+	return url, fmt.Sprintf("thumbs/%d-%d.jpg", id, n), url
+}
+
+// Export writes a self-contained static gallery of the history entries that
+// include returns true for to dir: an index.html with the title, a page per
+// entry and copies of the images and their thumbnails. Entries without any
+// remaining file and costs are left out. It returns the number of exported
+// entries.
+func Export(dir string, title string, include func(history.Entry) bool) (int, error) {
+	entries, err := history.Read()
+	if err != nil {
+		return 0, err
+	}
+	for _, sub := range []string{"images", "thumbs"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return 0, fmt.Errorf("failed to create gallery dir: %w", err)
+		}
+	}
+
+	var views []entryView
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		e.Cost = 0
+		v := newEntryView(staticLinks{}, e.ID, e)
+		if !slices.ContainsFunc(v.Files, func(f fileView) bool { return !f.Missing }) || !include(e) {
+			continue
+		}
+		for n, f := range v.Files {
+			if f.Missing {
+				continue
+			}
+			if err := copyFile(e.Images[n], filepath.Join(dir, f.URL)); err != nil {
+				return 0, err
+			}
+			if f.Video {
+				continue
+			}
+			thumb, err := thumbnail(e.Images[n])
+			if err != nil {
+				log.Printf("warning: using %s as its thumbnail: %v", f.Name, err)
+				v.Files[n].ThumbURL = f.URL
+				continue
+			}
+			if err := copyFile(thumb, filepath.Join(dir, f.ThumbURL)); err != nil {
+				return 0, err
+			}
+		}
+		if err := writePage(filepath.Join(dir, v.URL), "entry", map[string]any{
+			"Entry": v,
+			"Home":  "index.html",
+		}); err != nil {
+			return 0, err
+		}
+		views = append(views, v)
+	}
+	if err := writePage(filepath.Join(dir, "index.html"), "index", map[string]any{
+		"Title":   title,
+		"Entries": views,
+		"Page":    1,
+		"Pages":   1,
+	}); err != nil {
+		return 0, err
+	}
+	return len(views), nil
+}
+
+func writePage(path string, name string, data any) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create page: %w", err)
+	}
+	if err := templates.ExecuteTemplate(f, name, data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to render %s: %w", filepath.Base(path), err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write page: %w", err)
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	return nil
+}
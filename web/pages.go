@@ -0,0 +1,102 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package web
+
+// pages are the HTML templates of the gallery. They need no external assets,
+// so the gallery works without network access.
+const pages = `
+{{define "head"}}<!doctype html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.}} - CLImage</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 0 auto; padding: 1rem; max-width: 80rem; background: #111; color: #ddd; }
+a { color: #9cf; }
+header { display: flex; justify-content: space-between; align-items: baseline; }
+.grid { display: grid; grid-template-columns: repeat(auto-fill, minmax(200px, 1fr)); gap: 1rem; }
+.card { background: #1c1c1c; border-radius: 6px; overflow: hidden; text-decoration: none; color: inherit; }
+.card img, .placeholder { display: block; width: 100%; aspect-ratio: 1; object-fit: cover; background: #222; }
+.placeholder { display: flex; align-items: center; justify-content: center; color: #888; }
+.card p { margin: .5rem; font-size: .85rem; overflow: hidden; display: -webkit-box; -webkit-line-clamp: 3; -webkit-box-orient: vertical; }
+.card small, dt { color: #888; }
+.media img, .media video { max-width: 100%; max-height: 80vh; display: block; margin-bottom: .5rem; }
+dl { display: grid; grid-template-columns: max-content 1fr; gap: .25rem 1rem; }
+dd { margin: 0; white-space: pre-wrap; }
+.error { color: #f88; }
+</style>
+</head>
+<body>
+{{end}}
+
+{{define "index"}}{{template "head" .Title}}
+<header><h1>{{.Title}}</h1>{{if gt .Pages 1}}<span>page {{.Page}} of {{.Pages}}</span>{{end}}</header>
+{{if not .Entries}}<p>No generations yet.</p>{{end}}
+<div class="grid">
+{{range .Entries}}
+<a class="card" href="{{.URL}}">
+{{with .Files}}{{with index . 0}}{{if or .Video .Missing}}<div class="placeholder">{{if .Missing}}deleted{{else}}video{{end}}</div>{{else}}<img src="{{.ThumbURL}}" alt="" loading="lazy">{{end}}{{end}}{{else}}<div class="placeholder">filtered</div>{{end}}
+<p>{{.Prompt}}</p>
+<p><small>{{time .Time}}, {{.Model}}{{if gt (len .Files) 1}}, {{len .Files}} images{{end}}</small></p>
+</a>
+{{end}}
+</div>
+<p>
+{{with .Newer}}<a href="{{.}}">newer</a>{{end}}
+{{with .Older}}<a href="{{.}}">older</a>{{end}}
+</p>
+</body>
+</html>
+{{end}}
+
+{{define "entry"}}{{template "head" .Entry.Prompt}}{{$e := .Entry}}
+<header><h1>Generation</h1><a href="{{.Home}}">gallery</a></header>
+{{with .Error}}<p class="error">Re-run failed: {{.}}</p>{{end}}
+<div class="media">
+{{range $e.Files}}
+{{if .Missing}}<p>{{.Name}} was deleted</p>
+{{else}}{{if .Video}}<video src="{{.URL}}" controls></video>{{else}}<img src="{{.URL}}" alt="">{{end}}
+<p><a href="{{.DownloadURL}}" download>download {{.Name}}</a></p>{{end}}
+{{end}}
+</div>
+<dl>
+<dt>Prompt</dt><dd>{{$e.Prompt}}</dd>
+<dt>Model</dt><dd>{{$e.Model}}</dd>
+<dt>Time</dt><dd>{{time $e.Time}}</dd>
+<dt>History ID</dt><dd>{{$e.ID}}</dd>
+{{if $e.Cost}}<dt>Cost</dt><dd>${{printf "%.2f" $e.Cost}}</dd>{{end}}
+{{if $e.Filtered}}<dt>Filtered</dt><dd>{{$e.Filtered}} images</dd>{{end}}
+{{with $e.Meta}}
+{{with .Seed}}<dt>Seed</dt><dd>{{.}}</dd>{{end}}
+{{with .Size}}<dt>Size</dt><dd>{{.}}</dd>{{end}}
+{{with .Watermark}}<dt>Watermark</dt><dd>{{.}}</dd>{{end}}
+{{range settings .Settings}}<dt>{{index . 0}}</dt><dd>{{index . 1}}</dd>{{end}}
+{{end}}
+</dl>
+{{if .Rerun}}
+<form method="post" action="{{$e.URL}}/rerun">
+<label><input type="checkbox" name="new_seed" checked> new seed</label>
+<button type="submit">Re-run</button>
+</form>
+{{end}}
+</body>
+</html>
+{{end}}
+`
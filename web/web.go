@@ -0,0 +1,360 @@
+/*
+CLImage is a AI image generation CLI tool.
+Copyright (C) 2025  Mayer & Ott GbR
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as
+published by the Free Software Foundation, either version 3 of the
+License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public Licen
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package web serves a small browser gallery of the generation history, e.g.
+// to review generations on a remote machine through an SSH port forward.
+package web
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"log"
+	"maps"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bloodmagesoftware/climage/history"
+	"github.com/bloodmagesoftware/climage/providers"
+	"github.com/bloodmagesoftware/climage/sidecar"
+	"github.com/bloodmagesoftware/climage/sizes"
+)
+
+const (
+	// pageSize is the number of history entries per gallery page.
+	pageSize = 48
+	// thumbnailWidth is the maximum width of the gallery thumbnails.
+	thumbnailWidth = 320
+)
+
+// Server serves the gallery. Only files recorded in the history are served.
+type Server struct {
+	// Rerun generates the entry again, reusing the recorded seed unless
+	// newSeed is set. Re-running is disabled if nil.
+	Rerun func(ctx context.Context, e history.Entry, newSeed bool) error
+	// Addr is the address the server listens on. Requests are only answered
+	// for localhost and the host of Addr, so a site that rebinds its DNS name
+	// to 127.0.0.1 can't read the gallery or start re-runs.
+	Addr string
+}
+
+// Handler returns the HTTP handler of the gallery.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /entry/{id}", s.handleEntry)
+	mux.HandleFunc("POST /entry/{id}/rerun", s.handleRerun)
+	mux.HandleFunc("GET /image/{id}/{n}", s.handleImage)
+	mux.HandleFunc("GET /thumb/{id}/{n}", s.handleThumbnail)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.allowedHost(r.Host) {
+			http.Error(w, "unknown host", http.StatusForbidden)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// allowedHost reports if the Host header names the server.
+func (s *Server) allowedHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = strings.Trim(hostport, "[]")
+	}
+	switch strings.ToLower(host) {
+	case "localhost", "127.0.0.1", "::1":
+		return true
+	}
+	addrHost, _, err := net.SplitHostPort(s.Addr)
+	return err == nil && addrHost != "" && strings.EqualFold(host, strings.Trim(addrHost, "[]"))
+}
+
+// entryView is a history entry prepared for the templates.
+type entryView struct {
+	ID  int
+	URL string
+	history.Entry
+	Files []fileView
+	// Meta is the sidecar of the first image, if there is one.
+	Meta *sidecar.Metadata
+}
+
+type fileView struct {
+	Name        string
+	Video       bool
+	Missing     bool
+	URL         string
+	ThumbURL    string
+	DownloadURL string
+}
+
+// linker builds the links of the pages, which differ between the server and
+// a static export.
+type linker interface {
+	entry(id int) string
+	file(id, n int, path string) (url, thumb, download string)
+}
+
+// serverLinks are the links of the server's routes.
+type serverLinks struct{}
+
+func (serverLinks) entry(id int) string {
+	return fmt.Sprintf("/entry/%d", id)
+}
+
+func (serverLinks) file(id, n int, path string) (string, string, string) {
+	url := fmt.Sprintf("/image/%d/%d", id, n)
+	return url, fmt.Sprintf("/thumb/%d/%d", id, n), url + "?download"
+}
+
+func newEntryView(l linker, id int, e history.Entry) entryView {
+	v := entryView{ID: id, URL: l.entry(id), Entry: e}
+	for n, f := range e.Images {
+		_, err := os.Stat(f)
+		fv := fileView{
+			Name:    filepath.Base(f),
+			Video:   isVideo(f),
+			Missing: err != nil,
+		}
+		fv.URL, fv.ThumbURL, fv.DownloadURL = l.file(id, n, f)
+		v.Files = append(v.Files, fv)
+	}
+	if len(e.Images) > 0 {
+		if m, err := sidecar.Read(sidecar.Path(e.Images[0])); err == nil {
+			v.Meta = &m
+		}
+	}
+	return v
+}
+
+func isVideo(path string) bool {
+	return strings.HasPrefix(mime.TypeByExtension(filepath.Ext(path)), "video/")
+}
+
+// entry looks up the history entry of the id in the request path, see
+// history.Get.
+func entry(w http.ResponseWriter, r *http.Request) (int, history.Entry, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return 0, history.Entry{}, false
+	}
+	e, err := history.Get(id)
+	if errors.Is(err, history.ErrNotFound) {
+		http.NotFound(w, r)
+		return 0, history.Entry{}, false
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return 0, history.Entry{}, false
+	}
+	return id, e, true
+}
+
+// file looks up the image path of the id and number in the request path.
+func file(w http.ResponseWriter, r *http.Request) (string, bool) {
+	_, e, ok := entry(w, r)
+	if !ok {
+		return "", false
+	}
+	n, err := strconv.Atoi(r.PathValue("n"))
+	if err != nil || n < 0 || n >= len(e.Images) {
+		http.NotFound(w, r)
+		return "", false
+	}
+	return e.Images[n], true
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	entries, err := history.Read()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	page = max(page, 1)
+	pages := max((len(entries)+pageSize-1)/pageSize, 1)
+	page = min(page, pages)
+
+	// newest first
+	var views []entryView
+	for i := len(entries) - 1 - (page-1)*pageSize; i >= 0 && len(views) < pageSize; i-- {
+		views = append(views, newEntryView(serverLinks{}, entries[i].ID, entries[i]))
+	}
+	data := map[string]any{
+		"Title":   "Gallery",
+		"Entries": views,
+		"Page":    page,
+		"Pages":   pages,
+	}
+	if page > 1 {
+		data["Newer"] = fmt.Sprintf("/?page=%d", page-1)
+	}
+	if page < pages {
+		data["Older"] = fmt.Sprintf("/?page=%d", page+1)
+	}
+	render(w, "index", data)
+}
+
+func (s *Server) handleEntry(w http.ResponseWriter, r *http.Request) {
+	id, e, ok := entry(w, r)
+	if !ok {
+		return
+	}
+	render(w, "entry", map[string]any{
+		"Entry": newEntryView(serverLinks{}, id, e),
+		"Home":  "/",
+		"Rerun": s.Rerun != nil,
+		"Error": r.URL.Query().Get("error"),
+	})
+}
+
+func (s *Server) handleRerun(w http.ResponseWriter, r *http.Request) {
+	if s.Rerun == nil {
+		http.NotFound(w, r)
+		return
+	}
+	// Other sites must not start paid generations from the user's browser.
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if u, err := url.Parse(origin); err != nil || u.Host != r.Host {
+			http.Error(w, "cross-origin request", http.StatusForbidden)
+			return
+		}
+	}
+	id, e, ok := entry(w, r)
+	if !ok {
+		return
+	}
+	if err := s.Rerun(r.Context(), e, r.FormValue("new_seed") != ""); err != nil {
+		http.Redirect(w, r, fmt.Sprintf("/entry/%d?error=%s", id, url.QueryEscape(err.Error())), http.StatusSeeOther)
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func (s *Server) handleImage(w http.ResponseWriter, r *http.Request) {
+	path, ok := file(w, r)
+	if !ok {
+		return
+	}
+	if _, err := os.Stat(path); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	if r.URL.Query().Has("download") {
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filepath.Base(path)}))
+	}
+	http.ServeFile(w, r, path)
+}
+
+func (s *Server) handleThumbnail(w http.ResponseWriter, r *http.Request) {
+	path, ok := file(w, r)
+	if !ok {
+		return
+	}
+	thumb, err := thumbnail(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Cache-Control", "max-age=86400")
+	http.ServeFile(w, r, thumb)
+}
+
+// thumbnail returns the path of a cached JPEG thumbnail of the image, which
+// is created on the first request.
+func thumbnail(path string) (string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user cache dir: %w", err)
+	}
+	// a changed image gets a new thumbnail
+	key := sha256.Sum256(fmt.Appendf(nil, "%s\x00%d\x00%d", path, fi.Size(), fi.ModTime().UnixNano()))
+	thumb := filepath.Join(cacheDir, "climage", "thumbnails", hex.EncodeToString(key[:])+".jpg")
+	if _, err := os.Stat(thumb); err == nil {
+		return thumb, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	src, _, err := image.Decode(f)
+	_ = f.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to decode image: %w", err)
+	}
+	b := src.Bounds()
+	size := sizes.Size{Width: min(b.Dx(), thumbnailWidth)}
+	size.Height = max(b.Dy()*size.Width/max(b.Dx(), 1), 1)
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, sizes.Resize(src, size), &jpeg.Options{Quality: 85}); err != nil {
+		return "", fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(thumb), 0700); err != nil {
+		return "", fmt.Errorf("failed to create thumbnail dir: %w", err)
+	}
+	// concurrent requests for the same thumbnail write their own temporary
+	// file
+	if err := providers.WriteFileAtomic(thumb, buf.Bytes(), 0600); err != nil {
+		return "", fmt.Errorf("failed to write thumbnail: %w", err)
+	}
+	return thumb, nil
+}
+
+func render(w http.ResponseWriter, name string, data any) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name, data); err != nil {
+		log.Printf("warning: failed to render %s: %v", name, err)
+		http.Error(w, "failed to render page", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = buf.WriteTo(w)
+}
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"time": func(t time.Time) string { return t.Local().Format(time.DateTime) },
+	"settings": func(m map[string]string) [][2]string {
+		var s [][2]string
+		for _, k := range slices.Sorted(maps.Keys(m)) {
+			s = append(s, [2]string{k, m[k]})
+		}
+		return s
+	},
+}).Parse(pages))